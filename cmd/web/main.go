@@ -25,6 +25,7 @@ func main() {
 	validate := config.NewValidator(viperConfig)
 	app := config.NewFiber(viperConfig)
 	producer := config.NewKafkaProducer(viperConfig, log)
+	notifier := config.NewNotifier(viperConfig, log)
 
 	config.Bootstrap(&config.BootstrapConfig{
 		DB:       db,
@@ -33,6 +34,7 @@ func main() {
 		Validate: validate,
 		Config:   viperConfig,
 		Producer: producer,
+		Notifier: notifier,
 	})
 
 	webPort := viperConfig.GetInt("web.port")
@@ -6,26 +6,40 @@ import (
 	"os/signal"
 	"sync"
 	"syscall"
+	"time"
 
 	"go-clean-template/internal/config"
 	"go-clean-template/internal/delivery/messaging"
+	"go-clean-template/internal/delivery/scheduler"
+	gatewayMessaging "go-clean-template/internal/gateway/messaging"
+	"go-clean-template/internal/repository"
+	"go-clean-template/internal/usecase"
 
+	"github.com/go-playground/validator/v10"
 	"github.com/spf13/viper"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
 func main() {
 	viperConfig := config.NewViper()
 	logger := config.NewLogger(viperConfig)
 	logger.Info("Starting worker service")
+	db := config.NewDatabase(viperConfig, logger)
+	validate := config.NewValidator(viperConfig)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	wg := &sync.WaitGroup{}
 
-	wg.Add(3)
+	wg.Add(8)
 	go RunUserConsumer(logger, viperConfig, ctx, wg)
 	go RunContactConsumer(logger, viperConfig, ctx, wg)
 	go RunAddressConsumer(logger, viperConfig, ctx, wg)
+	go RunSessionCleanup(logger, viperConfig, db, validate, ctx, wg)
+	go RunLoginAttemptCleanup(logger, viperConfig, db, ctx, wg)
+	go RunAccountDeletion(logger, viperConfig, db, validate, ctx, wg)
+	go RunDataExport(logger, viperConfig, db, validate, ctx, wg)
+	go RunTrashPurge(logger, viperConfig, db, validate, ctx, wg)
 
 	terminateSignals := make(chan os.Signal, 1)
 	signal.Notify(terminateSignals, syscall.SIGINT, syscall.SIGKILL, syscall.SIGTERM)
@@ -41,9 +55,81 @@ func main() {
 	}
 
 	wg.Wait()
+
+	// Close SQL DB
+	sqlDB, err := db.DB()
+	if err != nil {
+		logger.Errorf("Failed to get SQL DB: %v", err)
+	} else if err := sqlDB.Close(); err != nil {
+		logger.Errorf("Failed to close SQL DB: %v", err)
+	}
+
 	logger.Info("Worker exited")
 }
 
+func RunSessionCleanup(logger *zap.SugaredLogger, viperConfig *viper.Viper, db *gorm.DB, validate *validator.Validate, ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+	logger.Info("setup session cleanup job")
+	sessionRepository := repository.NewSessionRepository(logger)
+	sessionUseCase := usecase.NewSessionUseCase(db, logger, validate, sessionRepository, config.NewSessionPolicy(viperConfig))
+	sessionCleanupJob := scheduler.NewSessionCleanupJob(sessionUseCase, logger)
+	interval := config.NewJobInterval(viperConfig, logger, "session.cleanup_interval")
+	scheduler.RunJob(ctx, "session-cleanup", interval, logger, sessionCleanupJob.Run)
+}
+
+func RunLoginAttemptCleanup(logger *zap.SugaredLogger, viperConfig *viper.Viper, db *gorm.DB, ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+	logger.Info("setup login attempt cleanup job")
+	loginAttemptRepository := repository.NewLoginAttemptRepository(logger)
+	loginProtectionUseCase := usecase.NewLoginProtectionUseCase(db, logger, loginAttemptRepository,
+		config.NewLoginThrottle(viperConfig, "login_protection.account"), config.NewLoginThrottle(viperConfig, "login_protection.ip"))
+	loginAttemptCleanupJob := scheduler.NewLoginAttemptCleanupJob(loginProtectionUseCase, logger)
+	interval := config.NewJobInterval(viperConfig, logger, "login_protection.cleanup_interval")
+	scheduler.RunJob(ctx, "login-attempt-cleanup", interval, logger, loginAttemptCleanupJob.Run)
+}
+
+func RunAccountDeletion(logger *zap.SugaredLogger, viperConfig *viper.Viper, db *gorm.DB, validate *validator.Validate, ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+	logger.Info("setup account deletion job")
+	var userProducer *gatewayMessaging.UserProducer
+	if producer := config.NewKafkaProducer(viperConfig, logger); producer != nil {
+		userProducer = gatewayMessaging.NewUserProducer(producer, logger)
+	}
+	accountDeletionUseCase := usecase.NewAccountDeletionUseCase(db, logger, validate, repository.NewUserRepository(logger),
+		repository.NewSessionRepository(logger), repository.NewContactRepository(logger), repository.NewAddressRepository(logger),
+		config.NewPasswordHasher(viperConfig, logger), userProducer, repository.NewAuditLogRepository(logger),
+		repository.NewApiKeyRepository(logger), nil, nil,
+		time.Second*time.Duration(viperConfig.GetInt("account_deletion.grace_period")),
+		time.Second*time.Duration(viperConfig.GetInt("account_deletion.reauth_window")))
+	accountDeletionJob := scheduler.NewAccountDeletionJob(accountDeletionUseCase, logger)
+	interval := config.NewJobInterval(viperConfig, logger, "account_deletion.purge_interval")
+	scheduler.RunJob(ctx, "account-deletion", interval, logger, accountDeletionJob.Run)
+}
+
+func RunDataExport(logger *zap.SugaredLogger, viperConfig *viper.Viper, db *gorm.DB, validate *validator.Validate, ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+	logger.Info("setup data export job")
+	dataExportUseCase := usecase.NewDataExportUseCase(db, logger, validate, repository.NewUserRepository(logger),
+		repository.NewSessionRepository(logger), repository.NewContactRepository(logger), repository.NewAuditLogRepository(logger),
+		repository.NewDataExportRepository(logger), config.NewNotifier(viperConfig, logger),
+		time.Second*time.Duration(viperConfig.GetInt("data_export.ttl")), viperConfig.GetString("data_export.url"))
+	dataExportJob := scheduler.NewDataExportJob(dataExportUseCase, logger)
+	interval := config.NewJobInterval(viperConfig, logger, "data_export.interval")
+	scheduler.RunJob(ctx, "data-export", interval, logger, dataExportJob.Run)
+}
+
+func RunTrashPurge(logger *zap.SugaredLogger, viperConfig *viper.Viper, db *gorm.DB, validate *validator.Validate, ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+	logger.Info("setup trash purge job")
+	contactUseCase := usecase.NewContactUseCase(db, logger, validate, repository.NewContactRepository(logger),
+		repository.NewAddressRepository(logger), repository.NewUserRepository(logger), nil, nil,
+		viperConfig.GetBool("email_verification.block_contact_create"),
+		time.Second*time.Duration(viperConfig.GetInt("trash.retention")))
+	trashPurgeJob := scheduler.NewTrashPurgeJob(contactUseCase, logger)
+	interval := config.NewJobInterval(viperConfig, logger, "trash.purge_interval")
+	scheduler.RunJob(ctx, "trash-purge", interval, logger, trashPurgeJob.Run)
+}
+
 func RunAddressConsumer(logger *zap.SugaredLogger, viperConfig *viper.Viper, ctx context.Context, wg *sync.WaitGroup) {
 	defer wg.Done()
 	logger.Info("setup address consumer")
@@ -0,0 +1,21 @@
+alter table users
+    add column token varchar(100) null;
+
+create table refresh_tokens
+(
+    id         varchar(100) not null,
+    family_id  varchar(100) not null,
+    user_id    varchar(100) not null,
+    token_hash varchar(64)  not null,
+    expires_at bigint       not null,
+    rotated_at bigint       not null default 0,
+    revoked_at bigint       not null default 0,
+    created_at bigint       not null,
+    primary key (id),
+    CONSTRAINT uk_refresh_tokens_token_hash UNIQUE (token_hash),
+    CONSTRAINT fk_refresh_tokens_user_id FOREIGN KEY (user_id) REFERENCES users (id)
+);
+
+create index idx_refresh_tokens_family_id on refresh_tokens (family_id);
+
+drop table sessions;
@@ -0,0 +1,41 @@
+create table sessions
+(
+    id           varchar(100) not null,
+    user_id      varchar(100) not null,
+    token_hash   varchar(64)  not null,
+    user_agent   varchar(255) not null default '',
+    ip_address   varchar(45)  not null default '',
+    created_at   bigint       not null,
+    last_seen_at bigint       not null,
+    expires_at   bigint       not null,
+    revoked_at   bigint       not null default 0,
+    primary key (id),
+    CONSTRAINT uk_sessions_token_hash UNIQUE (token_hash),
+    CONSTRAINT fk_sessions_user_id FOREIGN KEY (user_id) REFERENCES users (id)
+);
+
+create index idx_sessions_user_id on sessions (user_id);
+
+-- the current refresh token of every live family becomes a session
+insert into sessions (id, user_id, token_hash, created_at, last_seen_at, expires_at)
+select family_id, user_id, token_hash, created_at, created_at, expires_at
+from refresh_tokens
+where rotated_at = 0
+  and revoked_at = 0;
+
+-- opaque login tokens become sessions too, so nobody is logged out
+insert into sessions (id, user_id, token_hash, created_at, last_seen_at, expires_at)
+select gen_random_uuid()::varchar,
+       id,
+       encode(sha256(token::bytea), 'hex'),
+       updated_at,
+       updated_at,
+       (extract(epoch from now() + interval '30 days') * 1000)::bigint
+from users
+where token is not null
+  and token <> '';
+
+drop table refresh_tokens;
+
+alter table users
+    drop column token;
@@ -0,0 +1 @@
+drop table password_resets;
\ No newline at end of file
@@ -0,0 +1,12 @@
+create table password_resets
+(
+    id         varchar(100) not null,
+    user_id    varchar(100) not null,
+    token_hash varchar(64)  not null,
+    expires_at bigint       not null,
+    used_at    bigint       not null default 0,
+    created_at bigint       not null,
+    primary key (id),
+    CONSTRAINT uk_password_resets_token_hash UNIQUE (token_hash),
+    CONSTRAINT fk_password_resets_user_id FOREIGN KEY (user_id) REFERENCES users (id)
+);
@@ -0,0 +1,7 @@
+drop table email_verifications;
+
+drop index uk_users_email;
+
+alter table users
+    drop column email_verified_at,
+    drop column email;
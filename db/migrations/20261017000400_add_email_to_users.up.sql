@@ -0,0 +1,19 @@
+alter table users
+    add column email             varchar(200) not null default '',
+    add column email_verified_at bigint       not null default 0;
+
+create unique index uk_users_email on users (lower(email)) where email <> '';
+
+create table email_verifications
+(
+    id         varchar(100) not null,
+    user_id    varchar(100) not null,
+    email      varchar(200) not null,
+    token_hash varchar(64)  not null,
+    expires_at bigint       not null,
+    used_at    bigint       not null default 0,
+    created_at bigint       not null,
+    primary key (id),
+    CONSTRAINT uk_email_verifications_token_hash UNIQUE (token_hash),
+    CONSTRAINT fk_email_verifications_user_id FOREIGN KEY (user_id) REFERENCES users (id)
+);
@@ -0,0 +1,40 @@
+create table user_totps
+(
+    user_id        varchar(100) not null,
+    secret         varchar(255) not null,
+    confirmed_at   bigint       not null default 0,
+    last_used_step bigint       not null default 0,
+    created_at     bigint       not null,
+    updated_at     bigint       not null,
+    primary key (user_id),
+    CONSTRAINT fk_user_totps_user_id FOREIGN KEY (user_id) REFERENCES users (id)
+);
+
+create table recovery_codes
+(
+    id         varchar(100) not null,
+    user_id    varchar(100) not null,
+    code_hash  varchar(64)  not null,
+    used_at    bigint       not null default 0,
+    created_at bigint       not null,
+    primary key (id),
+    CONSTRAINT fk_recovery_codes_user_id FOREIGN KEY (user_id) REFERENCES users (id)
+);
+
+create index idx_recovery_codes_user_id on recovery_codes (user_id);
+
+create table mfa_challenges
+(
+    id         varchar(100) not null,
+    user_id    varchar(100) not null,
+    token_hash varchar(64)  not null,
+    user_agent varchar(255) not null default '',
+    ip_address varchar(45)  not null default '',
+    attempts   int          not null default 0,
+    expires_at bigint       not null,
+    used_at    bigint       not null default 0,
+    created_at bigint       not null,
+    primary key (id),
+    CONSTRAINT uk_mfa_challenges_token_hash UNIQUE (token_hash),
+    CONSTRAINT fk_mfa_challenges_user_id FOREIGN KEY (user_id) REFERENCES users (id)
+);
@@ -0,0 +1,8 @@
+create table login_attempts
+(
+    key             varchar(200) not null,
+    failures        int          not null default 0,
+    last_failure_at bigint       not null default 0,
+    locked_until    bigint       not null default 0,
+    primary key (key)
+);
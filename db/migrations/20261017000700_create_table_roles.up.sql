@@ -0,0 +1,49 @@
+create table roles
+(
+    id          varchar(100) not null,
+    name        varchar(100) not null,
+    description varchar(255) not null default '',
+    created_at  bigint       not null,
+    updated_at  bigint       not null,
+    primary key (id)
+);
+
+create table permissions
+(
+    id          varchar(100) not null,
+    description varchar(255) not null default '',
+    primary key (id)
+);
+
+create table role_permissions
+(
+    role_id       varchar(100) not null,
+    permission_id varchar(100) not null,
+    primary key (role_id, permission_id),
+    CONSTRAINT fk_role_permissions_role_id FOREIGN KEY (role_id) REFERENCES roles (id),
+    CONSTRAINT fk_role_permissions_permission_id FOREIGN KEY (permission_id) REFERENCES permissions (id)
+);
+
+create table user_roles
+(
+    user_id    varchar(100) not null,
+    role_id    varchar(100) not null,
+    created_at bigint       not null,
+    primary key (user_id, role_id),
+    CONSTRAINT fk_user_roles_user_id FOREIGN KEY (user_id) REFERENCES users (id),
+    CONSTRAINT fk_user_roles_role_id FOREIGN KEY (role_id) REFERENCES roles (id)
+);
+
+insert into permissions (id, description)
+values ('users:read', 'List and view user accounts'),
+       ('users:write', 'Disable, enable and log out user accounts'),
+       ('roles:read', 'List roles and their permissions'),
+       ('roles:write', 'Grant and revoke roles');
+
+insert into roles (id, name, description, created_at, updated_at)
+values ('admin', 'Administrator', 'Full access to administration routes',
+        (extract(epoch from now()) * 1000)::bigint, (extract(epoch from now()) * 1000)::bigint);
+
+insert into role_permissions (role_id, permission_id)
+select 'admin', id
+from permissions;
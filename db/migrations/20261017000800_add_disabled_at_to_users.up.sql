@@ -0,0 +1,2 @@
+alter table users
+    add column disabled_at bigint not null default 0;
@@ -0,0 +1,17 @@
+create table api_keys
+(
+    id           varchar(100) not null,
+    user_id      varchar(100) not null,
+    name         varchar(100) not null,
+    prefix       varchar(20)  not null,
+    key_hash     varchar(64)  not null,
+    scopes       varchar(255) not null,
+    expires_at   bigint       not null default 0,
+    last_used_at bigint       not null default 0,
+    created_at   bigint       not null,
+    primary key (id),
+    CONSTRAINT uk_api_keys_key_hash UNIQUE (key_hash),
+    CONSTRAINT fk_api_keys_user_id FOREIGN KEY (user_id) REFERENCES users (id)
+);
+
+create index idx_api_keys_user_id on api_keys (user_id);
@@ -0,0 +1,30 @@
+create table user_identities
+(
+    id            varchar(100) not null,
+    user_id       varchar(100) not null,
+    issuer        varchar(255) not null,
+    subject       varchar(255) not null,
+    email         varchar(200) not null default '',
+    created_at    bigint       not null,
+    last_login_at bigint       not null default 0,
+    primary key (id),
+    CONSTRAINT uk_user_identities_issuer_subject UNIQUE (issuer, subject),
+    CONSTRAINT fk_user_identities_user_id FOREIGN KEY (user_id) REFERENCES users (id)
+);
+
+create index idx_user_identities_user_id on user_identities (user_id);
+
+create table oidc_states
+(
+    id            varchar(100) not null,
+    state_hash    varchar(64)  not null,
+    nonce         varchar(100) not null,
+    code_verifier varchar(100) not null,
+    expires_at    bigint       not null,
+    used_at       bigint       not null default 0,
+    created_at    bigint       not null,
+    primary key (id),
+    CONSTRAINT uk_oidc_states_state_hash UNIQUE (state_hash)
+);
+
+create index idx_oidc_states_expires_at on oidc_states (expires_at);
@@ -0,0 +1,41 @@
+alter table users
+    add column delete_after bigint not null default 0;
+
+create index idx_users_delete_after on users (delete_after);
+
+-- credentials and grants go with the account, contacts and addresses are deleted by the application
+alter table sessions
+    drop constraint fk_sessions_user_id,
+    add constraint fk_sessions_user_id foreign key (user_id) references users (id) on delete cascade;
+
+alter table password_resets
+    drop constraint fk_password_resets_user_id,
+    add constraint fk_password_resets_user_id foreign key (user_id) references users (id) on delete cascade;
+
+alter table email_verifications
+    drop constraint fk_email_verifications_user_id,
+    add constraint fk_email_verifications_user_id foreign key (user_id) references users (id) on delete cascade;
+
+alter table user_totps
+    drop constraint fk_user_totps_user_id,
+    add constraint fk_user_totps_user_id foreign key (user_id) references users (id) on delete cascade;
+
+alter table recovery_codes
+    drop constraint fk_recovery_codes_user_id,
+    add constraint fk_recovery_codes_user_id foreign key (user_id) references users (id) on delete cascade;
+
+alter table mfa_challenges
+    drop constraint fk_mfa_challenges_user_id,
+    add constraint fk_mfa_challenges_user_id foreign key (user_id) references users (id) on delete cascade;
+
+alter table user_roles
+    drop constraint fk_user_roles_user_id,
+    add constraint fk_user_roles_user_id foreign key (user_id) references users (id) on delete cascade;
+
+alter table api_keys
+    drop constraint fk_api_keys_user_id,
+    add constraint fk_api_keys_user_id foreign key (user_id) references users (id) on delete cascade;
+
+alter table user_identities
+    drop constraint fk_user_identities_user_id,
+    add constraint fk_user_identities_user_id foreign key (user_id) references users (id) on delete cascade;
@@ -0,0 +1,14 @@
+create table audit_logs
+(
+    id         varchar(100) not null,
+    user_id    varchar(100) not null,
+    actor_id   varchar(100) not null,
+    action     varchar(100) not null,
+    user_agent varchar(255) not null default '',
+    ip_address varchar(45)  not null default '',
+    created_at bigint       not null,
+    primary key (id),
+    CONSTRAINT fk_audit_logs_user_id FOREIGN KEY (user_id) REFERENCES users (id) on delete cascade
+);
+
+create index idx_audit_logs_user_id_created_at on audit_logs (user_id, created_at);
@@ -0,0 +1 @@
+drop table data_exports;
@@ -0,0 +1,15 @@
+create table data_exports
+(
+    id           varchar(100) not null,
+    user_id      varchar(100) not null,
+    status       varchar(20)  not null,
+    archive      bytea,
+    created_at   bigint       not null,
+    completed_at bigint       not null default 0,
+    expires_at   bigint       not null default 0,
+    primary key (id),
+    CONSTRAINT fk_data_exports_user_id FOREIGN KEY (user_id) REFERENCES users (id) on delete cascade
+);
+
+create index idx_data_exports_user_id on data_exports (user_id);
+create index idx_data_exports_status on data_exports (status);
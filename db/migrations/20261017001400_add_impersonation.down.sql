@@ -0,0 +1,8 @@
+delete from role_permissions
+where permission_id = 'users:impersonate';
+
+delete from permissions
+where id = 'users:impersonate';
+
+alter table audit_logs
+    drop column detail;
@@ -0,0 +1,8 @@
+alter table audit_logs
+    add column detail varchar(255) not null default '';
+
+insert into permissions (id, description)
+values ('users:impersonate', 'Act as another user to see what they see');
+
+insert into role_permissions (role_id, permission_id)
+values ('admin', 'users:impersonate');
@@ -0,0 +1,16 @@
+create table magic_links
+(
+    id          varchar(100) not null,
+    user_id     varchar(100) not null,
+    email       varchar(200) not null,
+    token_hash  varchar(64)  not null,
+    device_hash varchar(64)  not null,
+    expires_at  bigint       not null,
+    used_at     bigint       not null default 0,
+    created_at  bigint       not null,
+    primary key (id),
+    CONSTRAINT uk_magic_links_token_hash UNIQUE (token_hash),
+    CONSTRAINT fk_magic_links_user_id FOREIGN KEY (user_id) REFERENCES users (id) on delete cascade
+);
+
+create index idx_magic_links_user_id on magic_links (user_id);
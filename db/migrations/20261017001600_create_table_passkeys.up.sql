@@ -0,0 +1,30 @@
+create table passkeys
+(
+    id            varchar(100) not null,
+    user_id       varchar(100) not null,
+    name          varchar(100) not null default '',
+    credential_id varchar(1400) not null,
+    public_key    bytea        not null,
+    sign_count    bigint       not null default 0,
+    aaguid        varchar(36)  not null default '',
+    last_used_at  bigint       not null default 0,
+    created_at    bigint       not null,
+    primary key (id),
+    CONSTRAINT uk_passkeys_credential_id UNIQUE (credential_id),
+    CONSTRAINT fk_passkeys_user_id FOREIGN KEY (user_id) REFERENCES users (id) on delete cascade
+);
+
+create index idx_passkeys_user_id on passkeys (user_id);
+
+create table webauthn_challenges
+(
+    id             varchar(100) not null,
+    challenge_hash varchar(64)  not null,
+    ceremony       varchar(20)  not null,
+    user_id        varchar(100) not null default '',
+    expires_at     bigint       not null,
+    used_at        bigint       not null default 0,
+    created_at     bigint       not null,
+    primary key (id),
+    CONSTRAINT uk_webauthn_challenges_challenge_hash UNIQUE (challenge_hash)
+);
@@ -0,0 +1,8 @@
+drop index if exists idx_contacts_search_phonetic;
+drop index if exists idx_contacts_search_name;
+drop index if exists idx_contacts_search_vector;
+
+alter table contacts
+    drop column search_phonetic,
+    drop column search_name,
+    drop column search_vector;
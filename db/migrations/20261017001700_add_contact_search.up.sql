@@ -0,0 +1,21 @@
+create extension if not exists pg_trgm;
+create extension if not exists fuzzystrmatch;
+
+-- names are not words of any language, so nothing is stemmed or dropped as a stop word
+alter table contacts
+    add column search_vector tsvector generated always as (
+        setweight(to_tsvector('simple'::regconfig, coalesce(first_name, '')), 'A') ||
+        setweight(to_tsvector('simple'::regconfig, coalesce(last_name, '')), 'A') ||
+        setweight(to_tsvector('simple'::regconfig, coalesce(email, '')), 'B') ||
+        setweight(to_tsvector('simple'::regconfig, coalesce(phone, '')), 'C')
+        ) stored,
+    add column search_name text generated always as (
+        lower(coalesce(first_name, '') || ' ' || coalesce(last_name, ''))
+        ) stored,
+    add column search_phonetic text[] generated always as (
+        array_remove(array [dmetaphone(coalesce(first_name, '')), dmetaphone(coalesce(last_name, ''))], '')
+        ) stored;
+
+create index idx_contacts_search_vector on contacts using gin (search_vector);
+create index idx_contacts_search_name on contacts using gin (search_name gin_trgm_ops);
+create index idx_contacts_search_phonetic on contacts using gin (search_phonetic);
@@ -0,0 +1 @@
+drop index if exists idx_contacts_user_id_created_at;
@@ -0,0 +1,2 @@
+-- serves the default sort of the contact list, for pages by cursor as well as by number
+create index idx_contacts_user_id_created_at on contacts (user_id, created_at, id);
@@ -0,0 +1,9 @@
+drop index idx_addresses_deleted_at;
+drop index idx_contacts_deleted_at;
+drop index idx_contacts_user_id_deleted_at;
+
+alter table addresses
+    drop column deleted_at;
+
+alter table contacts
+    drop column deleted_at;
@@ -0,0 +1,10 @@
+alter table contacts
+    add column deleted_at bigint not null default 0;
+
+alter table addresses
+    add column deleted_at bigint not null default 0;
+
+-- only trashed rows are looked up by deleted_at, by the trash listing and the purge
+create index idx_contacts_user_id_deleted_at on contacts (user_id, deleted_at) where deleted_at <> 0;
+create index idx_contacts_deleted_at on contacts (deleted_at) where deleted_at <> 0;
+create index idx_addresses_deleted_at on addresses (deleted_at) where deleted_at <> 0;
@@ -0,0 +1,4 @@
+drop index idx_sessions_previous_token_hash;
+
+alter table sessions
+    drop column previous_token_hash;
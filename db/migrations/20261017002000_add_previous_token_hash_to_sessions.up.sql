@@ -0,0 +1,5 @@
+alter table sessions
+    add column previous_token_hash varchar(64) not null default '';
+
+-- a replayed refresh token is only recognized as reuse when it is the one its session was rotated away from
+create index idx_sessions_previous_token_hash on sessions (previous_token_hash) where previous_token_hash <> '';
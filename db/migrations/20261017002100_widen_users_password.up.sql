@@ -0,0 +1,3 @@
+-- argon2id hashes grow with their parameters and salt, varchar(100) only fits the defaults
+alter table users
+    alter column password type varchar(255);
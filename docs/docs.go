@@ -15,14 +15,14 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/api/contacts": {
+        "/api/admin/roles": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "List contacts",
+                "description": "List all roles and their permissions, requires roles:read",
                 "consumes": [
                     "application/json"
                 ],
@@ -30,10 +30,56 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Contact API"
+                    "Admin API"
                 ],
-                "summary": "List contacts",
+                "summary": "List roles",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.WebResponse-array_go-clean-template_internal_model_RoleResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/admin/users": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Search users, requires users:read",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin API"
+                ],
+                "summary": "List users",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
                         "description": "Name",
@@ -47,9 +93,9 @@ const docTemplate = `{
                         "in": "query"
                     },
                     {
-                        "type": "string",
-                        "description": "Phone",
-                        "name": "phone",
+                        "type": "boolean",
+                        "description": "Only disabled or only enabled users",
+                        "name": "disabled",
                         "in": "query"
                     },
                     {
@@ -69,7 +115,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/go-clean-template_internal_model.WebResponse-array_go-clean-template_internal_model_ContactResponse"
+                            "$ref": "#/definitions/go-clean-template_internal_model.PageResponse-go-clean-template_internal_model_UserResponse"
                         }
                     },
                     "400": {
@@ -78,6 +124,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -85,14 +137,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/api/admin/users/{userId}": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Create new contact",
+                "description": "Get a user by id, requires users:read",
                 "consumes": [
                     "application/json"
                 ],
@@ -100,29 +154,33 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Contact API"
+                    "Admin API"
                 ],
-                "summary": "Create new contact",
+                "summary": "Get user",
                 "parameters": [
                     {
-                        "description": "Create Contact Request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/go-clean-template_internal_model.CreateContactRequest"
-                        }
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/go-clean-template_internal_model.WebResponse-go-clean-template_internal_model_ContactResponse"
+                            "$ref": "#/definitions/go-clean-template_internal_model.WebResponse-go-clean-template_internal_model_UserResponse"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
                         }
@@ -136,14 +194,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/contacts/{contactId}": {
-            "get": {
+        "/api/admin/users/{userId}/_disable": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get contact",
+                "description": "Block a user from logging in and revoke their sessions, requires users:write",
                 "consumes": [
                     "application/json"
                 ],
@@ -151,14 +209,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Contact API"
+                    "Admin API"
                 ],
-                "summary": "Get contact",
+                "summary": "Disable user",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Contact ID",
-                        "name": "contactId",
+                        "description": "User ID",
+                        "name": "userId",
                         "in": "path",
                         "required": true
                     }
@@ -167,11 +225,23 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/go-clean-template_internal_model.WebResponse-go-clean-template_internal_model_ContactResponse"
+                            "$ref": "#/definitions/go-clean-template_internal_model.WebResponse-go-clean-template_internal_model_UserResponse"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
                         }
@@ -183,14 +253,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/api/admin/users/{userId}/_enable": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Update contact",
+                "description": "Allow a disabled user to log in again, requires users:write",
                 "consumes": [
                     "application/json"
                 ],
@@ -198,36 +270,33 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Contact API"
+                    "Admin API"
                 ],
-                "summary": "Update contact",
+                "summary": "Enable user",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Contact ID",
-                        "name": "contactId",
+                        "description": "User ID",
+                        "name": "userId",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Update Contact Request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/go-clean-template_internal_model.UpdateContactRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/go-clean-template_internal_model.WebResponse-go-clean-template_internal_model_ContactResponse"
+                            "$ref": "#/definitions/go-clean-template_internal_model.WebResponse-go-clean-template_internal_model_UserResponse"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
                         }
@@ -239,14 +308,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/api/admin/users/{userId}/_impersonate": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Delete contact",
+                "description": "Issue a short-lived access token to act as the user, requires users:impersonate. The token can only read the account and every request made with it is audited",
                 "consumes": [
                     "application/json"
                 ],
@@ -254,14 +325,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Contact API"
+                    "Admin API"
                 ],
-                "summary": "Delete contact",
+                "summary": "Impersonate user",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Contact ID",
-                        "name": "contactId",
+                        "description": "User ID",
+                        "name": "userId",
                         "in": "path",
                         "required": true
                     }
@@ -270,11 +341,23 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/go-clean-template_internal_model.WebResponse-bool"
+                            "$ref": "#/definitions/go-clean-template_internal_model.WebResponse-go-clean-template_internal_model_UserResponse"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
                         }
@@ -288,14 +371,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/contacts/{contactId}/addresses": {
-            "get": {
+        "/api/admin/users/{userId}/_logout": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "List addresses",
+                "description": "Revoke every session of a user, requires users:write",
                 "consumes": [
                     "application/json"
                 ],
@@ -303,14 +386,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Address API"
+                    "Admin API"
                 ],
-                "summary": "List addresses",
+                "summary": "Force logout user",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Contact ID",
-                        "name": "contactId",
+                        "description": "User ID",
+                        "name": "userId",
                         "in": "path",
                         "required": true
                     }
@@ -319,11 +402,17 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/go-clean-template_internal_model.WebResponse-array_go-clean-template_internal_model_AddressResponse"
+                            "$ref": "#/definitions/go-clean-template_internal_model.WebResponse-bool"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
                         }
@@ -335,14 +424,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/api/admin/users/{userId}/_reset-password": {
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Create new address",
+                "description": "Send a password reset link to the user's email, requires users:write",
                 "consumes": [
                     "application/json"
                 ],
@@ -350,36 +441,39 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Address API"
+                    "Admin API"
                 ],
-                "summary": "Create new address",
+                "summary": "Reset user password",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Contact ID",
-                        "name": "contactId",
+                        "description": "User ID",
+                        "name": "userId",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Create Address Request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/go-clean-template_internal_model.CreateAddressRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/go-clean-template_internal_model.WebResponse-go-clean-template_internal_model_AddressResponse"
+                            "$ref": "#/definitions/go-clean-template_internal_model.WebResponse-bool"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
                         "schema": {
                             "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
                         }
@@ -393,14 +487,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/contacts/{contactId}/addresses/{addressId}": {
+        "/api/admin/users/{userId}/roles": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get address",
+                "description": "List the roles granted to a user, requires roles:read",
                 "consumes": [
                     "application/json"
                 ],
@@ -408,21 +502,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Address API"
+                    "Admin API"
                 ],
-                "summary": "Get address",
+                "summary": "List user roles",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Contact ID",
-                        "name": "contactId",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Address ID",
-                        "name": "addressId",
+                        "description": "User ID",
+                        "name": "userId",
                         "in": "path",
                         "required": true
                     }
@@ -431,11 +518,17 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/go-clean-template_internal_model.WebResponse-go-clean-template_internal_model_AddressResponse"
+                            "$ref": "#/definitions/go-clean-template_internal_model.WebResponse-array_go-clean-template_internal_model_RoleResponse"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
                         }
@@ -448,13 +541,13 @@ const docTemplate = `{
                     }
                 }
             },
-            "put": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Update address",
+                "description": "Grant a role to a user, requires roles:write and every permission of the role",
                 "consumes": [
                     "application/json"
                 ],
@@ -462,31 +555,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Address API"
+                    "Admin API"
                 ],
-                "summary": "Update address",
+                "summary": "Grant role",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Contact ID",
-                        "name": "contactId",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Address ID",
-                        "name": "addressId",
+                        "description": "User ID",
+                        "name": "userId",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Update Address Request",
+                        "description": "Grant Role Request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/go-clean-template_internal_model.UpdateAddressRequest"
+                            "$ref": "#/definitions/go-clean-template_internal_model.GrantRoleRequest"
                         }
                     }
                 ],
@@ -494,7 +580,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/go-clean-template_internal_model.WebResponse-go-clean-template_internal_model_AddressResponse"
+                            "$ref": "#/definitions/go-clean-template_internal_model.WebResponse-bool"
                         }
                     },
                     "400": {
@@ -503,6 +589,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -510,14 +608,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/api/admin/users/{userId}/roles/{roleId}": {
             "delete": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Delete address",
+                "description": "Take a role away from a user, requires roles:write and every permission of the role",
                 "consumes": [
                     "application/json"
                 ],
@@ -525,21 +625,21 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Address API"
+                    "Admin API"
                 ],
-                "summary": "Delete address",
+                "summary": "Revoke role",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Contact ID",
-                        "name": "contactId",
+                        "description": "User ID",
+                        "name": "userId",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Address ID",
-                        "name": "addressId",
+                        "description": "Role ID",
+                        "name": "roleId",
                         "in": "path",
                         "required": true
                     }
@@ -551,8 +651,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/go-clean-template_internal_model.WebResponse-bool"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
                         }
@@ -566,27 +672,138 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/users": {
-            "post": {
-                "description": "Register new user",
-                "consumes": [
-                    "application/json"
-                ],
+        "/api/contacts": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "List contacts, with q the contacts matching it best come first",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "User API"
+                    "Contact API"
                 ],
-                "summary": "Register new user",
+                "summary": "List contacts",
                 "parameters": [
                     {
-                        "description": "Register User Request",
+                        "type": "string",
+                        "description": "Search first and last name, email and phone, tolerating typos",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Email",
+                        "name": "email",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Phone",
+                        "name": "phone",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Size",
+                        "name": "size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated keys to sort by, - sorts descending, e.g. -updated_at,last_name",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated fields to return, e.g. first_name,email",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from paging.next, returns the page after it in place of page",
+                        "name": "after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from paging.previous, returns the page before it in place of page",
+                        "name": "before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Count total_item and total_page, by default only without a cursor",
+                        "name": "total",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.WebResponse-array_go-clean-template_internal_model_ContactResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Create new contact",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Contact API"
+                ],
+                "summary": "Create new contact",
+                "parameters": [
+                    {
+                        "description": "Create Contact Request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/go-clean-template_internal_model.RegisterUserRequest"
+                            "$ref": "#/definitions/go-clean-template_internal_model.CreateContactRequest"
                         }
                     }
                 ],
@@ -594,7 +811,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/go-clean-template_internal_model.WebResponse-go-clean-template_internal_model_UserResponse"
+                            "$ref": "#/definitions/go-clean-template_internal_model.WebResponse-go-clean-template_internal_model_ContactResponse"
                         }
                     },
                     "400": {
@@ -610,14 +827,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/api/contacts/_trash": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Logout user",
+                "description": "List deleted contacts that can still be restored, the last deleted first",
                 "consumes": [
                     "application/json"
                 ],
@@ -625,14 +844,28 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User API"
+                    "Contact API"
+                ],
+                "summary": "List trashed contacts",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Size",
+                        "name": "size",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Logout user",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/go-clean-template_internal_model.WebResponse-bool"
+                            "$ref": "#/definitions/go-clean-template_internal_model.PageResponse-go-clean-template_internal_model_ContactResponse"
                         }
                     },
                     "400": {
@@ -650,14 +883,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/users/_current": {
+        "/api/contacts/{contactId}": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get current user",
+                "description": "Get contact",
                 "consumes": [
                     "application/json"
                 ],
@@ -665,14 +898,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User API"
+                    "Contact API"
+                ],
+                "summary": "Get contact",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Contact ID",
+                        "name": "contactId",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get current user",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/go-clean-template_internal_model.WebResponse-go-clean-template_internal_model_UserResponse"
+                            "$ref": "#/definitions/go-clean-template_internal_model.WebResponse-go-clean-template_internal_model_ContactResponse"
                         }
                     },
                     "400": {
@@ -689,13 +931,13 @@ const docTemplate = `{
                     }
                 }
             },
-            "patch": {
+            "put": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Update user",
+                "description": "Update contact",
                 "consumes": [
                     "application/json"
                 ],
@@ -703,17 +945,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User API"
+                    "Contact API"
                 ],
-                "summary": "Update user",
+                "summary": "Update contact",
                 "parameters": [
                     {
-                        "description": "Update User Request",
+                        "type": "string",
+                        "description": "Contact ID",
+                        "name": "contactId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update Contact Request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/go-clean-template_internal_model.UpdateUserRequest"
+                            "$ref": "#/definitions/go-clean-template_internal_model.UpdateContactRequest"
                         }
                     }
                 ],
@@ -721,7 +970,54 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/go-clean-template_internal_model.WebResponse-go-clean-template_internal_model_UserResponse"
+                            "$ref": "#/definitions/go-clean-template_internal_model.WebResponse-go-clean-template_internal_model_ContactResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete contact",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Contact API"
+                ],
+                "summary": "Delete contact",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Contact ID",
+                        "name": "contactId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.WebResponse-bool"
                         }
                     },
                     "400": {
@@ -739,9 +1035,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/users/_login": {
+        "/api/contacts/{contactId}/_restore": {
             "post": {
-                "description": "Login user",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Restore a deleted contact from the trash",
                 "consumes": [
                     "application/json"
                 ],
@@ -749,25 +1050,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User API"
+                    "Contact API"
                 ],
-                "summary": "Login user",
+                "summary": "Restore contact",
                 "parameters": [
                     {
-                        "description": "Login User Request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/go-clean-template_internal_model.LoginUserRequest"
-                        }
+                        "type": "string",
+                        "description": "Contact ID",
+                        "name": "contactId",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/go-clean-template_internal_model.WebResponse-go-clean-template_internal_model_UserResponse"
+                            "$ref": "#/definitions/go-clean-template_internal_model.WebResponse-go-clean-template_internal_model_ContactResponse"
                         }
                     },
                     "400": {
@@ -776,6 +1075,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -784,163 +1089,3126 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "go-clean-template_internal_model.AddressResponse": {
+        },
+        "/api/contacts/{contactId}/addresses": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "List addresses",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Address API"
+                ],
+                "summary": "List addresses",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Contact ID",
+                        "name": "contactId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.WebResponse-array_go-clean-template_internal_model_AddressResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Create new address",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Address API"
+                ],
+                "summary": "Create new address",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Contact ID",
+                        "name": "contactId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Create Address Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.CreateAddressRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.WebResponse-go-clean-template_internal_model_AddressResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/contacts/{contactId}/addresses/_trash": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "List deleted addresses of the contact that can still be restored, the last deleted first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Address API"
+                ],
+                "summary": "List trashed addresses",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Contact ID",
+                        "name": "contactId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.WebResponse-array_go-clean-template_internal_model_AddressResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/contacts/{contactId}/addresses/{addressId}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get address",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Address API"
+                ],
+                "summary": "Get address",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Contact ID",
+                        "name": "contactId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Address ID",
+                        "name": "addressId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.WebResponse-go-clean-template_internal_model_AddressResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update address",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Address API"
+                ],
+                "summary": "Update address",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Contact ID",
+                        "name": "contactId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Address ID",
+                        "name": "addressId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update Address Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.UpdateAddressRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.WebResponse-go-clean-template_internal_model_AddressResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete address",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Address API"
+                ],
+                "summary": "Delete address",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Contact ID",
+                        "name": "contactId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Address ID",
+                        "name": "addressId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.WebResponse-bool"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/contacts/{contactId}/addresses/{addressId}/_restore": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Restore a deleted address from the trash",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Address API"
+                ],
+                "summary": "Restore address",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Contact ID",
+                        "name": "contactId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Address ID",
+                        "name": "addressId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.WebResponse-go-clean-template_internal_model_AddressResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users": {
+            "post": {
+                "description": "Register new user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User API"
+                ],
+                "summary": "Register new user",
+                "parameters": [
+                    {
+                        "description": "Register User Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.RegisterUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.WebResponse-go-clean-template_internal_model_UserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Logout user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User API"
+                ],
+                "summary": "Logout user",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.WebResponse-bool"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/_current": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get current user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User API"
+                ],
+                "summary": "Get current user",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.WebResponse-go-clean-template_internal_model_UserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Schedule the current user's account, contacts and addresses for deletion, confirmed with the password, a two-factor code or a recent login",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User API"
+                ],
+                "summary": "Delete account",
+                "parameters": [
+                    {
+                        "description": "Delete User Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.DeleteUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.WebResponse-go-clean-template_internal_model_UserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "423": {
+                        "description": "Locked",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User API"
+                ],
+                "summary": "Update user",
+                "parameters": [
+                    {
+                        "description": "Update User Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.UpdateUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.WebResponse-go-clean-template_internal_model_UserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/_current/_cancel-deletion": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Keep the current user's account that is scheduled for deletion",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User API"
+                ],
+                "summary": "Cancel account deletion",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.WebResponse-go-clean-template_internal_model_UserResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/_current/_change-password": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Set a new password, confirmed with the current one, every other session is logged out",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User API"
+                ],
+                "summary": "Change password",
+                "parameters": [
+                    {
+                        "description": "Change Password Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ChangePasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.WebResponse-bool"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "423": {
+                        "description": "Locked",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/_current/api-keys": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "List the API keys of the current user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "API Key API"
+                ],
+                "summary": "List API keys",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.WebResponse-array_go-clean-template_internal_model_ApiKeyResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Create an API key for machine clients, the key is only returned once",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "API Key API"
+                ],
+                "summary": "Create API key",
+                "parameters": [
+                    {
+                        "description": "Create API Key Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.CreateApiKeyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.WebResponse-go-clean-template_internal_model_ApiKeyResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/_current/api-keys/{apiKeyId}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete an API key of the current user, clients using it are rejected right away",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "API Key API"
+                ],
+                "summary": "Delete API key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "API Key ID",
+                        "name": "apiKeyId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.WebResponse-bool"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/_current/export": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Queue an export of everything stored about the current user, the user is notified when it is ready",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Data Export API"
+                ],
+                "summary": "Request data export",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.WebResponse-go-clean-template_internal_model_DataExportResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/_current/exports/{exportId}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get the status of a data export",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Data Export API"
+                ],
+                "summary": "Get data export",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Data Export ID",
+                        "name": "exportId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.WebResponse-go-clean-template_internal_model_DataExportResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/_current/exports/{exportId}/download": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Download the zip archive of a data export with the profile, contacts and addresses (JSON and vCard), sessions and audit log",
+                "produces": [
+                    "application/zip"
+                ],
+                "tags": [
+                    "Data Export API"
+                ],
+                "summary": "Download data export",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Data Export ID",
+                        "name": "exportId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "410": {
+                        "description": "Gone",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/_current/mfa/recovery-codes": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Replace all recovery codes, requires a TOTP or recovery code",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Two-Factor API"
+                ],
+                "summary": "Regenerate recovery codes",
+                "parameters": [
+                    {
+                        "description": "Regenerate Recovery Codes Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.RegenerateRecoveryCodesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.WebResponse-go-clean-template_internal_model_RecoveryCodesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/_current/mfa/totp": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Generate a TOTP secret and otpauth URI for the current user, it is enabled once confirmed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Two-Factor API"
+                ],
+                "summary": "Enroll TOTP",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.WebResponse-go-clean-template_internal_model_TotpEnrollmentResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Turn off two-factor authentication, requires a TOTP or recovery code",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Two-Factor API"
+                ],
+                "summary": "Disable TOTP",
+                "parameters": [
+                    {
+                        "description": "Disable Totp Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.DisableTotpRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.WebResponse-bool"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/_current/mfa/totp/_confirm": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Enable TOTP with a first code from the authenticator and return the recovery codes",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Two-Factor API"
+                ],
+                "summary": "Confirm TOTP",
+                "parameters": [
+                    {
+                        "description": "Confirm Totp Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ConfirmTotpRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.WebResponse-go-clean-template_internal_model_RecoveryCodesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/_current/passkeys": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "List the passkeys of the current user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Passkey API"
+                ],
+                "summary": "List passkeys",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.WebResponse-array_go-clean-template_internal_model_PasskeyResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Store the credential created by navigator.credentials.create as a passkey of the current user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Passkey API"
+                ],
+                "summary": "Register passkey",
+                "parameters": [
+                    {
+                        "description": "Finish Passkey Registration Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.FinishPasskeyRegistrationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.WebResponse-go-clean-template_internal_model_PasskeyResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/_current/passkeys/_begin": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get the options to pass to navigator.credentials.create to add a passkey to the current user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Passkey API"
+                ],
+                "summary": "Begin passkey registration",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.WebResponse-go-clean-template_internal_model_PasskeyCreationOptions"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/_current/passkeys/{passkeyId}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete a passkey of the current user, it can no longer be used to log in",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Passkey API"
+                ],
+                "summary": "Delete passkey",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Passkey ID",
+                        "name": "passkeyId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.WebResponse-bool"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/_current/sessions": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "List the active sessions of the current user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Session API"
+                ],
+                "summary": "List sessions",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.WebResponse-array_go-clean-template_internal_model_SessionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Log out the current user everywhere",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Session API"
+                ],
+                "summary": "Revoke all sessions",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.WebResponse-bool"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/_current/sessions/{sessionId}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Log out a single session of the current user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Session API"
+                ],
+                "summary": "Revoke session",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.WebResponse-bool"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/_forgot-password": {
+            "post": {
+                "description": "Send a password reset link to the user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User API"
+                ],
+                "summary": "Forgot password",
+                "parameters": [
+                    {
+                        "description": "Forgot Password Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ForgotPasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.WebResponse-bool"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/_login": {
+            "post": {
+                "description": "Login user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User API"
+                ],
+                "summary": "Login user",
+                "parameters": [
+                    {
+                        "description": "Login User Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.LoginUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.WebResponse-go-clean-template_internal_model_UserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "423": {
+                        "description": "Locked",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/_login/magic-link": {
+            "post": {
+                "description": "Exchange the token from a magic link and the device_token returned when it was requested for an access and refresh token pair, or an mfa_token when two-factor authentication is enabled",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User API"
+                ],
+                "summary": "Login with a magic link",
+                "parameters": [
+                    {
+                        "description": "Login Magic Link Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.LoginMagicLinkRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.WebResponse-go-clean-template_internal_model_UserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/_login/mfa": {
+            "post": {
+                "description": "Exchange the mfa_token returned by login and a TOTP or recovery code for an access and refresh token pair",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User API"
+                ],
+                "summary": "Complete two-factor login",
+                "parameters": [
+                    {
+                        "description": "Login Mfa User Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.LoginMfaUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.WebResponse-go-clean-template_internal_model_UserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/_login/passkey": {
+            "post": {
+                "description": "Exchange the credential returned by navigator.credentials.get for an access and refresh token pair",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User API"
+                ],
+                "summary": "Login with a passkey",
+                "parameters": [
+                    {
+                        "description": "Login Passkey Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.LoginPasskeyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.WebResponse-go-clean-template_internal_model_UserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/_login/passkey/_begin": {
+            "post": {
+                "description": "Get the options to pass to navigator.credentials.get, without an id any passkey of the site can answer",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User API"
+                ],
+                "summary": "Begin passkey login",
+                "parameters": [
+                    {
+                        "description": "Begin Passkey Login Request",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.BeginPasskeyLoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.WebResponse-go-clean-template_internal_model_PasskeyRequestOptions"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/_magic-link": {
+            "post": {
+                "description": "Email a single-use login link to the user, keep the returned device_token to redeem it",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User API"
+                ],
+                "summary": "Request magic link",
+                "parameters": [
+                    {
+                        "description": "Request Magic Link Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.RequestMagicLinkRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.WebResponse-go-clean-template_internal_model_MagicLinkResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/_oidc/authorize": {
+            "post": {
+                "description": "Start an OpenID Connect login, send the user agent to the returned URL",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User API"
+                ],
+                "summary": "Start single sign-on",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.WebResponse-go-clean-template_internal_model_OidcAuthorizationResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/_oidc/callback": {
+            "post": {
+                "description": "Exchange the code and state the issuer redirected back with for a session",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User API"
+                ],
+                "summary": "Complete single sign-on",
+                "parameters": [
+                    {
+                        "description": "OIDC Callback Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.OidcCallbackRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.WebResponse-go-clean-template_internal_model_UserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/_refresh": {
+            "post": {
+                "description": "Exchange a refresh token for a new access and refresh token pair",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User API"
+                ],
+                "summary": "Refresh access token",
+                "parameters": [
+                    {
+                        "description": "Refresh User Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.RefreshUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.WebResponse-go-clean-template_internal_model_UserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/_resend-verification": {
+            "post": {
+                "description": "Send a new verification link to an unverified email",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User API"
+                ],
+                "summary": "Resend email verification",
+                "parameters": [
+                    {
+                        "description": "Resend Verification Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ResendVerificationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.WebResponse-bool"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/_reset-password": {
+            "post": {
+                "description": "Set a new password using a password reset token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User API"
+                ],
+                "summary": "Reset password",
+                "parameters": [
+                    {
+                        "description": "Reset Password Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ResetPasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.WebResponse-bool"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/_verify-email": {
+            "post": {
+                "description": "Mark the user's email as verified using a verification token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User API"
+                ],
+                "summary": "Verify email",
+                "parameters": [
+                    {
+                        "description": "Verify Email Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.VerifyEmailRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.WebResponse-bool"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/go-clean-template_internal_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "go-clean-template_internal_model.AddressResponse": {
+            "type": "object",
+            "properties": {
+                "city": {
+                    "type": "string"
+                },
+                "country": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "integer"
+                },
+                "deleted_at": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "postal_code": {
+                    "type": "string"
+                },
+                "province": {
+                    "type": "string"
+                },
+                "street": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "integer"
+                }
+            }
+        },
+        "go-clean-template_internal_model.ApiKeyResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "integer"
+                },
+                "expires_at": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "key": {
+                    "description": "Key is only returned once, when the key is created",
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "go-clean-template_internal_model.BeginPasskeyLoginRequest": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string",
+                    "maxLength": 100
+                }
+            }
+        },
+        "go-clean-template_internal_model.ChangePasswordRequest": {
+            "type": "object",
+            "required": [
+                "current_password",
+                "password"
+            ],
+            "properties": {
+                "current_password": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "password": {
+                    "type": "string",
+                    "maxLength": 100
+                }
+            }
+        },
+        "go-clean-template_internal_model.ConfirmTotpRequest": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string"
+                }
+            }
+        },
+        "go-clean-template_internal_model.ContactResponse": {
+            "type": "object",
+            "properties": {
+                "addresses": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/go-clean-template_internal_model.AddressResponse"
+                    }
+                },
+                "created_at": {
+                    "type": "integer"
+                },
+                "deleted_at": {
+                    "type": "integer"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "first_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_name": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "integer"
+                }
+            }
+        },
+        "go-clean-template_internal_model.CreateAddressRequest": {
+            "type": "object",
+            "properties": {
+                "city": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "country": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "postal_code": {
+                    "type": "string",
+                    "maxLength": 10
+                },
+                "province": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "street": {
+                    "type": "string",
+                    "maxLength": 255
+                }
+            }
+        },
+        "go-clean-template_internal_model.CreateApiKeyRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "scopes"
+            ],
+            "properties": {
+                "expires_at": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "scopes": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "go-clean-template_internal_model.CreateContactRequest": {
+            "type": "object",
+            "required": [
+                "first_name"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "maxLength": 200
+                },
+                "first_name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "last_name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "phone": {
+                    "type": "string",
+                    "maxLength": 20
+                }
+            }
+        },
+        "go-clean-template_internal_model.DataExportResponse": {
+            "type": "object",
+            "properties": {
+                "completed_at": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "integer"
+                },
+                "expires_at": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "go-clean-template_internal_model.DeleteUserRequest": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "maxLength": 20
+                },
+                "password": {
+                    "type": "string",
+                    "maxLength": 100
+                }
+            }
+        },
+        "go-clean-template_internal_model.DisableTotpRequest": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "maxLength": 20
+                }
+            }
+        },
+        "go-clean-template_internal_model.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "type": "string"
+                },
+                "fields": {
+                    "description": "Fields lists the reasons per rejected field, only for field validation errors",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "go-clean-template_internal_model.FinishPasskeyRegistrationRequest": {
+            "type": "object",
+            "required": [
+                "credential"
+            ],
+            "properties": {
+                "credential": {
+                    "$ref": "#/definitions/go-clean-template_internal_model.PasskeyAttestation"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                }
+            }
+        },
+        "go-clean-template_internal_model.ForgotPasswordRequest": {
             "type": "object",
+            "required": [
+                "id"
+            ],
             "properties": {
-                "city": {
+                "id": {
+                    "type": "string",
+                    "maxLength": 100
+                }
+            }
+        },
+        "go-clean-template_internal_model.GrantRoleRequest": {
+            "type": "object",
+            "required": [
+                "role_id"
+            ],
+            "properties": {
+                "role_id": {
+                    "type": "string",
+                    "maxLength": 100
+                }
+            }
+        },
+        "go-clean-template_internal_model.LoginMagicLinkRequest": {
+            "type": "object",
+            "required": [
+                "device_token",
+                "token"
+            ],
+            "properties": {
+                "device_token": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "token": {
+                    "type": "string",
+                    "maxLength": 100
+                }
+            }
+        },
+        "go-clean-template_internal_model.LoginMfaUserRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "mfa_token"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "maxLength": 20
+                },
+                "mfa_token": {
+                    "type": "string",
+                    "maxLength": 100
+                }
+            }
+        },
+        "go-clean-template_internal_model.LoginPasskeyRequest": {
+            "type": "object",
+            "required": [
+                "credential"
+            ],
+            "properties": {
+                "credential": {
+                    "$ref": "#/definitions/go-clean-template_internal_model.PasskeyAssertion"
+                }
+            }
+        },
+        "go-clean-template_internal_model.LoginUserRequest": {
+            "type": "object",
+            "required": [
+                "id",
+                "password"
+            ],
+            "properties": {
+                "id": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "password": {
+                    "type": "string",
+                    "maxLength": 100
+                }
+            }
+        },
+        "go-clean-template_internal_model.MagicLinkResponse": {
+            "type": "object",
+            "properties": {
+                "device_token": {
                     "type": "string"
                 },
-                "country": {
+                "expires_at": {
+                    "type": "integer"
+                }
+            }
+        },
+        "go-clean-template_internal_model.OidcAuthorizationResponse": {
+            "type": "object",
+            "properties": {
+                "authorization_url": {
                     "type": "string"
+                }
+            }
+        },
+        "go-clean-template_internal_model.OidcCallbackRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "state"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "maxLength": 2048
                 },
-                "created_at": {
+                "state": {
+                    "type": "string",
+                    "maxLength": 100
+                }
+            }
+        },
+        "go-clean-template_internal_model.PageMetadata": {
+            "type": "object",
+            "properties": {
+                "next": {
+                    "description": "Next and Previous are cursors to the neighbouring pages where the list supports them",
+                    "type": "string"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "previous": {
+                    "type": "string"
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "total_item": {
+                    "type": "integer"
+                },
+                "total_page": {
                     "type": "integer"
+                }
+            }
+        },
+        "go-clean-template_internal_model.PageResponse-go-clean-template_internal_model_ContactResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/go-clean-template_internal_model.ContactResponse"
+                    }
+                },
+                "paging": {
+                    "$ref": "#/definitions/go-clean-template_internal_model.PageMetadata"
+                }
+            }
+        },
+        "go-clean-template_internal_model.PageResponse-go-clean-template_internal_model_UserResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/go-clean-template_internal_model.UserResponse"
+                    }
                 },
+                "paging": {
+                    "$ref": "#/definitions/go-clean-template_internal_model.PageMetadata"
+                }
+            }
+        },
+        "go-clean-template_internal_model.PasskeyAssertion": {
+            "type": "object",
+            "required": [
+                "id",
+                "response",
+                "type"
+            ],
+            "properties": {
                 "id": {
+                    "type": "string",
+                    "maxLength": 1400
+                },
+                "response": {
+                    "$ref": "#/definitions/go-clean-template_internal_model.PasskeyAssertionResponse"
+                },
+                "type": {
                     "type": "string"
+                }
+            }
+        },
+        "go-clean-template_internal_model.PasskeyAssertionResponse": {
+            "type": "object",
+            "required": [
+                "authenticatorData",
+                "clientDataJSON",
+                "signature"
+            ],
+            "properties": {
+                "authenticatorData": {
+                    "type": "string",
+                    "maxLength": 4096
                 },
-                "postal_code": {
+                "clientDataJSON": {
+                    "type": "string",
+                    "maxLength": 4096
+                },
+                "signature": {
+                    "type": "string",
+                    "maxLength": 1024
+                },
+                "userHandle": {
+                    "type": "string",
+                    "maxLength": 100
+                }
+            }
+        },
+        "go-clean-template_internal_model.PasskeyAttestation": {
+            "type": "object",
+            "required": [
+                "id",
+                "response",
+                "type"
+            ],
+            "properties": {
+                "id": {
+                    "type": "string",
+                    "maxLength": 1400
+                },
+                "response": {
+                    "$ref": "#/definitions/go-clean-template_internal_model.PasskeyAttestationResponse"
+                },
+                "type": {
                     "type": "string"
+                }
+            }
+        },
+        "go-clean-template_internal_model.PasskeyAttestationResponse": {
+            "type": "object",
+            "required": [
+                "attestationObject",
+                "clientDataJSON"
+            ],
+            "properties": {
+                "attestationObject": {
+                    "type": "string",
+                    "maxLength": 65536
                 },
-                "province": {
+                "clientDataJSON": {
+                    "type": "string",
+                    "maxLength": 4096
+                }
+            }
+        },
+        "go-clean-template_internal_model.PasskeyAuthenticatorSelection": {
+            "type": "object",
+            "properties": {
+                "residentKey": {
                     "type": "string"
                 },
-                "street": {
+                "userVerification": {
+                    "type": "string"
+                }
+            }
+        },
+        "go-clean-template_internal_model.PasskeyCreationOptions": {
+            "type": "object",
+            "properties": {
+                "attestation": {
                     "type": "string"
                 },
-                "updated_at": {
+                "authenticatorSelection": {
+                    "$ref": "#/definitions/go-clean-template_internal_model.PasskeyAuthenticatorSelection"
+                },
+                "challenge": {
+                    "type": "string"
+                },
+                "excludeCredentials": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/go-clean-template_internal_model.PasskeyDescriptor"
+                    }
+                },
+                "pubKeyCredParams": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/go-clean-template_internal_model.PasskeyCredentialParameters"
+                    }
+                },
+                "rp": {
+                    "$ref": "#/definitions/go-clean-template_internal_model.PasskeyRelyingParty"
+                },
+                "timeout": {
                     "type": "integer"
+                },
+                "user": {
+                    "$ref": "#/definitions/go-clean-template_internal_model.PasskeyUser"
                 }
             }
         },
-        "go-clean-template_internal_model.ContactResponse": {
+        "go-clean-template_internal_model.PasskeyCredentialParameters": {
             "type": "object",
             "properties": {
-                "addresses": {
+                "alg": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "go-clean-template_internal_model.PasskeyDescriptor": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "go-clean-template_internal_model.PasskeyRelyingParty": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "go-clean-template_internal_model.PasskeyRequestOptions": {
+            "type": "object",
+            "properties": {
+                "allowCredentials": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/go-clean-template_internal_model.AddressResponse"
+                        "$ref": "#/definitions/go-clean-template_internal_model.PasskeyDescriptor"
                     }
                 },
+                "challenge": {
+                    "type": "string"
+                },
+                "rpId": {
+                    "type": "string"
+                },
+                "timeout": {
+                    "type": "integer"
+                },
+                "userVerification": {
+                    "type": "string"
+                }
+            }
+        },
+        "go-clean-template_internal_model.PasskeyResponse": {
+            "type": "object",
+            "properties": {
                 "created_at": {
                     "type": "integer"
                 },
-                "email": {
+                "id": {
                     "type": "string"
                 },
-                "first_name": {
+                "last_used_at": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "go-clean-template_internal_model.PasskeyUser": {
+            "type": "object",
+            "properties": {
+                "displayName": {
                     "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
-                "last_name": {
-                    "type": "string"
-                },
-                "phone": {
+                "name": {
                     "type": "string"
-                },
-                "updated_at": {
-                    "type": "integer"
                 }
             }
         },
-        "go-clean-template_internal_model.CreateAddressRequest": {
+        "go-clean-template_internal_model.RecoveryCodesResponse": {
             "type": "object",
             "properties": {
-                "city": {
-                    "type": "string",
-                    "maxLength": 255
-                },
-                "country": {
-                    "type": "string",
-                    "maxLength": 100
-                },
-                "postal_code": {
-                    "type": "string",
-                    "maxLength": 10
-                },
-                "province": {
+                "codes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "go-clean-template_internal_model.RefreshUserRequest": {
+            "type": "object",
+            "required": [
+                "refresh_token"
+            ],
+            "properties": {
+                "refresh_token": {
                     "type": "string",
-                    "maxLength": 255
-                },
-                "street": {
+                    "maxLength": 200
+                }
+            }
+        },
+        "go-clean-template_internal_model.RegenerateRecoveryCodesRequest": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
                     "type": "string",
-                    "maxLength": 255
+                    "maxLength": 20
                 }
             }
         },
-        "go-clean-template_internal_model.CreateContactRequest": {
+        "go-clean-template_internal_model.RegisterUserRequest": {
             "type": "object",
             "required": [
-                "first_name"
+                "email",
+                "id",
+                "name",
+                "password"
             ],
             "properties": {
                 "email": {
                     "type": "string",
                     "maxLength": 200
                 },
-                "first_name": {
+                "id": {
                     "type": "string",
                     "maxLength": 100
                 },
-                "last_name": {
+                "name": {
                     "type": "string",
                     "maxLength": 100
                 },
-                "phone": {
+                "password": {
                     "type": "string",
-                    "maxLength": 20
+                    "maxLength": 100
                 }
             }
         },
-        "go-clean-template_internal_model.ErrorResponse": {
+        "go-clean-template_internal_model.RequestMagicLinkRequest": {
+            "type": "object",
+            "required": [
+                "id"
+            ],
+            "properties": {
+                "id": {
+                    "type": "string",
+                    "maxLength": 100
+                }
+            }
+        },
+        "go-clean-template_internal_model.ResendVerificationRequest": {
             "type": "object",
+            "required": [
+                "email"
+            ],
             "properties": {
-                "errors": {
-                    "type": "string"
+                "email": {
+                    "type": "string",
+                    "maxLength": 200
                 }
             }
         },
-        "go-clean-template_internal_model.LoginUserRequest": {
+        "go-clean-template_internal_model.ResetPasswordRequest": {
             "type": "object",
             "required": [
-                "id",
-                "password"
+                "password",
+                "token"
             ],
             "properties": {
-                "id": {
+                "password": {
                     "type": "string",
                     "maxLength": 100
                 },
-                "password": {
+                "token": {
                     "type": "string",
                     "maxLength": 100
                 }
             }
         },
-        "go-clean-template_internal_model.RegisterUserRequest": {
+        "go-clean-template_internal_model.RoleResponse": {
             "type": "object",
-            "required": [
-                "id",
-                "name",
-                "password"
-            ],
             "properties": {
+                "description": {
+                    "type": "string"
+                },
                 "id": {
-                    "type": "string",
-                    "maxLength": 100
+                    "type": "string"
                 },
                 "name": {
-                    "type": "string",
-                    "maxLength": 100
+                    "type": "string"
                 },
-                "password": {
-                    "type": "string",
-                    "maxLength": 100
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "go-clean-template_internal_model.SessionResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "integer"
+                },
+                "current": {
+                    "type": "boolean"
+                },
+                "expires_at": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "ip_address": {
+                    "type": "string"
+                },
+                "last_seen_at": {
+                    "type": "integer"
+                },
+                "user_agent": {
+                    "type": "string"
+                }
+            }
+        },
+        "go-clean-template_internal_model.TotpEnrollmentResponse": {
+            "type": "object",
+            "properties": {
+                "secret": {
+                    "type": "string"
+                },
+                "uri": {
+                    "type": "string"
                 }
             }
         },
@@ -999,10 +4267,6 @@ const docTemplate = `{
                 "name": {
                     "type": "string",
                     "maxLength": 100
-                },
-                "password": {
-                    "type": "string",
-                    "maxLength": 100
                 }
             }
         },
@@ -1012,12 +4276,36 @@ const docTemplate = `{
                 "created_at": {
                     "type": "integer"
                 },
+                "delete_after": {
+                    "type": "integer"
+                },
+                "disabled_at": {
+                    "type": "integer"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "email_verified_at": {
+                    "type": "integer"
+                },
+                "expires_at": {
+                    "type": "integer"
+                },
                 "id": {
                     "type": "string"
                 },
+                "mfa_required": {
+                    "type": "boolean"
+                },
+                "mfa_token": {
+                    "type": "string"
+                },
                 "name": {
                     "type": "string"
                 },
+                "refresh_token": {
+                    "type": "string"
+                },
                 "token": {
                     "type": "string"
                 },
@@ -1026,6 +4314,18 @@ const docTemplate = `{
                 }
             }
         },
+        "go-clean-template_internal_model.VerifyEmailRequest": {
+            "type": "object",
+            "required": [
+                "token"
+            ],
+            "properties": {
+                "token": {
+                    "type": "string",
+                    "maxLength": 100
+                }
+            }
+        },
         "go-clean-template_internal_model.WebResponse-array_go-clean-template_internal_model_AddressResponse": {
             "type": "object",
             "properties": {
@@ -1037,6 +4337,17 @@ const docTemplate = `{
                 }
             }
         },
+        "go-clean-template_internal_model.WebResponse-array_go-clean-template_internal_model_ApiKeyResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/go-clean-template_internal_model.ApiKeyResponse"
+                    }
+                }
+            }
+        },
         "go-clean-template_internal_model.WebResponse-array_go-clean-template_internal_model_ContactResponse": {
             "type": "object",
             "properties": {
@@ -1048,6 +4359,39 @@ const docTemplate = `{
                 }
             }
         },
+        "go-clean-template_internal_model.WebResponse-array_go-clean-template_internal_model_PasskeyResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/go-clean-template_internal_model.PasskeyResponse"
+                    }
+                }
+            }
+        },
+        "go-clean-template_internal_model.WebResponse-array_go-clean-template_internal_model_RoleResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/go-clean-template_internal_model.RoleResponse"
+                    }
+                }
+            }
+        },
+        "go-clean-template_internal_model.WebResponse-array_go-clean-template_internal_model_SessionResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/go-clean-template_internal_model.SessionResponse"
+                    }
+                }
+            }
+        },
         "go-clean-template_internal_model.WebResponse-bool": {
             "type": "object",
             "properties": {
@@ -1064,6 +4408,14 @@ const docTemplate = `{
                 }
             }
         },
+        "go-clean-template_internal_model.WebResponse-go-clean-template_internal_model_ApiKeyResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/go-clean-template_internal_model.ApiKeyResponse"
+                }
+            }
+        },
         "go-clean-template_internal_model.WebResponse-go-clean-template_internal_model_ContactResponse": {
             "type": "object",
             "properties": {
@@ -1072,6 +4424,70 @@ const docTemplate = `{
                 }
             }
         },
+        "go-clean-template_internal_model.WebResponse-go-clean-template_internal_model_DataExportResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/go-clean-template_internal_model.DataExportResponse"
+                }
+            }
+        },
+        "go-clean-template_internal_model.WebResponse-go-clean-template_internal_model_MagicLinkResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/go-clean-template_internal_model.MagicLinkResponse"
+                }
+            }
+        },
+        "go-clean-template_internal_model.WebResponse-go-clean-template_internal_model_OidcAuthorizationResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/go-clean-template_internal_model.OidcAuthorizationResponse"
+                }
+            }
+        },
+        "go-clean-template_internal_model.WebResponse-go-clean-template_internal_model_PasskeyCreationOptions": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/go-clean-template_internal_model.PasskeyCreationOptions"
+                }
+            }
+        },
+        "go-clean-template_internal_model.WebResponse-go-clean-template_internal_model_PasskeyRequestOptions": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/go-clean-template_internal_model.PasskeyRequestOptions"
+                }
+            }
+        },
+        "go-clean-template_internal_model.WebResponse-go-clean-template_internal_model_PasskeyResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/go-clean-template_internal_model.PasskeyResponse"
+                }
+            }
+        },
+        "go-clean-template_internal_model.WebResponse-go-clean-template_internal_model_RecoveryCodesResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/go-clean-template_internal_model.RecoveryCodesResponse"
+                }
+            }
+        },
+        "go-clean-template_internal_model.WebResponse-go-clean-template_internal_model_TotpEnrollmentResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/go-clean-template_internal_model.TotpEnrollmentResponse"
+                }
+            }
+        },
         "go-clean-template_internal_model.WebResponse-go-clean-template_internal_model_UserResponse": {
             "type": "object",
             "properties": {
@@ -1,10 +1,13 @@
 package config
 
 import (
+	"time"
+
 	"go-clean-template/internal/delivery/http"
 	"go-clean-template/internal/delivery/http/middleware"
 	"go-clean-template/internal/delivery/http/route"
 	"go-clean-template/internal/gateway/messaging"
+	"go-clean-template/internal/gateway/notifier"
 	"go-clean-template/internal/repository"
 	"go-clean-template/internal/usecase"
 
@@ -23,11 +26,29 @@ type BootstrapConfig struct {
 	Validate *validator.Validate
 	Config   *viper.Viper
 	Producer sarama.SyncProducer
+	Notifier notifier.Notifier
 }
 
 func Bootstrap(config *BootstrapConfig) {
 	// setup repositories
 	userRepository := repository.NewUserRepository(config.Log)
+	sessionRepository := repository.NewSessionRepository(config.Log)
+	passwordResetRepository := repository.NewPasswordResetRepository(config.Log)
+	magicLinkRepository := repository.NewMagicLinkRepository(config.Log)
+	emailVerificationRepository := repository.NewEmailVerificationRepository(config.Log)
+	userTotpRepository := repository.NewUserTotpRepository(config.Log)
+	recoveryCodeRepository := repository.NewRecoveryCodeRepository(config.Log)
+	mfaChallengeRepository := repository.NewMfaChallengeRepository(config.Log)
+	loginAttemptRepository := repository.NewLoginAttemptRepository(config.Log)
+	apiKeyRepository := repository.NewApiKeyRepository(config.Log)
+	userIdentityRepository := repository.NewUserIdentityRepository(config.Log)
+	oidcStateRepository := repository.NewOidcStateRepository(config.Log)
+	passkeyRepository := repository.NewPasskeyRepository(config.Log)
+	webAuthnChallengeRepository := repository.NewWebAuthnChallengeRepository(config.Log)
+	roleRepository := repository.NewRoleRepository(config.Log)
+	auditLogRepository := repository.NewAuditLogRepository(config.Log)
+	dataExportRepository := repository.NewDataExportRepository(config.Log)
+	userRoleRepository := repository.NewUserRoleRepository(config.Log)
 	contactRepository := repository.NewContactRepository(config.Log)
 	addressRepository := repository.NewAddressRepository(config.Log)
 
@@ -42,25 +63,106 @@ func Bootstrap(config *BootstrapConfig) {
 		addressProducer = messaging.NewAddressProducer(config.Producer, config.Log)
 	}
 
+	// setup security
+	tokenProvider := NewTokenProvider(config.Config, config.Log)
+	sessionPolicy := NewSessionPolicy(config.Config)
+	secretCipher := NewSecretCipher(config.Config, config.Log)
+	oidcProvider := NewOidcProvider(config.Config, config.Log)
+	webAuthn := NewWebAuthn(config.Config, config.Log)
+	passwordHasher := NewPasswordHasher(config.Config, config.Log)
+	passwordPolicy := NewPasswordPolicy(config.Config, config.Log)
+	tokenHasher := NewTokenHasher(config.Config, config.Log)
+
 	// setup use cases
-	userUseCase := usecase.NewUserUseCase(config.DB, config.Log, config.Validate, userRepository, userProducer)
-	contactUseCase := usecase.NewContactUseCase(config.DB, config.Log, config.Validate, contactRepository, contactProducer)
+	emailVerificationUseCase := usecase.NewEmailVerificationUseCase(config.DB, config.Log, config.Validate, userRepository,
+		emailVerificationRepository, config.Notifier,
+		time.Second*time.Duration(config.Config.GetInt("email_verification.ttl")),
+		time.Second*time.Duration(config.Config.GetInt("email_verification.resend_interval")),
+		config.Config.GetString("email_verification.url"))
+	loginProtectionUseCase := usecase.NewLoginProtectionUseCase(config.DB, config.Log, loginAttemptRepository,
+		NewLoginThrottle(config.Config, "login_protection.account"), NewLoginThrottle(config.Config, "login_protection.ip"))
+	mfaUseCase := usecase.NewMfaUseCase(config.DB, config.Log, config.Validate, userRepository, userTotpRepository,
+		recoveryCodeRepository, secretCipher, config.Config.GetString("mfa.issuer"), loginProtectionUseCase)
+	userUseCase := usecase.NewUserUseCase(config.DB, config.Log, config.Validate, userRepository, sessionRepository, tokenProvider, sessionPolicy, userProducer,
+		emailVerificationUseCase, config.Config.GetBool("email_verification.block_login"),
+		mfaUseCase, mfaChallengeRepository,
+		time.Second*time.Duration(config.Config.GetInt("mfa.challenge_ttl")), config.Config.GetInt("mfa.challenge_attempts"),
+		loginProtectionUseCase, roleRepository, passwordHasher, passwordPolicy, auditLogRepository, tokenHasher,
+		magicLinkRepository)
+	magicLinkUseCase := usecase.NewMagicLinkUseCase(config.DB, config.Log, config.Validate, userRepository, magicLinkRepository,
+		config.Notifier, tokenHasher, time.Second*time.Duration(config.Config.GetInt("magic_link.ttl")),
+		config.Config.GetString("magic_link.url"), loginProtectionUseCase)
+	oidcUseCase := usecase.NewOidcUseCase(config.DB, config.Log, config.Validate, userRepository, userIdentityRepository,
+		oidcStateRepository, oidcProvider, userUseCase, userProducer,
+		time.Second*time.Duration(config.Config.GetInt("oidc.state_ttl")),
+		config.Config.GetBool("oidc.auto_provision"), config.Config.GetBool("oidc.link_by_email"))
+	passkeyUseCase := usecase.NewPasskeyUseCase(config.DB, config.Log, config.Validate, userRepository, passkeyRepository,
+		webAuthnChallengeRepository, auditLogRepository, webAuthn, userUseCase, userProducer,
+		time.Second*time.Duration(config.Config.GetInt("webauthn.challenge_ttl")))
+	apiKeyUseCase := usecase.NewApiKeyUseCase(config.DB, config.Log, config.Validate, userRepository, apiKeyRepository,
+		time.Second*time.Duration(config.Config.GetInt("api_key.last_used_interval")), config.Config.GetInt("api_key.max_per_user"))
+	roleUseCase := usecase.NewRoleUseCase(config.DB, config.Log, config.Validate, userRepository, roleRepository, userRoleRepository)
+	sessionUseCase := usecase.NewSessionUseCase(config.DB, config.Log, config.Validate, sessionRepository, sessionPolicy)
+	passwordResetUseCase := usecase.NewPasswordResetUseCase(config.DB, config.Log, config.Validate, userRepository, sessionRepository,
+		passwordResetRepository, config.Notifier, userProducer,
+		time.Second*time.Duration(config.Config.GetInt("password_reset.ttl")), config.Config.GetString("password_reset.url"),
+		passwordHasher, passwordPolicy)
+	adminUserUseCase := usecase.NewAdminUserUseCase(config.DB, config.Log, config.Validate, userRepository, sessionRepository,
+		passwordResetUseCase, userProducer, auditLogRepository, tokenProvider,
+		time.Second*time.Duration(config.Config.GetInt("impersonation.ttl")))
+	auditLogUseCase := usecase.NewAuditLogUseCase(config.DB, config.Log, config.Validate, auditLogRepository)
+	accountDeletionUseCase := usecase.NewAccountDeletionUseCase(config.DB, config.Log, config.Validate, userRepository, sessionRepository,
+		contactRepository, addressRepository, passwordHasher, userProducer, auditLogRepository, apiKeyRepository, mfaUseCase,
+		loginProtectionUseCase, time.Second*time.Duration(config.Config.GetInt("account_deletion.grace_period")),
+		time.Second*time.Duration(config.Config.GetInt("account_deletion.reauth_window")))
+	dataExportUseCase := usecase.NewDataExportUseCase(config.DB, config.Log, config.Validate, userRepository, sessionRepository,
+		contactRepository, auditLogRepository, dataExportRepository, config.Notifier,
+		time.Second*time.Duration(config.Config.GetInt("data_export.ttl")), config.Config.GetString("data_export.url"))
+	contactUseCase := usecase.NewContactUseCase(config.DB, config.Log, config.Validate, contactRepository, addressRepository, userRepository,
+		contactProducer, addressProducer, config.Config.GetBool("email_verification.block_contact_create"),
+		time.Second*time.Duration(config.Config.GetInt("trash.retention")))
 	addressUseCase := usecase.NewAddressUseCase(config.DB, config.Log, config.Validate, contactRepository, addressRepository, addressProducer)
 
 	// setup controller
 	userController := http.NewUserController(userUseCase, config.Log)
+	sessionController := http.NewSessionController(sessionUseCase, config.Log)
+	passwordResetController := http.NewPasswordResetController(passwordResetUseCase, config.Log)
+	magicLinkController := http.NewMagicLinkController(magicLinkUseCase, config.Log)
+	emailVerificationController := http.NewEmailVerificationController(emailVerificationUseCase, config.Log)
+	mfaController := http.NewMfaController(mfaUseCase, config.Log)
+	oidcController := http.NewOidcController(oidcUseCase, config.Log)
+	passkeyController := http.NewPasskeyController(passkeyUseCase, config.Log)
+	apiKeyController := http.NewApiKeyController(apiKeyUseCase, config.Log)
+	roleController := http.NewRoleController(roleUseCase, config.Log)
+	adminUserController := http.NewAdminUserController(adminUserUseCase, config.Log)
+	accountDeletionController := http.NewAccountDeletionController(accountDeletionUseCase, config.Log)
+	dataExportController := http.NewDataExportController(dataExportUseCase, config.Log)
 	contactController := http.NewContactController(contactUseCase, config.Log)
 	addressController := http.NewAddressController(addressUseCase, config.Log)
 
 	// setup middleware
-	authMiddleware := middleware.NewAuth(userUseCase)
+	authMiddleware := middleware.NewAuth(userUseCase, apiKeyUseCase)
+	impersonationMiddleware := middleware.NewImpersonationAudit(auditLogUseCase)
 
 	routeConfig := route.RouteConfig{
-		App:               config.App,
-		UserController:    userController,
-		ContactController: contactController,
-		AddressController: addressController,
-		AuthMiddleware:    authMiddleware,
+		App:                         config.App,
+		UserController:              userController,
+		SessionController:           sessionController,
+		PasswordResetController:     passwordResetController,
+		MagicLinkController:         magicLinkController,
+		EmailVerificationController: emailVerificationController,
+		MfaController:               mfaController,
+		OidcController:              oidcController,
+		PasskeyController:           passkeyController,
+		ApiKeyController:            apiKeyController,
+		RoleController:              roleController,
+		AdminUserController:         adminUserController,
+		AccountDeletionController:   accountDeletionController,
+		DataExportController:        dataExportController,
+		ContactController:           contactController,
+		AddressController:           addressController,
+		AuthMiddleware:              authMiddleware,
+		ImpersonationMiddleware:     impersonationMiddleware,
 	}
 	routeConfig.Setup()
 }
@@ -1,6 +1,10 @@
 package config
 
 import (
+	"errors"
+
+	"go-clean-template/internal/usecase"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/spf13/viper"
 )
@@ -18,10 +22,24 @@ func NewFiber(config *viper.Viper) *fiber.App {
 func NewErrorHandler() fiber.ErrorHandler {
 	return func(ctx *fiber.Ctx, err error) error {
 		code := fiber.StatusInternalServerError
-		if e, ok := err.(*fiber.Error); ok {
+		var e *fiber.Error
+		if errors.As(err, &e) {
 			code = e.Code
 		}
 
+		var retryAfter *usecase.RetryAfterError
+		if errors.As(err, &retryAfter) {
+			ctx.Set(fiber.HeaderRetryAfter, retryAfter.RetryAfter)
+		}
+
+		var fieldError *usecase.FieldError
+		if errors.As(err, &fieldError) {
+			return ctx.Status(code).JSON(fiber.Map{
+				"errors": err.Error(),
+				"fields": fieldError.Fields,
+			})
+		}
+
 		return ctx.Status(code).JSON(fiber.Map{
 			"errors": err.Error(),
 		})
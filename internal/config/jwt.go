@@ -0,0 +1,64 @@
+package config
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"slices"
+	"time"
+
+	"go-clean-template/internal/security"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// exampleJWTSecrets are the placeholders shipped with the example configuration, they are public and would let
+// anyone sign access tokens
+var exampleJWTSecrets = []string{
+	"will-be-overwritten-by-env-at-least-32-chars",
+	"secret-jwt-signing-key-at-least-32-chars",
+	"your-signing-key-of-at-least-32-chars",
+}
+
+func NewTokenProvider(viper *viper.Viper, log *zap.SugaredLogger) *security.TokenProvider {
+	provider := &security.TokenProvider{
+		Issuer:    viper.GetString("jwt.issuer"),
+		AccessTTL: time.Second * time.Duration(viper.GetInt("jwt.access_ttl")),
+	}
+
+	switch algorithm := viper.GetString("jwt.algorithm"); algorithm {
+	case "EdDSA":
+		seed, err := base64.StdEncoding.DecodeString(viper.GetString("jwt.private_key"))
+		if err != nil || len(seed) != ed25519.SeedSize {
+			log.Fatalf("jwt.private_key must be a base64 encoded ed25519 seed")
+		}
+		privateKey := ed25519.NewKeyFromSeed(seed)
+		provider.Method = jwt.SigningMethodEdDSA
+		provider.SignKey = privateKey
+		provider.VerifyKey = privateKey.Public()
+	case "HS256", "":
+		secret := viper.GetString("jwt.secret")
+		if len(secret) < 32 {
+			log.Fatalf("jwt.secret must be at least 32 characters")
+		}
+		if slices.Contains(exampleJWTSecrets, secret) {
+			log.Fatalf("jwt.secret is an example value, set JWT_SECRET to a secret of your own")
+		}
+		provider.Method = jwt.SigningMethodHS256
+		provider.SignKey = []byte(secret)
+		provider.VerifyKey = []byte(secret)
+	default:
+		log.Fatalf("Unsupported jwt algorithm: %s", algorithm)
+	}
+
+	if legacyUntil := viper.GetString("jwt.legacy_until"); legacyUntil != "" {
+		until, err := time.Parse(time.RFC3339, legacyUntil)
+		if err != nil {
+			log.Fatalf("Failed to parse jwt.legacy_until: %v", err)
+		}
+		provider.LegacyUntil = until
+	}
+
+	return provider
+}
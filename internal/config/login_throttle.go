@@ -0,0 +1,19 @@
+package config
+
+import (
+	"time"
+
+	"go-clean-template/internal/security"
+
+	"github.com/spf13/viper"
+)
+
+// NewLoginThrottle reads the throttle under a prefix, login_protection.account or login_protection.ip
+func NewLoginThrottle(viper *viper.Viper, prefix string) *security.LoginThrottle {
+	return &security.LoginThrottle{
+		Threshold:  viper.GetInt(prefix + ".threshold"),
+		Lockout:    time.Second * time.Duration(viper.GetInt(prefix+".lockout")),
+		MaxLockout: time.Second * time.Duration(viper.GetInt(prefix+".max_lockout")),
+		ResetAfter: time.Second * time.Duration(viper.GetInt(prefix+".reset_after")),
+	}
+}
@@ -0,0 +1,40 @@
+package config
+
+import (
+	"encoding/base64"
+	"slices"
+
+	"go-clean-template/internal/security"
+
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// exampleMfaEncryptionKeys are the keys shipped with the example configuration, they are public and would let
+// anyone decrypt the stored TOTP secrets
+var exampleMfaEncryptionKeys = []string{
+	"d2lsbC1iZS1vdmVyd3JpdHRlbi1ieS1lbnYtMzJieXQ=",
+	"c2VjcmV0LW1mYS1lbmNyeXB0aW9uLWtleS0zMmJ5dGU=",
+}
+
+func NewSecretCipher(viper *viper.Viper, log *zap.SugaredLogger) *security.SecretCipher {
+	encoded := viper.GetString("mfa.encryption_key")
+	if encoded == "" {
+		log.Fatalf("mfa.encryption_key must be set, generate one with openssl rand -base64 32")
+	}
+	if slices.Contains(exampleMfaEncryptionKeys, encoded) {
+		log.Fatalf("mfa.encryption_key is an example value, set MFA_ENCRYPTION_KEY to a key of your own")
+	}
+
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		log.Fatalf("mfa.encryption_key must be base64 encoded: %v", err)
+	}
+
+	cipher, err := security.NewSecretCipher(key)
+	if err != nil {
+		log.Fatalf("Failed to create secret cipher: %v", err)
+	}
+
+	return cipher
+}
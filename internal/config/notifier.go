@@ -0,0 +1,20 @@
+package config
+
+import (
+	"go-clean-template/internal/gateway/notifier"
+
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+func NewNotifier(viper *viper.Viper, log *zap.SugaredLogger) notifier.Notifier {
+	switch driver := viper.GetString("notifier.driver"); driver {
+	case "file":
+		return notifier.NewFileNotifier(viper.GetString("notifier.file.path"), log)
+	case "log", "":
+		return notifier.NewLogNotifier(log)
+	default:
+		log.Fatalf("Unsupported notifier driver: %s", driver)
+		return nil
+	}
+}
@@ -0,0 +1,33 @@
+package config
+
+import (
+	"time"
+
+	"go-clean-template/internal/gateway/oidc"
+
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// NewOidcProvider returns nil when OpenID Connect login is disabled
+func NewOidcProvider(viper *viper.Viper, log *zap.SugaredLogger) *oidc.Provider {
+	if !viper.GetBool("oidc.enabled") {
+		return nil
+	}
+
+	issuer := viper.GetString("oidc.issuer")
+	clientId := viper.GetString("oidc.client_id")
+	redirectURL := viper.GetString("oidc.redirect_url")
+	if issuer == "" || clientId == "" || redirectURL == "" {
+		log.Fatalf("oidc.issuer, oidc.client_id and oidc.redirect_url are required when oidc is enabled")
+	}
+
+	// a hung issuer would otherwise hold authorize and callback requests for good
+	timeout := viper.GetInt("oidc.timeout")
+	if timeout <= 0 {
+		log.Fatalf("oidc.timeout must be at least 1 second")
+	}
+
+	return oidc.NewProvider(issuer, clientId, viper.GetString("oidc.client_secret"), redirectURL,
+		viper.GetStringSlice("oidc.scopes"), time.Second*time.Duration(timeout))
+}
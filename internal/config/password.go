@@ -0,0 +1,49 @@
+package config
+
+import (
+	"os"
+
+	"go-clean-template/internal/security"
+
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+func NewPasswordHasher(viper *viper.Viper, log *zap.SugaredLogger) security.PasswordHasher {
+	hasher := &security.Argon2idHasher{
+		Memory:      viper.GetUint32("password_hash.memory"),
+		Iterations:  viper.GetUint32("password_hash.iterations"),
+		Parallelism: uint8(viper.GetUint("password_hash.parallelism")),
+		SaltLength:  16,
+		KeyLength:   32,
+	}
+
+	if hasher.Memory < 8*uint32(hasher.Parallelism) || hasher.Iterations < 1 || hasher.Parallelism < 1 {
+		log.Fatalf("password_hash needs iterations and parallelism of at least 1 and memory of at least 8 KiB per thread")
+	}
+
+	return hasher
+}
+
+func NewPasswordPolicy(viper *viper.Viper, log *zap.SugaredLogger) *security.PasswordPolicy {
+	policy := &security.PasswordPolicy{
+		MinLength:      viper.GetInt("password_policy.min_length"),
+		MinClasses:     viper.GetInt("password_policy.min_classes"),
+		RejectUserInfo: viper.GetBool("password_policy.reject_user_info"),
+	}
+
+	if path := viper.GetString("password_policy.breached_list"); path != "" {
+		file, err := os.Open(path)
+		if err != nil {
+			log.Fatalf("Failed to open password_policy.breached_list: %v", err)
+		}
+		defer file.Close()
+
+		policy.Breached, err = security.LoadBreachedPasswords(file)
+		if err != nil {
+			log.Fatalf("Failed to load password_policy.breached_list: %v", err)
+		}
+	}
+
+	return policy
+}
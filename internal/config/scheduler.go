@@ -0,0 +1,19 @@
+package config
+
+import (
+	"time"
+
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// NewJobInterval reads the interval of a background job in seconds, a missing or non positive value
+// would stop the worker at its first tick so it is refused at startup
+func NewJobInterval(viper *viper.Viper, log *zap.SugaredLogger, key string) time.Duration {
+	seconds := viper.GetInt(key)
+	if seconds <= 0 {
+		log.Fatalf("%s must be at least 1 second", key)
+	}
+
+	return time.Second * time.Duration(seconds)
+}
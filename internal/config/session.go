@@ -0,0 +1,36 @@
+package config
+
+import (
+	"slices"
+	"time"
+
+	"go-clean-template/internal/security"
+
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// exampleTokenPeppers are the peppers shipped with the example configuration, a public pepper adds nothing to
+// the hash of a token
+var exampleTokenPeppers = []string{
+	"secret-session-token-pepper-32-chars",
+}
+
+func NewSessionPolicy(viper *viper.Viper) *security.SessionPolicy {
+	return &security.SessionPolicy{
+		AbsoluteTTL: time.Second * time.Duration(viper.GetInt("session.absolute_ttl")),
+		IdleTTL:     time.Second * time.Duration(viper.GetInt("session.idle_ttl")),
+	}
+}
+
+func NewTokenHasher(viper *viper.Viper, log *zap.SugaredLogger) *security.TokenHasher {
+	pepper := viper.GetString("session.token_pepper")
+	if pepper != "" && len(pepper) < 32 {
+		log.Fatalf("session.token_pepper must be at least 32 characters")
+	}
+	if slices.Contains(exampleTokenPeppers, pepper) {
+		log.Fatalf("session.token_pepper is an example value, set SESSION_TOKEN_PEPPER to a secret of your own")
+	}
+
+	return &security.TokenHasher{Pepper: []byte(pepper)}
+}
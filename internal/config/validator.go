@@ -1,10 +1,39 @@
 package config
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/go-playground/validator/v10"
 	"github.com/spf13/viper"
 )
 
 func NewValidator(viper *viper.Viper) *validator.Validate {
-	return validator.New()
+	validate := validator.New()
+
+	err := validate.RegisterValidation("unique_sort", uniqueSort)
+	if err != nil {
+		panic(fmt.Errorf("Fatal error validator: %w \n", err))
+	}
+
+	return validate
+}
+
+// uniqueSort rejects sort keys naming the same column twice, whatever the direction
+func uniqueSort(fl validator.FieldLevel) bool {
+	keys, ok := fl.Field().Interface().([]string)
+	if !ok {
+		return false
+	}
+
+	columns := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		column := strings.TrimPrefix(key, "-")
+		if columns[column] {
+			return false
+		}
+		columns[column] = true
+	}
+
+	return true
 }
@@ -0,0 +1,28 @@
+package config
+
+import (
+	"go-clean-template/internal/security"
+
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// NewWebAuthn returns nil when passkeys are disabled
+func NewWebAuthn(viper *viper.Viper, log *zap.SugaredLogger) *security.WebAuthn {
+	if !viper.GetBool("webauthn.enabled") {
+		return nil
+	}
+
+	rpId := viper.GetString("webauthn.rp_id")
+	origins := viper.GetStringSlice("webauthn.origins")
+	if rpId == "" || len(origins) == 0 {
+		log.Fatalf("webauthn.rp_id and webauthn.origins are required when webauthn is enabled")
+	}
+
+	rpName := viper.GetString("webauthn.rp_name")
+	if rpName == "" {
+		rpName = viper.GetString("app.name")
+	}
+
+	return &security.WebAuthn{RPID: rpId, RPName: rpName, Origins: origins}
+}
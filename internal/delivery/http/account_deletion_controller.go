@@ -0,0 +1,81 @@
+package http
+
+import (
+	"go-clean-template/internal/delivery/http/middleware"
+	"go-clean-template/internal/model"
+	"go-clean-template/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+type AccountDeletionController struct {
+	Log     *zap.SugaredLogger
+	UseCase *usecase.AccountDeletionUseCase
+}
+
+func NewAccountDeletionController(useCase *usecase.AccountDeletionUseCase, logger *zap.SugaredLogger) *AccountDeletionController {
+	return &AccountDeletionController{
+		Log:     logger,
+		UseCase: useCase,
+	}
+}
+
+// Delete godoc
+// @Summary Delete account
+// @Description Schedule the current user's account, contacts and addresses for deletion, confirmed with the password, a two-factor code or a recent login
+// @Tags User API
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body model.DeleteUserRequest true "Delete User Request"
+// @Success 200 {object} model.WebResponse[model.UserResponse]
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 423 {object} model.ErrorResponse
+// @Failure 429 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /api/users/_current [delete]
+func (c *AccountDeletionController) Delete(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+
+	request := new(model.DeleteUserRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.Warnf("Failed to parse request body : %+v", err)
+		return fiber.ErrBadRequest
+	}
+
+	request.ID = auth.ID
+	request.SessionId = auth.SessionID
+	request.UserAgent, request.IPAddress = clientInfo(ctx)
+	response, err := c.UseCase.Schedule(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Errorw("Failed to delete user", "error", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[*model.UserResponse]{Data: response})
+}
+
+// Cancel godoc
+// @Summary Cancel account deletion
+// @Description Keep the current user's account that is scheduled for deletion
+// @Tags User API
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} model.WebResponse[model.UserResponse]
+// @Failure 500 {object} model.ErrorResponse
+// @Router /api/users/_current/_cancel-deletion [post]
+func (c *AccountDeletionController) Cancel(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+
+	request := &model.CancelUserDeletionRequest{ID: auth.ID}
+	request.UserAgent, request.IPAddress = clientInfo(ctx)
+	response, err := c.UseCase.Cancel(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Errorw("Failed to cancel user deletion", "error", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[*model.UserResponse]{Data: response})
+}
@@ -185,3 +185,65 @@ func (c *AddressController) Delete(ctx *fiber.Ctx) error {
 
 	return ctx.JSON(model.WebResponse[bool]{Data: true})
 }
+
+// Trash godoc
+// @Summary List trashed addresses
+// @Description List deleted addresses of the contact that can still be restored, the last deleted first
+// @Tags Address API
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param contactId path string true "Contact ID"
+// @Success 200 {object} model.WebResponse[[]model.AddressResponse]
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /api/contacts/{contactId}/addresses/_trash [get]
+func (c *AddressController) Trash(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+
+	request := &model.ListTrashedAddressRequest{
+		UserId:    auth.ID,
+		ContactId: ctx.Params("contactId"),
+	}
+
+	responses, err := c.UseCase.Trash(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Errorw("failed to list trashed addresses", "error", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[[]model.AddressResponse]{Data: responses})
+}
+
+// Restore godoc
+// @Summary Restore address
+// @Description Restore a deleted address from the trash
+// @Tags Address API
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param contactId path string true "Contact ID"
+// @Param addressId path string true "Address ID"
+// @Success 200 {object} model.WebResponse[model.AddressResponse]
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /api/contacts/{contactId}/addresses/{addressId}/_restore [post]
+func (c *AddressController) Restore(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+
+	request := &model.RestoreAddressRequest{
+		UserId:    auth.ID,
+		ContactId: ctx.Params("contactId"),
+		ID:        ctx.Params("addressId"),
+	}
+
+	response, err := c.UseCase.Restore(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Errorw("failed to restore address", "error", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[*model.AddressResponse]{Data: response})
+}
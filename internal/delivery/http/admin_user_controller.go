@@ -0,0 +1,248 @@
+package http
+
+import (
+	"math"
+
+	"go-clean-template/internal/delivery/http/middleware"
+	"go-clean-template/internal/model"
+	"go-clean-template/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+type AdminUserController struct {
+	Log     *zap.SugaredLogger
+	UseCase *usecase.AdminUserUseCase
+}
+
+func NewAdminUserController(useCase *usecase.AdminUserUseCase, logger *zap.SugaredLogger) *AdminUserController {
+	return &AdminUserController{
+		Log:     logger,
+		UseCase: useCase,
+	}
+}
+
+// List godoc
+// @Summary List users
+// @Description Search users, requires users:read
+// @Tags Admin API
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id query string false "User ID"
+// @Param name query string false "Name"
+// @Param email query string false "Email"
+// @Param disabled query bool false "Only disabled or only enabled users"
+// @Param page query int false "Page"
+// @Param size query int false "Size"
+// @Success 200 {object} model.PageResponse[model.UserResponse]
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /api/admin/users [get]
+func (c *AdminUserController) List(ctx *fiber.Ctx) error {
+	request := &model.SearchUserRequest{
+		ID:    ctx.Query("id", ""),
+		Name:  ctx.Query("name", ""),
+		Email: ctx.Query("email", ""),
+		Page:  ctx.QueryInt("page", 1),
+		Size:  ctx.QueryInt("size", 10),
+	}
+
+	if ctx.Query("disabled") != "" {
+		disabled := ctx.QueryBool("disabled")
+		request.Disabled = &disabled
+	}
+
+	responses, total, err := c.UseCase.Search(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Errorw("Failed to search users", "error", err)
+		return err
+	}
+
+	paging := model.PageMetadata{
+		Page:      request.Page,
+		Size:      request.Size,
+		TotalItem: total,
+		TotalPage: int64(math.Ceil(float64(total) / float64(request.Size))),
+	}
+
+	return ctx.JSON(model.PageResponse[model.UserResponse]{
+		Data:   responses,
+		Paging: paging,
+	})
+}
+
+// Get godoc
+// @Summary Get user
+// @Description Get a user by id, requires users:read
+// @Tags Admin API
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param userId path string true "User ID"
+// @Success 200 {object} model.WebResponse[model.UserResponse]
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /api/admin/users/{userId} [get]
+func (c *AdminUserController) Get(ctx *fiber.Ctx) error {
+	request := &model.GetAdminUserRequest{
+		ID: ctx.Params("userId"),
+	}
+
+	response, err := c.UseCase.Get(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Errorw("Failed to get user", "error", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[*model.UserResponse]{Data: response})
+}
+
+// Disable godoc
+// @Summary Disable user
+// @Description Block a user from logging in and revoke their sessions, requires users:write
+// @Tags Admin API
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param userId path string true "User ID"
+// @Success 200 {object} model.WebResponse[model.UserResponse]
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /api/admin/users/{userId}/_disable [post]
+func (c *AdminUserController) Disable(ctx *fiber.Ctx) error {
+	request := &model.DisableUserRequest{
+		Actor: middleware.GetUser(ctx),
+		ID:    ctx.Params("userId"),
+	}
+
+	response, err := c.UseCase.Disable(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Errorw("Failed to disable user", "error", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[*model.UserResponse]{Data: response})
+}
+
+// Enable godoc
+// @Summary Enable user
+// @Description Allow a disabled user to log in again, requires users:write
+// @Tags Admin API
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param userId path string true "User ID"
+// @Success 200 {object} model.WebResponse[model.UserResponse]
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /api/admin/users/{userId}/_enable [post]
+func (c *AdminUserController) Enable(ctx *fiber.Ctx) error {
+	request := &model.EnableUserRequest{
+		Actor: middleware.GetUser(ctx),
+		ID:    ctx.Params("userId"),
+	}
+
+	response, err := c.UseCase.Enable(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Errorw("Failed to enable user", "error", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[*model.UserResponse]{Data: response})
+}
+
+// Logout godoc
+// @Summary Force logout user
+// @Description Revoke every session of a user, requires users:write
+// @Tags Admin API
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param userId path string true "User ID"
+// @Success 200 {object} model.WebResponse[bool]
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /api/admin/users/{userId}/_logout [post]
+func (c *AdminUserController) Logout(ctx *fiber.Ctx) error {
+	request := &model.ForceLogoutUserRequest{
+		Actor: middleware.GetUser(ctx),
+		ID:    ctx.Params("userId"),
+	}
+
+	response, err := c.UseCase.Logout(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Errorw("Failed to logout user", "error", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[bool]{Data: response})
+}
+
+// ResetPassword godoc
+// @Summary Reset user password
+// @Description Send a password reset link to the user's email, requires users:write
+// @Tags Admin API
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param userId path string true "User ID"
+// @Success 200 {object} model.WebResponse[bool]
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 422 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /api/admin/users/{userId}/_reset-password [post]
+func (c *AdminUserController) ResetPassword(ctx *fiber.Ctx) error {
+	request := &model.AdminResetPasswordRequest{
+		Actor: middleware.GetUser(ctx),
+		ID:    ctx.Params("userId"),
+	}
+
+	response, err := c.UseCase.ResetPassword(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Errorw("Failed to reset user password", "error", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[bool]{Data: response})
+}
+
+// Impersonate godoc
+// @Summary Impersonate user
+// @Description Issue a short-lived access token to act as the user, requires users:impersonate. The token can only read the account and every request made with it is audited
+// @Tags Admin API
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param userId path string true "User ID"
+// @Success 200 {object} model.WebResponse[model.UserResponse]
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /api/admin/users/{userId}/_impersonate [post]
+func (c *AdminUserController) Impersonate(ctx *fiber.Ctx) error {
+	userAgent, ipAddress := clientInfo(ctx)
+	request := &model.ImpersonateUserRequest{
+		Actor:     middleware.GetUser(ctx),
+		ID:        ctx.Params("userId"),
+		UserAgent: userAgent,
+		IPAddress: ipAddress,
+	}
+
+	response, err := c.UseCase.Impersonate(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Errorw("Failed to impersonate user", "error", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[*model.UserResponse]{Data: response})
+}
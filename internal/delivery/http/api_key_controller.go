@@ -0,0 +1,111 @@
+package http
+
+import (
+	"go-clean-template/internal/delivery/http/middleware"
+	"go-clean-template/internal/model"
+	"go-clean-template/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+type ApiKeyController struct {
+	Log     *zap.SugaredLogger
+	UseCase *usecase.ApiKeyUseCase
+}
+
+func NewApiKeyController(useCase *usecase.ApiKeyUseCase, logger *zap.SugaredLogger) *ApiKeyController {
+	return &ApiKeyController{
+		Log:     logger,
+		UseCase: useCase,
+	}
+}
+
+// Create godoc
+// @Summary Create API key
+// @Description Create an API key for machine clients, the key is only returned once
+// @Tags API Key API
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body model.CreateApiKeyRequest true "Create API Key Request"
+// @Success 200 {object} model.WebResponse[model.ApiKeyResponse]
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /api/users/_current/api-keys [post]
+func (c *ApiKeyController) Create(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+
+	request := new(model.CreateApiKeyRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.Warnf("Failed to parse request body : %+v", err)
+		return fiber.ErrBadRequest
+	}
+	request.UserId = auth.ID
+
+	response, err := c.UseCase.Create(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Errorw("Failed to create API key", "error", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[*model.ApiKeyResponse]{Data: response})
+}
+
+// List godoc
+// @Summary List API keys
+// @Description List the API keys of the current user
+// @Tags API Key API
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} model.WebResponse[[]model.ApiKeyResponse]
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /api/users/_current/api-keys [get]
+func (c *ApiKeyController) List(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+
+	request := &model.ListApiKeyRequest{
+		UserId: auth.ID,
+	}
+
+	responses, err := c.UseCase.List(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Errorw("Failed to list API keys", "error", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[[]model.ApiKeyResponse]{Data: responses})
+}
+
+// Delete godoc
+// @Summary Delete API key
+// @Description Delete an API key of the current user, clients using it are rejected right away
+// @Tags API Key API
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param apiKeyId path string true "API Key ID"
+// @Success 200 {object} model.WebResponse[bool]
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /api/users/_current/api-keys/{apiKeyId} [delete]
+func (c *ApiKeyController) Delete(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+
+	request := &model.DeleteApiKeyRequest{
+		UserId: auth.ID,
+		ID:     ctx.Params("apiKeyId"),
+	}
+
+	if err := c.UseCase.Delete(ctx.UserContext(), request); err != nil {
+		c.Log.Errorw("Failed to delete API key", "error", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[bool]{Data: true})
+}
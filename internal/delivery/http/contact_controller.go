@@ -1,7 +1,7 @@
 package http
 
 import (
-	"math"
+	"strings"
 
 	"go-clean-template/internal/delivery/http/middleware"
 	"go-clean-template/internal/model"
@@ -56,16 +56,22 @@ func (c *ContactController) Create(ctx *fiber.Ctx) error {
 
 // List godoc
 // @Summary List contacts
-// @Description List contacts
+// @Description List contacts, with q the contacts matching it best come first
 // @Tags Contact API
 // @Accept json
 // @Produce json
 // @Security ApiKeyAuth
+// @Param q query string false "Search first and last name, email and phone, tolerating typos"
 // @Param name query string false "Name"
 // @Param email query string false "Email"
 // @Param phone query string false "Phone"
 // @Param page query int false "Page"
 // @Param size query int false "Size"
+// @Param sort query string false "Comma separated keys to sort by, - sorts descending, e.g. -updated_at,last_name"
+// @Param fields query string false "Comma separated fields to return, e.g. first_name,email"
+// @Param after query string false "Cursor from paging.next, returns the page after it in place of page"
+// @Param before query string false "Cursor from paging.previous, returns the page before it in place of page"
+// @Param total query bool false "Count total_item and total_page, by default only without a cursor"
 // @Success 200 {object} model.WebResponse[[]model.ContactResponse]
 // @Failure 400 {object} model.ErrorResponse
 // @Failure 500 {object} model.ErrorResponse
@@ -75,32 +81,55 @@ func (c *ContactController) List(ctx *fiber.Ctx) error {
 
 	request := &model.SearchContactRequest{
 		UserId: auth.ID,
+		Query:  ctx.Query("q", ""),
 		Name:   ctx.Query("name", ""),
 		Email:  ctx.Query("email", ""),
 		Phone:  ctx.Query("phone", ""),
 		Page:   ctx.QueryInt("page", 1),
 		Size:   ctx.QueryInt("size", 10),
+		Sort:   queryList(ctx, "sort"),
+		Fields: queryList(ctx, "fields"),
+		After:  ctx.Query("after", ""),
+		Before: ctx.Query("before", ""),
 	}
+	// counting is skipped by default when paging with cursors, which are meant for long lists
+	request.CountTotal = ctx.QueryBool("total", request.After == "" && request.Before == "")
 
-	responses, total, err := c.UseCase.Search(ctx.UserContext(), request)
+	responses, paging, err := c.UseCase.Search(ctx.UserContext(), request)
 	if err != nil {
 		c.Log.Errorw("error searching contact", "error", err)
 		return err
 	}
 
-	paging := model.PageMetadata{
-		Page:      request.Page,
-		Size:      request.Size,
-		TotalItem: total,
-		TotalPage: int64(math.Ceil(float64(total) / float64(request.Size))),
+	if len(request.Fields) > 0 {
+		trimmed := make([]map[string]any, len(responses))
+		for i, response := range responses {
+			trimmed[i] = response.Trim(request.Fields)
+		}
+
+		return ctx.JSON(model.PageResponse[map[string]any]{
+			Data:   trimmed,
+			Paging: *paging,
+		})
 	}
 
 	return ctx.JSON(model.PageResponse[model.ContactResponse]{
 		Data:   responses,
-		Paging: paging,
+		Paging: *paging,
 	})
 }
 
+// queryList splits a comma separated query parameter, a missing or empty parameter gives nil
+func queryList(ctx *fiber.Ctx, key string) []string {
+	var values []string
+	for _, value := range strings.Split(ctx.Query(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
 // Get godoc
 // @Summary Get contact
 // @Description Get contact
@@ -192,3 +221,67 @@ func (c *ContactController) Delete(ctx *fiber.Ctx) error {
 
 	return ctx.JSON(model.WebResponse[bool]{Data: true})
 }
+
+// Trash godoc
+// @Summary List trashed contacts
+// @Description List deleted contacts that can still be restored, the last deleted first
+// @Tags Contact API
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param page query int false "Page"
+// @Param size query int false "Size"
+// @Success 200 {object} model.PageResponse[model.ContactResponse]
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /api/contacts/_trash [get]
+func (c *ContactController) Trash(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+
+	request := &model.SearchTrashedContactRequest{
+		UserId: auth.ID,
+		Page:   ctx.QueryInt("page", 1),
+		Size:   ctx.QueryInt("size", 10),
+	}
+
+	responses, paging, err := c.UseCase.Trash(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Errorw("error listing trashed contacts", "error", err)
+		return err
+	}
+
+	return ctx.JSON(model.PageResponse[model.ContactResponse]{
+		Data:   responses,
+		Paging: *paging,
+	})
+}
+
+// Restore godoc
+// @Summary Restore contact
+// @Description Restore a deleted contact from the trash
+// @Tags Contact API
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param contactId path string true "Contact ID"
+// @Success 200 {object} model.WebResponse[model.ContactResponse]
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /api/contacts/{contactId}/_restore [post]
+func (c *ContactController) Restore(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+
+	request := &model.RestoreContactRequest{
+		UserId: auth.ID,
+		ID:     ctx.Params("contactId"),
+	}
+
+	response, err := c.UseCase.Restore(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Errorw("error restoring contact", "error", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[*model.ContactResponse]{Data: response})
+}
@@ -0,0 +1,108 @@
+package http
+
+import (
+	"go-clean-template/internal/delivery/http/middleware"
+	"go-clean-template/internal/model"
+	"go-clean-template/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+type DataExportController struct {
+	Log     *zap.SugaredLogger
+	UseCase *usecase.DataExportUseCase
+}
+
+func NewDataExportController(useCase *usecase.DataExportUseCase, logger *zap.SugaredLogger) *DataExportController {
+	return &DataExportController{
+		Log:     logger,
+		UseCase: useCase,
+	}
+}
+
+// Create godoc
+// @Summary Request data export
+// @Description Queue an export of everything stored about the current user, the user is notified when it is ready
+// @Tags Data Export API
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} model.WebResponse[model.DataExportResponse]
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /api/users/_current/export [post]
+func (c *DataExportController) Create(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+
+	request := &model.CreateDataExportRequest{UserId: auth.ID}
+	request.UserAgent, request.IPAddress = clientInfo(ctx)
+
+	response, err := c.UseCase.Create(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Errorw("Failed to create data export", "error", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[*model.DataExportResponse]{Data: response})
+}
+
+// Get godoc
+// @Summary Get data export
+// @Description Get the status of a data export
+// @Tags Data Export API
+// @Produce json
+// @Security ApiKeyAuth
+// @Param exportId path string true "Data Export ID"
+// @Success 200 {object} model.WebResponse[model.DataExportResponse]
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /api/users/_current/exports/{exportId} [get]
+func (c *DataExportController) Get(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+
+	request := &model.GetDataExportRequest{
+		UserId: auth.ID,
+		ID:     ctx.Params("exportId"),
+	}
+
+	response, err := c.UseCase.Get(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Errorw("Failed to get data export", "error", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[*model.DataExportResponse]{Data: response})
+}
+
+// Download godoc
+// @Summary Download data export
+// @Description Download the zip archive of a data export with the profile, contacts and addresses (JSON and vCard), sessions and audit log
+// @Tags Data Export API
+// @Produce application/zip
+// @Security ApiKeyAuth
+// @Param exportId path string true "Data Export ID"
+// @Success 200 {file} file
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Failure 410 {object} model.ErrorResponse
+// @Router /api/users/_current/exports/{exportId}/download [get]
+func (c *DataExportController) Download(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+
+	request := &model.GetDataExportRequest{
+		UserId: auth.ID,
+		ID:     ctx.Params("exportId"),
+	}
+
+	archive, err := c.UseCase.Download(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Errorw("Failed to download data export", "error", err)
+		return err
+	}
+
+	ctx.Attachment("data-export-" + request.ID + ".zip")
+	ctx.Set(fiber.HeaderContentType, "application/zip")
+	return ctx.Send(archive)
+}
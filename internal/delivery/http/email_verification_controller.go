@@ -0,0 +1,76 @@
+package http
+
+import (
+	"go-clean-template/internal/model"
+	"go-clean-template/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+type EmailVerificationController struct {
+	Log     *zap.SugaredLogger
+	UseCase *usecase.EmailVerificationUseCase
+}
+
+func NewEmailVerificationController(useCase *usecase.EmailVerificationUseCase, logger *zap.SugaredLogger) *EmailVerificationController {
+	return &EmailVerificationController{
+		Log:     logger,
+		UseCase: useCase,
+	}
+}
+
+// Verify godoc
+// @Summary Verify email
+// @Description Mark the user's email as verified using a verification token
+// @Tags User API
+// @Accept json
+// @Produce json
+// @Param request body model.VerifyEmailRequest true "Verify Email Request"
+// @Success 200 {object} model.WebResponse[bool]
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /api/users/_verify-email [post]
+func (c *EmailVerificationController) Verify(ctx *fiber.Ctx) error {
+	request := new(model.VerifyEmailRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.Warnf("Failed to parse request body : %+v", err)
+		return fiber.ErrBadRequest
+	}
+
+	response, err := c.UseCase.Verify(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to verify email : %+v", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[bool]{Data: response})
+}
+
+// Resend godoc
+// @Summary Resend email verification
+// @Description Send a new verification link to an unverified email
+// @Tags User API
+// @Accept json
+// @Produce json
+// @Param request body model.ResendVerificationRequest true "Resend Verification Request"
+// @Success 200 {object} model.WebResponse[bool]
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 429 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /api/users/_resend-verification [post]
+func (c *EmailVerificationController) Resend(ctx *fiber.Ctx) error {
+	request := new(model.ResendVerificationRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.Warnf("Failed to parse request body : %+v", err)
+		return fiber.ErrBadRequest
+	}
+
+	response, err := c.UseCase.Resend(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to resend email verification : %+v", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[bool]{Data: response})
+}
@@ -0,0 +1,50 @@
+package http
+
+import (
+	"go-clean-template/internal/model"
+	"go-clean-template/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+type MagicLinkController struct {
+	Log     *zap.SugaredLogger
+	UseCase *usecase.MagicLinkUseCase
+}
+
+func NewMagicLinkController(useCase *usecase.MagicLinkUseCase, logger *zap.SugaredLogger) *MagicLinkController {
+	return &MagicLinkController{
+		Log:     logger,
+		UseCase: useCase,
+	}
+}
+
+// Request godoc
+// @Summary Request magic link
+// @Description Email a single-use login link to the user, keep the returned device_token to redeem it
+// @Tags User API
+// @Accept json
+// @Produce json
+// @Param request body model.RequestMagicLinkRequest true "Request Magic Link Request"
+// @Success 200 {object} model.WebResponse[model.MagicLinkResponse]
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 429 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /api/users/_magic-link [post]
+func (c *MagicLinkController) Request(ctx *fiber.Ctx) error {
+	request := new(model.RequestMagicLinkRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.Warnf("Failed to parse request body : %+v", err)
+		return fiber.ErrBadRequest
+	}
+
+	_, request.IPAddress = clientInfo(ctx)
+	response, err := c.UseCase.Request(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to request magic link : %+v", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[*model.MagicLinkResponse]{Data: response})
+}
@@ -0,0 +1,148 @@
+package http
+
+import (
+	"go-clean-template/internal/delivery/http/middleware"
+	"go-clean-template/internal/model"
+	"go-clean-template/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+type MfaController struct {
+	Log     *zap.SugaredLogger
+	UseCase *usecase.MfaUseCase
+}
+
+func NewMfaController(useCase *usecase.MfaUseCase, logger *zap.SugaredLogger) *MfaController {
+	return &MfaController{
+		Log:     logger,
+		UseCase: useCase,
+	}
+}
+
+// Enroll godoc
+// @Summary Enroll TOTP
+// @Description Generate a TOTP secret and otpauth URI for the current user, it is enabled once confirmed
+// @Tags Two-Factor API
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} model.WebResponse[model.TotpEnrollmentResponse]
+// @Failure 409 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /api/users/_current/mfa/totp [post]
+func (c *MfaController) Enroll(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+
+	request := &model.EnrollTotpRequest{
+		ID: auth.ID,
+	}
+
+	response, err := c.UseCase.Enroll(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Errorw("Failed to enroll totp", "error", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[*model.TotpEnrollmentResponse]{Data: response})
+}
+
+// Confirm godoc
+// @Summary Confirm TOTP
+// @Description Enable TOTP with a first code from the authenticator and return the recovery codes
+// @Tags Two-Factor API
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body model.ConfirmTotpRequest true "Confirm Totp Request"
+// @Success 200 {object} model.WebResponse[model.RecoveryCodesResponse]
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /api/users/_current/mfa/totp/_confirm [post]
+func (c *MfaController) Confirm(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+
+	request := new(model.ConfirmTotpRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.Warnf("Failed to parse request body : %+v", err)
+		return fiber.ErrBadRequest
+	}
+
+	request.ID = auth.ID
+	response, err := c.UseCase.Confirm(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Errorw("Failed to confirm totp", "error", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[*model.RecoveryCodesResponse]{Data: response})
+}
+
+// Disable godoc
+// @Summary Disable TOTP
+// @Description Turn off two-factor authentication, requires a TOTP or recovery code
+// @Tags Two-Factor API
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body model.DisableTotpRequest true "Disable Totp Request"
+// @Success 200 {object} model.WebResponse[bool]
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /api/users/_current/mfa/totp [delete]
+func (c *MfaController) Disable(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+
+	request := new(model.DisableTotpRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.Warnf("Failed to parse request body : %+v", err)
+		return fiber.ErrBadRequest
+	}
+
+	request.ID = auth.ID
+	_, request.IPAddress = clientInfo(ctx)
+	response, err := c.UseCase.Disable(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Errorw("Failed to disable totp", "error", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[bool]{Data: response})
+}
+
+// RegenerateRecoveryCodes godoc
+// @Summary Regenerate recovery codes
+// @Description Replace all recovery codes, requires a TOTP or recovery code
+// @Tags Two-Factor API
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body model.RegenerateRecoveryCodesRequest true "Regenerate Recovery Codes Request"
+// @Success 200 {object} model.WebResponse[model.RecoveryCodesResponse]
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /api/users/_current/mfa/recovery-codes [post]
+func (c *MfaController) RegenerateRecoveryCodes(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+
+	request := new(model.RegenerateRecoveryCodesRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.Warnf("Failed to parse request body : %+v", err)
+		return fiber.ErrBadRequest
+	}
+
+	request.ID = auth.ID
+	_, request.IPAddress = clientInfo(ctx)
+	response, err := c.UseCase.RegenerateRecoveryCodes(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Errorw("Failed to regenerate recovery codes", "error", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[*model.RecoveryCodesResponse]{Data: response})
+}
@@ -1,23 +1,51 @@
 package middleware
 
 import (
+	"errors"
+	"strconv"
+	"time"
+
 	"go-clean-template/internal/model"
+	"go-clean-template/internal/security"
 	"go-clean-template/internal/usecase"
 
 	"github.com/gofiber/fiber/v2"
 )
 
-func NewAuth(userUserCase *usecase.UserUseCase) fiber.Handler {
+func NewAuth(userUserCase *usecase.UserUseCase, apiKeyUseCase *usecase.ApiKeyUseCase) fiber.Handler {
 	return func(ctx *fiber.Ctx) error {
 		request := &model.VerifyUserRequest{Token: ctx.Get("Authorization", "NOT_FOUND")}
-		userUserCase.Log.Debugf("Authorization : %s", request.Token)
 
-		auth, err := userUserCase.Verify(ctx.UserContext(), request)
+		var auth *model.Auth
+		var err error
+		if security.IsAPIKey(request.Token) {
+			auth, err = apiKeyUseCase.Verify(ctx.UserContext(), request)
+		} else {
+			userUserCase.Log.Debugf("Authorization : %s", request.Token)
+			auth, err = userUserCase.Verify(ctx.UserContext(), request)
+		}
 		if err != nil {
 			userUserCase.Log.Warnf("Failed find user by token : %+v", err)
+			if errors.Is(err, usecase.ErrAccessTokenExpired) || errors.Is(err, usecase.ErrSessionExpired) ||
+				errors.Is(err, usecase.ErrUserDisabled) || errors.Is(err, usecase.ErrApiKeyExpired) {
+				return err
+			}
 			return fiber.ErrUnauthorized
 		}
 
+		// sliding renewal, active clients get a fresh access token before the current one runs out
+		if auth.SessionID != "" && userUserCase.TokenProvider.NeedsRenewal(auth.ExpiresAt, time.Now()) {
+			renewRequest := &model.RenewUserRequest{ID: auth.ID, SessionId: auth.SessionID}
+			response, err := userUserCase.Renew(ctx.UserContext(), renewRequest)
+			if err != nil {
+				userUserCase.Log.Warnf("Failed renew access token : %+v", err)
+				return err
+			}
+
+			ctx.Set("X-Access-Token", response.Token)
+			ctx.Set("X-Access-Token-Expires-At", strconv.FormatInt(response.ExpiresAt, 10))
+		}
+
 		userUserCase.Log.Debugf("User : %+v", auth.ID)
 		ctx.Locals("auth", auth)
 		return ctx.Next()
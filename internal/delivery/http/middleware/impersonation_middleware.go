@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"go-clean-template/internal/model"
+	"go-clean-template/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// NewImpersonationAudit records every request made while impersonating in the audit log of the impersonated
+// user, a request that can not be recorded is refused, it has to run after NewAuth
+func NewImpersonationAudit(auditLogUseCase *usecase.AuditLogUseCase) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		auth := GetUser(ctx)
+		if !auth.Impersonated() {
+			return ctx.Next()
+		}
+
+		userAgent := ctx.Get(fiber.HeaderUserAgent)
+		if len(userAgent) > 255 {
+			userAgent = userAgent[:255]
+		}
+		detail := ctx.Method() + " " + ctx.OriginalURL()
+		if len(detail) > 255 {
+			detail = detail[:255]
+		}
+
+		request := &model.RecordAuditLogRequest{
+			UserId:    auth.ID,
+			ActorId:   auth.ActorID,
+			Action:    model.AuditImpersonatedCall,
+			Detail:    detail,
+			UserAgent: userAgent,
+			IPAddress: ctx.IP(),
+		}
+		if err := auditLogUseCase.Record(ctx.UserContext(), request); err != nil {
+			auditLogUseCase.Log.Warnf("Failed record impersonated request : %+v", err)
+			return fiber.ErrInternalServerError
+		}
+
+		return ctx.Next()
+	}
+}
+
+// NewImpersonationGuard only lets impersonated requests read, every change to the account and its data
+// stays with its owner, it has to run after NewAuth on every authenticated route
+func NewImpersonationGuard() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		if GetUser(ctx).Impersonated() && ctx.Method() != fiber.MethodGet && ctx.Method() != fiber.MethodHead {
+			return usecase.ErrImpersonationForbidden
+		}
+		return ctx.Next()
+	}
+}
+
+// NewOwnerOnly keeps impersonated requests away from routes that only the owner may use, reads included, such as
+// the data export that hands out every session and the whole audit log, it has to run after NewAuth
+func NewOwnerOnly() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		if GetUser(ctx).Impersonated() {
+			return usecase.ErrImpersonationForbidden
+		}
+		return ctx.Next()
+	}
+}
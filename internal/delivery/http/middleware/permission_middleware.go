@@ -0,0 +1,19 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// NewPermission only lets requests through whose access token carries every listed permission,
+// it has to run after NewAuth
+func NewPermission(permissions ...string) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		auth := GetUser(ctx)
+		for _, permission := range permissions {
+			if !auth.HasPermission(permission) {
+				return fiber.ErrForbidden
+			}
+		}
+		return ctx.Next()
+	}
+}
@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// NewScope only lets API keys through that carry every listed scope, access tokens are not limited by scopes,
+// it has to run after NewAuth
+func NewScope(scopes ...string) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		auth := GetUser(ctx)
+		for _, scope := range scopes {
+			if !auth.HasScope(scope) {
+				return fiber.ErrForbidden
+			}
+		}
+		return ctx.Next()
+	}
+}
+
+// NewSessionOnly keeps API keys away from routes that manage the account itself, it has to run after NewAuth
+func NewSessionOnly() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		if GetUser(ctx).ApiKeyID != "" {
+			return fiber.ErrForbidden
+		}
+		return ctx.Next()
+	}
+}
@@ -0,0 +1,75 @@
+package http
+
+import (
+	"go-clean-template/internal/model"
+	"go-clean-template/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+type OidcController struct {
+	Log     *zap.SugaredLogger
+	UseCase *usecase.OidcUseCase
+}
+
+func NewOidcController(useCase *usecase.OidcUseCase, logger *zap.SugaredLogger) *OidcController {
+	return &OidcController{
+		Log:     logger,
+		UseCase: useCase,
+	}
+}
+
+// Authorize godoc
+// @Summary Start single sign-on
+// @Description Start an OpenID Connect login, send the user agent to the returned URL
+// @Tags User API
+// @Accept json
+// @Produce json
+// @Success 200 {object} model.WebResponse[model.OidcAuthorizationResponse]
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Failure 502 {object} model.ErrorResponse
+// @Router /api/users/_oidc/authorize [post]
+func (c *OidcController) Authorize(ctx *fiber.Ctx) error {
+	response, err := c.UseCase.Authorize(ctx.UserContext())
+	if err != nil {
+		c.Log.Warnf("Failed to start oidc login : %+v", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[*model.OidcAuthorizationResponse]{Data: response})
+}
+
+// Callback godoc
+// @Summary Complete single sign-on
+// @Description Exchange the code and state the issuer redirected back with for a session
+// @Tags User API
+// @Accept json
+// @Produce json
+// @Param request body model.OidcCallbackRequest true "OIDC Callback Request"
+// @Success 200 {object} model.WebResponse[model.UserResponse]
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /api/users/_oidc/callback [post]
+func (c *OidcController) Callback(ctx *fiber.Ctx) error {
+	request := new(model.OidcCallbackRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.Warnf("Failed to parse request body : %+v", err)
+		return fiber.ErrBadRequest
+	}
+
+	request.UserAgent, request.IPAddress = clientInfo(ctx)
+
+	response, err := c.UseCase.Callback(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to complete oidc login : %+v", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[*model.UserResponse]{Data: response})
+}
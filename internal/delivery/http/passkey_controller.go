@@ -0,0 +1,203 @@
+package http
+
+import (
+	"go-clean-template/internal/delivery/http/middleware"
+	"go-clean-template/internal/model"
+	"go-clean-template/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+type PasskeyController struct {
+	Log     *zap.SugaredLogger
+	UseCase *usecase.PasskeyUseCase
+}
+
+func NewPasskeyController(useCase *usecase.PasskeyUseCase, logger *zap.SugaredLogger) *PasskeyController {
+	return &PasskeyController{
+		Log:     logger,
+		UseCase: useCase,
+	}
+}
+
+// BeginRegistration godoc
+// @Summary Begin passkey registration
+// @Description Get the options to pass to navigator.credentials.create to add a passkey to the current user
+// @Tags Passkey API
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} model.WebResponse[model.PasskeyCreationOptions]
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /api/users/_current/passkeys/_begin [post]
+func (c *PasskeyController) BeginRegistration(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+
+	request := &model.BeginPasskeyRegistrationRequest{
+		UserId: auth.ID,
+	}
+
+	response, err := c.UseCase.BeginRegistration(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to begin passkey registration : %+v", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[*model.PasskeyCreationOptions]{Data: response})
+}
+
+// FinishRegistration godoc
+// @Summary Register passkey
+// @Description Store the credential created by navigator.credentials.create as a passkey of the current user
+// @Tags Passkey API
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body model.FinishPasskeyRegistrationRequest true "Finish Passkey Registration Request"
+// @Success 200 {object} model.WebResponse[model.PasskeyResponse]
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /api/users/_current/passkeys [post]
+func (c *PasskeyController) FinishRegistration(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+
+	request := new(model.FinishPasskeyRegistrationRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.Warnf("Failed to parse request body : %+v", err)
+		return fiber.ErrBadRequest
+	}
+	request.UserId = auth.ID
+	request.UserAgent, request.IPAddress = clientInfo(ctx)
+
+	response, err := c.UseCase.FinishRegistration(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to register passkey : %+v", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[*model.PasskeyResponse]{Data: response})
+}
+
+// List godoc
+// @Summary List passkeys
+// @Description List the passkeys of the current user
+// @Tags Passkey API
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} model.WebResponse[[]model.PasskeyResponse]
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /api/users/_current/passkeys [get]
+func (c *PasskeyController) List(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+
+	request := &model.ListPasskeyRequest{
+		UserId: auth.ID,
+	}
+
+	responses, err := c.UseCase.List(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to list passkeys : %+v", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[[]model.PasskeyResponse]{Data: responses})
+}
+
+// Delete godoc
+// @Summary Delete passkey
+// @Description Delete a passkey of the current user, it can no longer be used to log in
+// @Tags Passkey API
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param passkeyId path string true "Passkey ID"
+// @Success 200 {object} model.WebResponse[bool]
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /api/users/_current/passkeys/{passkeyId} [delete]
+func (c *PasskeyController) Delete(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+
+	request := &model.DeletePasskeyRequest{
+		UserId: auth.ID,
+		ID:     ctx.Params("passkeyId"),
+	}
+	request.UserAgent, request.IPAddress = clientInfo(ctx)
+
+	if err := c.UseCase.Delete(ctx.UserContext(), request); err != nil {
+		c.Log.Warnf("Failed to delete passkey : %+v", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[bool]{Data: true})
+}
+
+// BeginLogin godoc
+// @Summary Begin passkey login
+// @Description Get the options to pass to navigator.credentials.get, without an id any passkey of the site can answer
+// @Tags User API
+// @Accept json
+// @Produce json
+// @Param request body model.BeginPasskeyLoginRequest false "Begin Passkey Login Request"
+// @Success 200 {object} model.WebResponse[model.PasskeyRequestOptions]
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /api/users/_login/passkey/_begin [post]
+func (c *PasskeyController) BeginLogin(ctx *fiber.Ctx) error {
+	request := new(model.BeginPasskeyLoginRequest)
+	if len(ctx.Body()) > 0 {
+		if err := ctx.BodyParser(request); err != nil {
+			c.Log.Warnf("Failed to parse request body : %+v", err)
+			return fiber.ErrBadRequest
+		}
+	}
+
+	response, err := c.UseCase.BeginLogin(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to begin passkey login : %+v", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[*model.PasskeyRequestOptions]{Data: response})
+}
+
+// Login godoc
+// @Summary Login with a passkey
+// @Description Exchange the credential returned by navigator.credentials.get for an access and refresh token pair
+// @Tags User API
+// @Accept json
+// @Produce json
+// @Param request body model.LoginPasskeyRequest true "Login Passkey Request"
+// @Success 200 {object} model.WebResponse[model.UserResponse]
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /api/users/_login/passkey [post]
+func (c *PasskeyController) Login(ctx *fiber.Ctx) error {
+	request := new(model.LoginPasskeyRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.Warnf("Failed to parse request body : %+v", err)
+		return fiber.ErrBadRequest
+	}
+
+	request.UserAgent, request.IPAddress = clientInfo(ctx)
+
+	response, err := c.UseCase.Login(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to login with passkey : %+v", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[*model.UserResponse]{Data: response})
+}
@@ -0,0 +1,75 @@
+package http
+
+import (
+	"go-clean-template/internal/model"
+	"go-clean-template/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+type PasswordResetController struct {
+	Log     *zap.SugaredLogger
+	UseCase *usecase.PasswordResetUseCase
+}
+
+func NewPasswordResetController(useCase *usecase.PasswordResetUseCase, logger *zap.SugaredLogger) *PasswordResetController {
+	return &PasswordResetController{
+		Log:     logger,
+		UseCase: useCase,
+	}
+}
+
+// Forgot godoc
+// @Summary Forgot password
+// @Description Send a password reset link to the user
+// @Tags User API
+// @Accept json
+// @Produce json
+// @Param request body model.ForgotPasswordRequest true "Forgot Password Request"
+// @Success 200 {object} model.WebResponse[bool]
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /api/users/_forgot-password [post]
+func (c *PasswordResetController) Forgot(ctx *fiber.Ctx) error {
+	request := new(model.ForgotPasswordRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.Warnf("Failed to parse request body : %+v", err)
+		return fiber.ErrBadRequest
+	}
+
+	response, err := c.UseCase.Forgot(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to request password reset : %+v", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[bool]{Data: response})
+}
+
+// Reset godoc
+// @Summary Reset password
+// @Description Set a new password using a password reset token
+// @Tags User API
+// @Accept json
+// @Produce json
+// @Param request body model.ResetPasswordRequest true "Reset Password Request"
+// @Success 200 {object} model.WebResponse[bool]
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /api/users/_reset-password [post]
+func (c *PasswordResetController) Reset(ctx *fiber.Ctx) error {
+	request := new(model.ResetPasswordRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.Warnf("Failed to parse request body : %+v", err)
+		return fiber.ErrBadRequest
+	}
+
+	response, err := c.UseCase.Reset(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to reset password : %+v", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[bool]{Data: response})
+}
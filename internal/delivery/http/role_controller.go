@@ -0,0 +1,134 @@
+package http
+
+import (
+	"go-clean-template/internal/delivery/http/middleware"
+	"go-clean-template/internal/model"
+	"go-clean-template/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+type RoleController struct {
+	Log     *zap.SugaredLogger
+	UseCase *usecase.RoleUseCase
+}
+
+func NewRoleController(useCase *usecase.RoleUseCase, logger *zap.SugaredLogger) *RoleController {
+	return &RoleController{
+		Log:     logger,
+		UseCase: useCase,
+	}
+}
+
+// List godoc
+// @Summary List roles
+// @Description List all roles and their permissions, requires roles:read
+// @Tags Admin API
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} model.WebResponse[[]model.RoleResponse]
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /api/admin/roles [get]
+func (c *RoleController) List(ctx *fiber.Ctx) error {
+	responses, err := c.UseCase.List(ctx.UserContext())
+	if err != nil {
+		c.Log.Errorw("Failed to list roles", "error", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[[]model.RoleResponse]{Data: responses})
+}
+
+// ListByUser godoc
+// @Summary List user roles
+// @Description List the roles granted to a user, requires roles:read
+// @Tags Admin API
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param userId path string true "User ID"
+// @Success 200 {object} model.WebResponse[[]model.RoleResponse]
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /api/admin/users/{userId}/roles [get]
+func (c *RoleController) ListByUser(ctx *fiber.Ctx) error {
+	request := &model.ListUserRoleRequest{
+		UserId: ctx.Params("userId"),
+	}
+
+	responses, err := c.UseCase.ListByUser(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Errorw("Failed to list user roles", "error", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[[]model.RoleResponse]{Data: responses})
+}
+
+// Grant godoc
+// @Summary Grant role
+// @Description Grant a role to a user, requires roles:write and every permission of the role
+// @Tags Admin API
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param userId path string true "User ID"
+// @Param request body model.GrantRoleRequest true "Grant Role Request"
+// @Success 200 {object} model.WebResponse[bool]
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /api/admin/users/{userId}/roles [post]
+func (c *RoleController) Grant(ctx *fiber.Ctx) error {
+	request := new(model.GrantRoleRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.Warnf("Failed to parse request body : %+v", err)
+		return fiber.ErrBadRequest
+	}
+
+	request.Actor = middleware.GetUser(ctx)
+	request.UserId = ctx.Params("userId")
+
+	response, err := c.UseCase.Grant(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Errorw("Failed to grant role", "error", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[bool]{Data: response})
+}
+
+// Revoke godoc
+// @Summary Revoke role
+// @Description Take a role away from a user, requires roles:write and every permission of the role
+// @Tags Admin API
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param userId path string true "User ID"
+// @Param roleId path string true "Role ID"
+// @Success 200 {object} model.WebResponse[bool]
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /api/admin/users/{userId}/roles/{roleId} [delete]
+func (c *RoleController) Revoke(ctx *fiber.Ctx) error {
+	request := &model.RevokeRoleRequest{
+		Actor:  middleware.GetUser(ctx),
+		UserId: ctx.Params("userId"),
+		RoleId: ctx.Params("roleId"),
+	}
+
+	response, err := c.UseCase.Revoke(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Errorw("Failed to revoke role", "error", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[bool]{Data: response})
+}
@@ -2,6 +2,8 @@ package route
 
 import (
 	"go-clean-template/internal/delivery/http"
+	"go-clean-template/internal/delivery/http/middleware"
+	"go-clean-template/internal/model"
 
 	_ "go-clean-template/docs"
 
@@ -10,21 +12,47 @@ import (
 )
 
 type RouteConfig struct {
-	App               *fiber.App
-	UserController    *http.UserController
-	ContactController *http.ContactController
-	AddressController *http.AddressController
-	AuthMiddleware    fiber.Handler
+	App                         *fiber.App
+	UserController              *http.UserController
+	SessionController           *http.SessionController
+	PasswordResetController     *http.PasswordResetController
+	MagicLinkController         *http.MagicLinkController
+	EmailVerificationController *http.EmailVerificationController
+	MfaController               *http.MfaController
+	OidcController              *http.OidcController
+	PasskeyController           *http.PasskeyController
+	ApiKeyController            *http.ApiKeyController
+	RoleController              *http.RoleController
+	AdminUserController         *http.AdminUserController
+	AccountDeletionController   *http.AccountDeletionController
+	DataExportController        *http.DataExportController
+	ContactController           *http.ContactController
+	AddressController           *http.AddressController
+	AuthMiddleware              fiber.Handler
+	ImpersonationMiddleware     fiber.Handler
 }
 
 func (c *RouteConfig) Setup() {
 	c.SetupGuestRoute()
 	c.SetupAuthRoute()
+	c.SetupAdminRoute()
 }
 
 func (c *RouteConfig) SetupGuestRoute() {
 	c.App.Post("/api/users", c.UserController.Register)
 	c.App.Post("/api/users/_login", c.UserController.Login)
+	c.App.Post("/api/users/_login/mfa", c.UserController.LoginMfa)
+	c.App.Post("/api/users/_login/magic-link", c.UserController.LoginMagicLink)
+	c.App.Post("/api/users/_magic-link", c.MagicLinkController.Request)
+	c.App.Post("/api/users/_login/passkey/_begin", c.PasskeyController.BeginLogin)
+	c.App.Post("/api/users/_login/passkey", c.PasskeyController.Login)
+	c.App.Post("/api/users/_oidc/authorize", c.OidcController.Authorize)
+	c.App.Post("/api/users/_oidc/callback", c.OidcController.Callback)
+	c.App.Post("/api/users/_refresh", c.UserController.Refresh)
+	c.App.Post("/api/users/_forgot-password", c.PasswordResetController.Forgot)
+	c.App.Post("/api/users/_reset-password", c.PasswordResetController.Reset)
+	c.App.Post("/api/users/_verify-email", c.EmailVerificationController.Verify)
+	c.App.Post("/api/users/_resend-verification", c.EmailVerificationController.Resend)
 
 	// Swagger
 	c.App.Get("/swagger/*", swagger.HandlerDefault)
@@ -32,19 +60,68 @@ func (c *RouteConfig) SetupGuestRoute() {
 
 func (c *RouteConfig) SetupAuthRoute() {
 	c.App.Use(c.AuthMiddleware)
+	c.App.Use(c.ImpersonationMiddleware)
+	c.App.Use(middleware.NewImpersonationGuard())
+
+	// API keys only reach the routes that name the scope they need
+	c.App.Use("/api/users", middleware.NewSessionOnly())
 	c.App.Delete("/api/users", c.UserController.Logout)
 	c.App.Patch("/api/users/_current", c.UserController.Update)
 	c.App.Get("/api/users/_current", c.UserController.Current)
+	c.App.Post("/api/users/_current/_change-password", c.UserController.ChangePassword)
+	c.App.Delete("/api/users/_current", c.AccountDeletionController.Delete)
+	c.App.Post("/api/users/_current/_cancel-deletion", c.AccountDeletionController.Cancel)
+	c.App.Post("/api/users/_current/export", middleware.NewOwnerOnly(), c.DataExportController.Create)
+	c.App.Get("/api/users/_current/exports/:exportId", middleware.NewOwnerOnly(), c.DataExportController.Get)
+	c.App.Get("/api/users/_current/exports/:exportId/download", middleware.NewOwnerOnly(), c.DataExportController.Download)
+	c.App.Get("/api/users/_current/sessions", c.SessionController.List)
+	c.App.Delete("/api/users/_current/sessions", c.SessionController.RevokeAll)
+	c.App.Delete("/api/users/_current/sessions/:sessionId", c.SessionController.Revoke)
+	c.App.Post("/api/users/_current/mfa/totp", c.MfaController.Enroll)
+	c.App.Post("/api/users/_current/mfa/totp/_confirm", c.MfaController.Confirm)
+	c.App.Delete("/api/users/_current/mfa/totp", c.MfaController.Disable)
+	c.App.Post("/api/users/_current/mfa/recovery-codes", c.MfaController.RegenerateRecoveryCodes)
+	c.App.Get("/api/users/_current/passkeys", c.PasskeyController.List)
+	c.App.Post("/api/users/_current/passkeys/_begin", c.PasskeyController.BeginRegistration)
+	c.App.Post("/api/users/_current/passkeys", c.PasskeyController.FinishRegistration)
+	c.App.Delete("/api/users/_current/passkeys/:passkeyId", c.PasskeyController.Delete)
+	c.App.Get("/api/users/_current/api-keys", c.ApiKeyController.List)
+	c.App.Post("/api/users/_current/api-keys", c.ApiKeyController.Create)
+	c.App.Delete("/api/users/_current/api-keys/:apiKeyId", c.ApiKeyController.Delete)
+
+	read := middleware.NewScope(model.ScopeContactsRead)
+	write := middleware.NewScope(model.ScopeContactsWrite)
+
+	c.App.Get("/api/contacts", read, c.ContactController.List)
+	c.App.Post("/api/contacts", write, c.ContactController.Create)
+	c.App.Get("/api/contacts/_trash", read, c.ContactController.Trash)
+	c.App.Put("/api/contacts/:contactId", write, c.ContactController.Update)
+	c.App.Get("/api/contacts/:contactId", read, c.ContactController.Get)
+	c.App.Delete("/api/contacts/:contactId", write, c.ContactController.Delete)
+	c.App.Post("/api/contacts/:contactId/_restore", write, c.ContactController.Restore)
+
+	c.App.Get("/api/contacts/:contactId/addresses", read, c.AddressController.List)
+	c.App.Post("/api/contacts/:contactId/addresses", write, c.AddressController.Create)
+	c.App.Get("/api/contacts/:contactId/addresses/_trash", read, c.AddressController.Trash)
+	c.App.Put("/api/contacts/:contactId/addresses/:addressId", write, c.AddressController.Update)
+	c.App.Get("/api/contacts/:contactId/addresses/:addressId", read, c.AddressController.Get)
+	c.App.Delete("/api/contacts/:contactId/addresses/:addressId", write, c.AddressController.Delete)
+	c.App.Post("/api/contacts/:contactId/addresses/:addressId/_restore", write, c.AddressController.Restore)
+}
+
+// SetupAdminRoute registers routes that need a permission on top of authentication
+func (c *RouteConfig) SetupAdminRoute() {
+	c.App.Use("/api/admin", middleware.NewSessionOnly())
+	c.App.Get("/api/admin/users", middleware.NewPermission(model.PermissionUsersRead), c.AdminUserController.List)
+	c.App.Get("/api/admin/users/:userId", middleware.NewPermission(model.PermissionUsersRead), c.AdminUserController.Get)
+	c.App.Post("/api/admin/users/:userId/_disable", middleware.NewPermission(model.PermissionUsersWrite), c.AdminUserController.Disable)
+	c.App.Post("/api/admin/users/:userId/_enable", middleware.NewPermission(model.PermissionUsersWrite), c.AdminUserController.Enable)
+	c.App.Post("/api/admin/users/:userId/_logout", middleware.NewPermission(model.PermissionUsersWrite), c.AdminUserController.Logout)
+	c.App.Post("/api/admin/users/:userId/_reset-password", middleware.NewPermission(model.PermissionUsersWrite), c.AdminUserController.ResetPassword)
+	c.App.Post("/api/admin/users/:userId/_impersonate", middleware.NewPermission(model.PermissionUsersImpersonate), c.AdminUserController.Impersonate)
 
-	c.App.Get("/api/contacts", c.ContactController.List)
-	c.App.Post("/api/contacts", c.ContactController.Create)
-	c.App.Put("/api/contacts/:contactId", c.ContactController.Update)
-	c.App.Get("/api/contacts/:contactId", c.ContactController.Get)
-	c.App.Delete("/api/contacts/:contactId", c.ContactController.Delete)
-
-	c.App.Get("/api/contacts/:contactId/addresses", c.AddressController.List)
-	c.App.Post("/api/contacts/:contactId/addresses", c.AddressController.Create)
-	c.App.Put("/api/contacts/:contactId/addresses/:addressId", c.AddressController.Update)
-	c.App.Get("/api/contacts/:contactId/addresses/:addressId", c.AddressController.Get)
-	c.App.Delete("/api/contacts/:contactId/addresses/:addressId", c.AddressController.Delete)
+	c.App.Get("/api/admin/roles", middleware.NewPermission(model.PermissionRolesRead), c.RoleController.List)
+	c.App.Get("/api/admin/users/:userId/roles", middleware.NewPermission(model.PermissionRolesRead), c.RoleController.ListByUser)
+	c.App.Post("/api/admin/users/:userId/roles", middleware.NewPermission(model.PermissionRolesWrite), c.RoleController.Grant)
+	c.App.Delete("/api/admin/users/:userId/roles/:roleId", middleware.NewPermission(model.PermissionRolesWrite), c.RoleController.Revoke)
 }
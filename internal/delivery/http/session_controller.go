@@ -0,0 +1,105 @@
+package http
+
+import (
+	"go-clean-template/internal/delivery/http/middleware"
+	"go-clean-template/internal/model"
+	"go-clean-template/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+type SessionController struct {
+	Log     *zap.SugaredLogger
+	UseCase *usecase.SessionUseCase
+}
+
+func NewSessionController(useCase *usecase.SessionUseCase, logger *zap.SugaredLogger) *SessionController {
+	return &SessionController{
+		Log:     logger,
+		UseCase: useCase,
+	}
+}
+
+// List godoc
+// @Summary List sessions
+// @Description List the active sessions of the current user
+// @Tags Session API
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} model.WebResponse[[]model.SessionResponse]
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /api/users/_current/sessions [get]
+func (c *SessionController) List(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+
+	request := &model.ListSessionRequest{
+		UserId:    auth.ID,
+		SessionId: auth.SessionID,
+	}
+
+	responses, err := c.UseCase.List(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Errorw("Failed to list sessions", "error", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[[]model.SessionResponse]{Data: responses})
+}
+
+// Revoke godoc
+// @Summary Revoke session
+// @Description Log out a single session of the current user
+// @Tags Session API
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param sessionId path string true "Session ID"
+// @Success 200 {object} model.WebResponse[bool]
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /api/users/_current/sessions/{sessionId} [delete]
+func (c *SessionController) Revoke(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+
+	request := &model.RevokeSessionRequest{
+		UserId: auth.ID,
+		ID:     ctx.Params("sessionId"),
+	}
+
+	if err := c.UseCase.Revoke(ctx.UserContext(), request); err != nil {
+		c.Log.Errorw("Failed to revoke session", "error", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[bool]{Data: true})
+}
+
+// RevokeAll godoc
+// @Summary Revoke all sessions
+// @Description Log out the current user everywhere
+// @Tags Session API
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} model.WebResponse[bool]
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /api/users/_current/sessions [delete]
+func (c *SessionController) RevokeAll(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+
+	request := &model.RevokeAllSessionRequest{
+		UserId: auth.ID,
+	}
+
+	if err := c.UseCase.RevokeAll(ctx.UserContext(), request); err != nil {
+		c.Log.Errorw("Failed to revoke all sessions", "error", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[bool]{Data: true})
+}
@@ -58,6 +58,9 @@ func (c *UserController) Register(ctx *fiber.Ctx) error {
 // @Param request body model.LoginUserRequest true "Login User Request"
 // @Success 200 {object} model.WebResponse[model.UserResponse]
 // @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 423 {object} model.ErrorResponse
+// @Failure 429 {object} model.ErrorResponse
 // @Failure 500 {object} model.ErrorResponse
 // @Router /api/users/_login [post]
 func (c *UserController) Login(ctx *fiber.Ctx) error {
@@ -68,6 +71,8 @@ func (c *UserController) Login(ctx *fiber.Ctx) error {
 		return fiber.ErrBadRequest
 	}
 
+	request.UserAgent, request.IPAddress = clientInfo(ctx)
+
 	response, err := c.UseCase.Login(ctx.UserContext(), request)
 	if err != nil {
 		c.Log.Warnf("Failed to login user : %+v", err)
@@ -77,6 +82,97 @@ func (c *UserController) Login(ctx *fiber.Ctx) error {
 	return ctx.JSON(model.WebResponse[*model.UserResponse]{Data: response})
 }
 
+// LoginMfa godoc
+// @Summary Complete two-factor login
+// @Description Exchange the mfa_token returned by login and a TOTP or recovery code for an access and refresh token pair
+// @Tags User API
+// @Accept json
+// @Produce json
+// @Param request body model.LoginMfaUserRequest true "Login Mfa User Request"
+// @Success 200 {object} model.WebResponse[model.UserResponse]
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /api/users/_login/mfa [post]
+func (c *UserController) LoginMfa(ctx *fiber.Ctx) error {
+	request := new(model.LoginMfaUserRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.Warnf("Failed to parse request body : %+v", err)
+		return fiber.ErrBadRequest
+	}
+
+	request.UserAgent, request.IPAddress = clientInfo(ctx)
+
+	response, err := c.UseCase.LoginMfa(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to complete two-factor login : %+v", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[*model.UserResponse]{Data: response})
+}
+
+// LoginMagicLink godoc
+// @Summary Login with a magic link
+// @Description Exchange the token from a magic link and the device_token returned when it was requested for an access and refresh token pair, or an mfa_token when two-factor authentication is enabled
+// @Tags User API
+// @Accept json
+// @Produce json
+// @Param request body model.LoginMagicLinkRequest true "Login Magic Link Request"
+// @Success 200 {object} model.WebResponse[model.UserResponse]
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /api/users/_login/magic-link [post]
+func (c *UserController) LoginMagicLink(ctx *fiber.Ctx) error {
+	request := new(model.LoginMagicLinkRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.Warnf("Failed to parse request body : %+v", err)
+		return fiber.ErrBadRequest
+	}
+
+	request.UserAgent, request.IPAddress = clientInfo(ctx)
+
+	response, err := c.UseCase.LoginMagicLink(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to login with magic link : %+v", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[*model.UserResponse]{Data: response})
+}
+
+// Refresh godoc
+// @Summary Refresh access token
+// @Description Exchange a refresh token for a new access and refresh token pair
+// @Tags User API
+// @Accept json
+// @Produce json
+// @Param request body model.RefreshUserRequest true "Refresh User Request"
+// @Success 200 {object} model.WebResponse[model.UserResponse]
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /api/users/_refresh [post]
+func (c *UserController) Refresh(ctx *fiber.Ctx) error {
+	request := new(model.RefreshUserRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.Warnf("Failed to parse request body : %+v", err)
+		return fiber.ErrBadRequest
+	}
+
+	request.UserAgent, request.IPAddress = clientInfo(ctx)
+
+	response, err := c.UseCase.Refresh(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to refresh token : %+v", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[*model.UserResponse]{Data: response})
+}
+
 // Current godoc
 // @Summary Get current user
 // @Description Get current user
@@ -119,7 +215,8 @@ func (c *UserController) Logout(ctx *fiber.Ctx) error {
 	auth := middleware.GetUser(ctx)
 
 	request := &model.LogoutUserRequest{
-		ID: auth.ID,
+		ID:        auth.ID,
+		SessionId: auth.SessionID,
 	}
 
 	response, err := c.UseCase.Logout(ctx.UserContext(), request)
@@ -161,3 +258,48 @@ func (c *UserController) Update(ctx *fiber.Ctx) error {
 
 	return ctx.JSON(model.WebResponse[*model.UserResponse]{Data: response})
 }
+
+// ChangePassword godoc
+// @Summary Change password
+// @Description Set a new password, confirmed with the current one, every other session is logged out
+// @Tags User API
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body model.ChangePasswordRequest true "Change Password Request"
+// @Success 200 {object} model.WebResponse[bool]
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 423 {object} model.ErrorResponse
+// @Failure 429 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /api/users/_current/_change-password [post]
+func (c *UserController) ChangePassword(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+
+	request := new(model.ChangePasswordRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.Warnf("Failed to parse request body : %+v", err)
+		return fiber.ErrBadRequest
+	}
+
+	request.ID = auth.ID
+	request.SessionId = auth.SessionID
+	request.UserAgent, request.IPAddress = clientInfo(ctx)
+	response, err := c.UseCase.ChangePassword(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Errorw("Failed to change password", "error", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[bool]{Data: response})
+}
+
+// clientInfo returns the user agent, trimmed to fit the sessions table, and the client IP
+func clientInfo(ctx *fiber.Ctx) (string, string) {
+	userAgent := ctx.Get(fiber.HeaderUserAgent)
+	if len(userAgent) > 255 {
+		userAgent = userAgent[:255]
+	}
+	return userAgent, ctx.IP()
+}
@@ -0,0 +1,31 @@
+package scheduler
+
+import (
+	"context"
+
+	"go-clean-template/internal/usecase"
+
+	"go.uber.org/zap"
+)
+
+type AccountDeletionJob struct {
+	UseCase *usecase.AccountDeletionUseCase
+	Log     *zap.SugaredLogger
+}
+
+func NewAccountDeletionJob(useCase *usecase.AccountDeletionUseCase, log *zap.SugaredLogger) *AccountDeletionJob {
+	return &AccountDeletionJob{
+		UseCase: useCase,
+		Log:     log,
+	}
+}
+
+func (j AccountDeletionJob) Run(ctx context.Context) error {
+	total, err := j.UseCase.Purge(ctx)
+	if err != nil {
+		return err
+	}
+
+	j.Log.Infof("Deleted %d accounts past their grace period", total)
+	return nil
+}
@@ -0,0 +1,36 @@
+package scheduler
+
+import (
+	"context"
+
+	"go-clean-template/internal/usecase"
+
+	"go.uber.org/zap"
+)
+
+type DataExportJob struct {
+	UseCase *usecase.DataExportUseCase
+	Log     *zap.SugaredLogger
+}
+
+func NewDataExportJob(useCase *usecase.DataExportUseCase, log *zap.SugaredLogger) *DataExportJob {
+	return &DataExportJob{
+		UseCase: useCase,
+		Log:     log,
+	}
+}
+
+func (j DataExportJob) Run(ctx context.Context) error {
+	built, err := j.UseCase.Build(ctx)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := j.UseCase.DeleteExpired(ctx)
+	if err != nil {
+		return err
+	}
+
+	j.Log.Infof("Built %d data exports and deleted %d expired ones", built, deleted)
+	return nil
+}
@@ -0,0 +1,31 @@
+package scheduler
+
+import (
+	"context"
+
+	"go-clean-template/internal/usecase"
+
+	"go.uber.org/zap"
+)
+
+type LoginAttemptCleanupJob struct {
+	UseCase *usecase.LoginProtectionUseCase
+	Log     *zap.SugaredLogger
+}
+
+func NewLoginAttemptCleanupJob(useCase *usecase.LoginProtectionUseCase, log *zap.SugaredLogger) *LoginAttemptCleanupJob {
+	return &LoginAttemptCleanupJob{
+		UseCase: useCase,
+		Log:     log,
+	}
+}
+
+func (j LoginAttemptCleanupJob) Run(ctx context.Context) error {
+	total, err := j.UseCase.DeleteStale(ctx)
+	if err != nil {
+		return err
+	}
+
+	j.Log.Infof("Deleted %d stale login attempts", total)
+	return nil
+}
@@ -0,0 +1,35 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type JobHandler func(ctx context.Context) error
+
+// RunJob calls the handler every interval until the context is cancelled, a job without a positive
+// interval is not run at all
+func RunJob(ctx context.Context, name string, interval time.Duration, log *zap.SugaredLogger, handler JobHandler) {
+	if interval <= 0 {
+		log.Errorw("Job interval must be positive, job not started", "job", name, "interval", interval)
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := handler(ctx); err != nil {
+				log.Errorw("Failed to run job", "job", name, "error", err)
+			}
+
+		case <-ctx.Done():
+			log.Infof("Stopping job: %s", name)
+			return
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package scheduler
+
+import (
+	"context"
+
+	"go-clean-template/internal/usecase"
+
+	"go.uber.org/zap"
+)
+
+type SessionCleanupJob struct {
+	UseCase *usecase.SessionUseCase
+	Log     *zap.SugaredLogger
+}
+
+func NewSessionCleanupJob(useCase *usecase.SessionUseCase, log *zap.SugaredLogger) *SessionCleanupJob {
+	return &SessionCleanupJob{
+		UseCase: useCase,
+		Log:     log,
+	}
+}
+
+func (j SessionCleanupJob) Run(ctx context.Context) error {
+	total, err := j.UseCase.DeleteInactive(ctx)
+	if err != nil {
+		return err
+	}
+
+	j.Log.Infof("Deleted %d inactive sessions", total)
+	return nil
+}
@@ -0,0 +1,31 @@
+package scheduler
+
+import (
+	"context"
+
+	"go-clean-template/internal/usecase"
+
+	"go.uber.org/zap"
+)
+
+type TrashPurgeJob struct {
+	UseCase *usecase.ContactUseCase
+	Log     *zap.SugaredLogger
+}
+
+func NewTrashPurgeJob(useCase *usecase.ContactUseCase, log *zap.SugaredLogger) *TrashPurgeJob {
+	return &TrashPurgeJob{
+		UseCase: useCase,
+		Log:     log,
+	}
+}
+
+func (j TrashPurgeJob) Run(ctx context.Context) error {
+	contacts, addresses, err := j.UseCase.Purge(ctx)
+	if err != nil {
+		return err
+	}
+
+	j.Log.Infof("Purged %d contacts and %d addresses past their trash retention", contacts, addresses)
+	return nil
+}
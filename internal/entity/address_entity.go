@@ -10,6 +10,7 @@ type Address struct {
 	Country    string  `gorm:"column:country"`
 	CreatedAt  int64   `gorm:"column:created_at;autoCreateTime:milli"`
 	UpdatedAt  int64   `gorm:"column:updated_at;autoCreateTime:milli;autoUpdateTime:milli"`
+	DeletedAt  int64   `gorm:"column:deleted_at"`
 	Contact    Contact `gorm:"foreignKey:contact_id;references:id"`
 }
 
@@ -0,0 +1,19 @@
+package entity
+
+// ApiKey is a struct that represents a long-lived key for machine clients, only the hash of the key is stored
+type ApiKey struct {
+	ID         string `gorm:"column:id;primaryKey"`
+	UserId     string `gorm:"column:user_id"`
+	Name       string `gorm:"column:name"`
+	Prefix     string `gorm:"column:prefix"`
+	KeyHash    string `gorm:"column:key_hash"`
+	Scopes     string `gorm:"column:scopes"`
+	ExpiresAt  int64  `gorm:"column:expires_at"`
+	LastUsedAt int64  `gorm:"column:last_used_at"`
+	CreatedAt  int64  `gorm:"column:created_at;autoCreateTime:milli"`
+	User       User   `gorm:"foreignKey:user_id;references:id"`
+}
+
+func (a *ApiKey) TableName() string {
+	return "api_keys"
+}
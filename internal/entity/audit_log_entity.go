@@ -0,0 +1,18 @@
+package entity
+
+// AuditLog is a struct that represents a security relevant action on an account, ActorId differs
+// from UserId when an administrator acted on the account or impersonated the user
+type AuditLog struct {
+	ID        string `gorm:"column:id;primaryKey"`
+	UserId    string `gorm:"column:user_id"`
+	ActorId   string `gorm:"column:actor_id"`
+	Action    string `gorm:"column:action"`
+	Detail    string `gorm:"column:detail"`
+	UserAgent string `gorm:"column:user_agent"`
+	IPAddress string `gorm:"column:ip_address"`
+	CreatedAt int64  `gorm:"column:created_at;autoCreateTime:milli"`
+}
+
+func (a *AuditLog) TableName() string {
+	return "audit_logs"
+}
@@ -9,6 +9,7 @@ type Contact struct {
 	UserId    string    `gorm:"column:user_id"`
 	CreatedAt int64     `gorm:"column:created_at;autoCreateTime:milli"`
 	UpdatedAt int64     `gorm:"column:updated_at;autoCreateTime:milli;autoUpdateTime:milli"`
+	DeletedAt int64     `gorm:"column:deleted_at"`
 	User      User      `gorm:"foreignKey:user_id;references:id"`
 	Addresses []Address `gorm:"foreignKey:contact_id;references:id"`
 }
@@ -0,0 +1,18 @@
+package entity
+
+// DataExport is a struct that represents a takeout of everything stored about a user, the archive is
+// built by the worker and kept until it expires
+type DataExport struct {
+	ID          string `gorm:"column:id;primaryKey"`
+	UserId      string `gorm:"column:user_id"`
+	Status      string `gorm:"column:status"`
+	Archive     []byte `gorm:"column:archive"`
+	CreatedAt   int64  `gorm:"column:created_at;autoCreateTime:milli"`
+	CompletedAt int64  `gorm:"column:completed_at"`
+	ExpiresAt   int64  `gorm:"column:expires_at"`
+	User        User   `gorm:"foreignKey:user_id;references:id"`
+}
+
+func (d *DataExport) TableName() string {
+	return "data_exports"
+}
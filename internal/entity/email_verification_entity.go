@@ -0,0 +1,17 @@
+package entity
+
+// EmailVerification is a struct that represents a single-use email verification token, only its hash is stored
+type EmailVerification struct {
+	ID        string `gorm:"column:id;primaryKey"`
+	UserId    string `gorm:"column:user_id"`
+	Email     string `gorm:"column:email"`
+	TokenHash string `gorm:"column:token_hash"`
+	ExpiresAt int64  `gorm:"column:expires_at"`
+	UsedAt    int64  `gorm:"column:used_at"`
+	CreatedAt int64  `gorm:"column:created_at;autoCreateTime:milli"`
+	User      User   `gorm:"foreignKey:user_id;references:id"`
+}
+
+func (e *EmailVerification) TableName() string {
+	return "email_verifications"
+}
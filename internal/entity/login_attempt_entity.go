@@ -0,0 +1,13 @@
+package entity
+
+// LoginAttempt is a struct that represents the failed login counter of an account or a client IP
+type LoginAttempt struct {
+	Key           string `gorm:"column:key;primaryKey"`
+	Failures      int    `gorm:"column:failures"`
+	LastFailureAt int64  `gorm:"column:last_failure_at"`
+	LockedUntil   int64  `gorm:"column:locked_until"`
+}
+
+func (a *LoginAttempt) TableName() string {
+	return "login_attempts"
+}
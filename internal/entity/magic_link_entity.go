@@ -0,0 +1,18 @@
+package entity
+
+// MagicLink is a struct that represents a single-use login link sent to Email, it can only be redeemed together
+// with the device token handed to the client that asked for it, only hashes of both are stored
+type MagicLink struct {
+	ID         string `gorm:"column:id;primaryKey"`
+	UserId     string `gorm:"column:user_id"`
+	Email      string `gorm:"column:email"`
+	TokenHash  string `gorm:"column:token_hash"`
+	DeviceHash string `gorm:"column:device_hash"`
+	ExpiresAt  int64  `gorm:"column:expires_at"`
+	UsedAt     int64  `gorm:"column:used_at"`
+	CreatedAt  int64  `gorm:"column:created_at;autoCreateTime:milli"`
+}
+
+func (m *MagicLink) TableName() string {
+	return "magic_links"
+}
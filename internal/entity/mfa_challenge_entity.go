@@ -0,0 +1,19 @@
+package entity
+
+// MfaChallenge is a struct that represents a login waiting for its second factor, only the hash of its token is stored
+type MfaChallenge struct {
+	ID        string `gorm:"column:id;primaryKey"`
+	UserId    string `gorm:"column:user_id"`
+	TokenHash string `gorm:"column:token_hash"`
+	UserAgent string `gorm:"column:user_agent"`
+	IPAddress string `gorm:"column:ip_address"`
+	Attempts  int    `gorm:"column:attempts"`
+	ExpiresAt int64  `gorm:"column:expires_at"`
+	UsedAt    int64  `gorm:"column:used_at"`
+	CreatedAt int64  `gorm:"column:created_at;autoCreateTime:milli"`
+	User      User   `gorm:"foreignKey:user_id;references:id"`
+}
+
+func (c *MfaChallenge) TableName() string {
+	return "mfa_challenges"
+}
@@ -0,0 +1,16 @@
+package entity
+
+// OidcState is a struct that represents a pending OpenID Connect login, only the hash of its state is stored
+type OidcState struct {
+	ID           string `gorm:"column:id;primaryKey"`
+	StateHash    string `gorm:"column:state_hash"`
+	Nonce        string `gorm:"column:nonce"`
+	CodeVerifier string `gorm:"column:code_verifier"`
+	ExpiresAt    int64  `gorm:"column:expires_at"`
+	UsedAt       int64  `gorm:"column:used_at"`
+	CreatedAt    int64  `gorm:"column:created_at;autoCreateTime:milli"`
+}
+
+func (o *OidcState) TableName() string {
+	return "oidc_states"
+}
@@ -0,0 +1,19 @@
+package entity
+
+// Passkey is a struct that represents a WebAuthn credential of a user, CredentialId is base64url encoded
+// and PublicKey is its COSE_Key
+type Passkey struct {
+	ID           string `gorm:"column:id;primaryKey"`
+	UserId       string `gorm:"column:user_id"`
+	Name         string `gorm:"column:name"`
+	CredentialId string `gorm:"column:credential_id"`
+	PublicKey    []byte `gorm:"column:public_key"`
+	SignCount    int64  `gorm:"column:sign_count"`
+	AAGUID       string `gorm:"column:aaguid"`
+	LastUsedAt   int64  `gorm:"column:last_used_at"`
+	CreatedAt    int64  `gorm:"column:created_at;autoCreateTime:milli"`
+}
+
+func (p *Passkey) TableName() string {
+	return "passkeys"
+}
@@ -0,0 +1,16 @@
+package entity
+
+// PasswordReset is a struct that represents a single-use password reset token, only its hash is stored
+type PasswordReset struct {
+	ID        string `gorm:"column:id;primaryKey"`
+	UserId    string `gorm:"column:user_id"`
+	TokenHash string `gorm:"column:token_hash"`
+	ExpiresAt int64  `gorm:"column:expires_at"`
+	UsedAt    int64  `gorm:"column:used_at"`
+	CreatedAt int64  `gorm:"column:created_at;autoCreateTime:milli"`
+	User      User   `gorm:"foreignKey:user_id;references:id"`
+}
+
+func (p *PasswordReset) TableName() string {
+	return "password_resets"
+}
@@ -0,0 +1,11 @@
+package entity
+
+// Permission is a struct that represents a single action a role allows, such as users:read
+type Permission struct {
+	ID          string `gorm:"column:id;primaryKey"`
+	Description string `gorm:"column:description"`
+}
+
+func (p *Permission) TableName() string {
+	return "permissions"
+}
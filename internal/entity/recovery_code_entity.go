@@ -0,0 +1,15 @@
+package entity
+
+// RecoveryCode is a struct that represents a single-use two-factor recovery code, only its hash is stored
+type RecoveryCode struct {
+	ID        string `gorm:"column:id;primaryKey"`
+	UserId    string `gorm:"column:user_id"`
+	CodeHash  string `gorm:"column:code_hash"`
+	UsedAt    int64  `gorm:"column:used_at"`
+	CreatedAt int64  `gorm:"column:created_at;autoCreateTime:milli"`
+	User      User   `gorm:"foreignKey:user_id;references:id"`
+}
+
+func (r *RecoveryCode) TableName() string {
+	return "recovery_codes"
+}
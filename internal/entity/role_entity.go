@@ -0,0 +1,15 @@
+package entity
+
+// Role is a struct that represents a named set of permissions that can be granted to users
+type Role struct {
+	ID          string       `gorm:"column:id;primaryKey"`
+	Name        string       `gorm:"column:name"`
+	Description string       `gorm:"column:description"`
+	CreatedAt   int64        `gorm:"column:created_at;autoCreateTime:milli"`
+	UpdatedAt   int64        `gorm:"column:updated_at;autoCreateTime:milli;autoUpdateTime:milli"`
+	Permissions []Permission `gorm:"many2many:role_permissions;foreignKey:id;joinForeignKey:role_id;references:id;joinReferences:permission_id"`
+}
+
+func (r *Role) TableName() string {
+	return "roles"
+}
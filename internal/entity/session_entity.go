@@ -0,0 +1,20 @@
+package entity
+
+// Session is a struct that represents one logged in device, only the hash of its refresh token is stored
+type Session struct {
+	ID                string `gorm:"column:id;primaryKey"`
+	UserId            string `gorm:"column:user_id"`
+	TokenHash         string `gorm:"column:token_hash"`
+	PreviousTokenHash string `gorm:"column:previous_token_hash"`
+	UserAgent         string `gorm:"column:user_agent"`
+	IPAddress         string `gorm:"column:ip_address"`
+	CreatedAt         int64  `gorm:"column:created_at;autoCreateTime:milli"`
+	LastSeenAt        int64  `gorm:"column:last_seen_at"`
+	ExpiresAt         int64  `gorm:"column:expires_at"`
+	RevokedAt         int64  `gorm:"column:revoked_at"`
+	User              User   `gorm:"foreignKey:user_id;references:id"`
+}
+
+func (s *Session) TableName() string {
+	return "sessions"
+}
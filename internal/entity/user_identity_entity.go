@@ -0,0 +1,17 @@
+package entity
+
+// UserIdentity is a struct that represents an account at an external OpenID Connect issuer linked to a user
+type UserIdentity struct {
+	ID          string `gorm:"column:id;primaryKey"`
+	UserId      string `gorm:"column:user_id"`
+	Issuer      string `gorm:"column:issuer"`
+	Subject     string `gorm:"column:subject"`
+	Email       string `gorm:"column:email"`
+	CreatedAt   int64  `gorm:"column:created_at;autoCreateTime:milli"`
+	LastLoginAt int64  `gorm:"column:last_login_at"`
+	User        User   `gorm:"foreignKey:user_id;references:id"`
+}
+
+func (u *UserIdentity) TableName() string {
+	return "user_identities"
+}
@@ -0,0 +1,14 @@
+package entity
+
+// UserRole is a struct that represents a role granted to a user
+type UserRole struct {
+	UserId    string `gorm:"column:user_id;primaryKey"`
+	RoleId    string `gorm:"column:role_id;primaryKey"`
+	CreatedAt int64  `gorm:"column:created_at;autoCreateTime:milli"`
+	User      User   `gorm:"foreignKey:user_id;references:id"`
+	Role      Role   `gorm:"foreignKey:role_id;references:id"`
+}
+
+func (u *UserRole) TableName() string {
+	return "user_roles"
+}
@@ -0,0 +1,16 @@
+package entity
+
+// UserTotp is a struct that represents the TOTP authenticator of a user, the secret is stored encrypted
+type UserTotp struct {
+	UserId       string `gorm:"column:user_id;primaryKey"`
+	Secret       string `gorm:"column:secret"`
+	ConfirmedAt  int64  `gorm:"column:confirmed_at"`
+	LastUsedStep int64  `gorm:"column:last_used_step"`
+	CreatedAt    int64  `gorm:"column:created_at;autoCreateTime:milli"`
+	UpdatedAt    int64  `gorm:"column:updated_at;autoCreateTime:milli;autoUpdateTime:milli"`
+	User         User   `gorm:"foreignKey:user_id;references:id"`
+}
+
+func (t *UserTotp) TableName() string {
+	return "user_totps"
+}
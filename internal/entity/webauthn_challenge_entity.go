@@ -0,0 +1,17 @@
+package entity
+
+// WebAuthnChallenge is a struct that represents a pending passkey registration or login, only the hash of
+// its challenge is stored. UserId is the registering user, or the account a login was started for if any
+type WebAuthnChallenge struct {
+	ID            string `gorm:"column:id;primaryKey"`
+	ChallengeHash string `gorm:"column:challenge_hash"`
+	Ceremony      string `gorm:"column:ceremony"`
+	UserId        string `gorm:"column:user_id"`
+	ExpiresAt     int64  `gorm:"column:expires_at"`
+	UsedAt        int64  `gorm:"column:used_at"`
+	CreatedAt     int64  `gorm:"column:created_at;autoCreateTime:milli"`
+}
+
+func (w *WebAuthnChallenge) TableName() string {
+	return "webauthn_challenges"
+}
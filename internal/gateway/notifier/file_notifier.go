@@ -0,0 +1,51 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+
+	"go-clean-template/internal/model"
+
+	"go.uber.org/zap"
+)
+
+// FileNotifier appends notifications as JSON lines to a file, for local development and tests
+type FileNotifier struct {
+	Path string
+	Log  *zap.SugaredLogger
+	mu   sync.Mutex
+}
+
+func NewFileNotifier(path string, log *zap.SugaredLogger) *FileNotifier {
+	return &FileNotifier{
+		Path: path,
+		Log:  log,
+	}
+}
+
+func (n *FileNotifier) Send(ctx context.Context, notification *model.Notification) error {
+	value, err := json.Marshal(notification)
+	if err != nil {
+		n.Log.Errorw("failed to marshal notification", "error", err)
+		return err
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	file, err := os.OpenFile(n.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		n.Log.Errorw("failed to open notification file", "error", err)
+		return err
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(value, '\n')); err != nil {
+		n.Log.Errorw("failed to write notification", "error", err)
+		return err
+	}
+
+	return nil
+}
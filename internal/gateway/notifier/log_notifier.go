@@ -0,0 +1,25 @@
+package notifier
+
+import (
+	"context"
+
+	"go-clean-template/internal/model"
+
+	"go.uber.org/zap"
+)
+
+// LogNotifier writes notifications to the application log, for local development only
+type LogNotifier struct {
+	Log *zap.SugaredLogger
+}
+
+func NewLogNotifier(log *zap.SugaredLogger) *LogNotifier {
+	return &LogNotifier{
+		Log: log,
+	}
+}
+
+func (n *LogNotifier) Send(ctx context.Context, notification *model.Notification) error {
+	n.Log.Infow("Notification", "to", notification.To, "subject", notification.Subject, "body", notification.Body)
+	return nil
+}
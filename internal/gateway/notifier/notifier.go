@@ -0,0 +1,12 @@
+package notifier
+
+import (
+	"context"
+
+	"go-clean-template/internal/model"
+)
+
+// Notifier delivers messages to users, e.g. by email or SMS
+type Notifier interface {
+	Send(ctx context.Context, notification *model.Notification) error
+}
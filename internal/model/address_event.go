@@ -10,6 +10,8 @@ type AddressEvent struct {
 	Country    string `json:"country"`
 	CreatedAt  int64  `json:"created_at"`
 	UpdatedAt  int64  `json:"updated_at"`
+	// DeletedAt is set when the event reports the address deleted, consumers should drop their copies
+	DeletedAt int64 `json:"deleted_at,omitempty"`
 }
 
 func (a *AddressEvent) GetId() string {
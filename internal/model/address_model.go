@@ -9,6 +9,7 @@ type AddressResponse struct {
 	Country    string `json:"country"`
 	CreatedAt  int64  `json:"created_at"`
 	UpdatedAt  int64  `json:"updated_at"`
+	DeletedAt  int64  `json:"deleted_at,omitempty"`
 }
 
 type ListAddressRequest struct {
@@ -48,3 +49,14 @@ type DeleteAddressRequest struct {
 	ContactId string `json:"-" validate:"required,max=100,uuid"`
 	ID        string `json:"-" validate:"required,max=100,uuid"`
 }
+
+type ListTrashedAddressRequest struct {
+	UserId    string `json:"-" validate:"required"`
+	ContactId string `json:"-" validate:"required,max=100,uuid"`
+}
+
+type RestoreAddressRequest struct {
+	UserId    string `json:"-" validate:"required"`
+	ContactId string `json:"-" validate:"required,max=100,uuid"`
+	ID        string `json:"-" validate:"required,max=100,uuid"`
+}
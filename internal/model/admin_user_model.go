@@ -0,0 +1,43 @@
+package model
+
+type SearchUserRequest struct {
+	ID       string `json:"id" validate:"max=100"`
+	Name     string `json:"name" validate:"max=100"`
+	Email    string `json:"email" validate:"max=200"`
+	Disabled *bool  `json:"disabled"`
+	Page     int    `json:"page" validate:"min=1"`
+	Size     int    `json:"size" validate:"min=1,max=100"`
+}
+
+type GetAdminUserRequest struct {
+	ID string `json:"-" validate:"required,max=100"`
+}
+
+// DisableUserRequest and the other admin account actions carry the acting admin,
+// who can not lock themselves out
+type DisableUserRequest struct {
+	Actor *Auth  `json:"-" validate:"required"`
+	ID    string `json:"-" validate:"required,max=100"`
+}
+
+type EnableUserRequest struct {
+	Actor *Auth  `json:"-" validate:"required"`
+	ID    string `json:"-" validate:"required,max=100"`
+}
+
+type ForceLogoutUserRequest struct {
+	Actor *Auth  `json:"-" validate:"required"`
+	ID    string `json:"-" validate:"required,max=100"`
+}
+
+type AdminResetPasswordRequest struct {
+	Actor *Auth  `json:"-" validate:"required"`
+	ID    string `json:"-" validate:"required,max=100"`
+}
+
+type ImpersonateUserRequest struct {
+	Actor     *Auth  `json:"-" validate:"required"`
+	ID        string `json:"-" validate:"required,max=100"`
+	UserAgent string `json:"-" validate:"max=255"`
+	IPAddress string `json:"-" validate:"max=45"`
+}
@@ -0,0 +1,29 @@
+package model
+
+type ApiKeyResponse struct {
+	ID         string   `json:"id"`
+	Name       string   `json:"name"`
+	Prefix     string   `json:"prefix"`
+	Scopes     []string `json:"scopes"`
+	ExpiresAt  int64    `json:"expires_at,omitempty"`
+	LastUsedAt int64    `json:"last_used_at,omitempty"`
+	CreatedAt  int64    `json:"created_at"`
+	// Key is only returned once, when the key is created
+	Key string `json:"key,omitempty"`
+}
+
+type CreateApiKeyRequest struct {
+	UserId    string   `json:"-" validate:"required"`
+	Name      string   `json:"name" validate:"required,max=100"`
+	Scopes    []string `json:"scopes" validate:"required,min=1,dive,oneof=contacts:read contacts:write"`
+	ExpiresAt int64    `json:"expires_at" validate:"min=0"`
+}
+
+type ListApiKeyRequest struct {
+	UserId string `json:"-" validate:"required"`
+}
+
+type DeleteApiKeyRequest struct {
+	UserId string `json:"-" validate:"required"`
+	ID     string `json:"-" validate:"required,max=100,uuid"`
+}
@@ -0,0 +1,38 @@
+package model
+
+const (
+	AuditLogin             = "login"
+	AuditPasswordChanged   = "user.password_changed"
+	AuditPasskeyAdded      = "passkey.added"
+	AuditPasskeyRemoved    = "passkey.removed"
+	AuditDataExport        = "data_export.requested"
+	AuditUserDisabled      = "user.disabled"
+	AuditUserEnabled       = "user.enabled"
+	AuditUserLoggedOut     = "user.logged_out"
+	AuditPasswordResetSent = "user.password_reset_sent"
+	AuditDeletionScheduled = "user.deletion_scheduled"
+	AuditDeletionCancelled = "user.deletion_cancelled"
+	AuditImpersonation     = "impersonation.started"
+	AuditImpersonatedCall  = "impersonation.request"
+)
+
+type AuditLogResponse struct {
+	ID        string `json:"id"`
+	ActorID   string `json:"actor_id"`
+	Action    string `json:"action"`
+	Detail    string `json:"detail,omitempty"`
+	UserAgent string `json:"user_agent,omitempty"`
+	IPAddress string `json:"ip_address,omitempty"`
+	CreatedAt int64  `json:"created_at"`
+}
+
+// RecordAuditLogRequest records an action that is not part of a use case of its own, such as a request
+// an administrator made while impersonating the user
+type RecordAuditLogRequest struct {
+	UserId    string `json:"-" validate:"required,max=100"`
+	ActorId   string `json:"-" validate:"required,max=100"`
+	Action    string `json:"-" validate:"required,max=100"`
+	Detail    string `json:"-" validate:"max=255"`
+	UserAgent string `json:"-" validate:"max=255"`
+	IPAddress string `json:"-" validate:"max=45"`
+}
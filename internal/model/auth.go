@@ -1,6 +1,41 @@
 package model
 
+import "slices"
+
 type Auth struct {
 	// Login user id
 	ID string
+	// Login session id, empty for access tokens issued before sessions existed
+	SessionID string
+	// Access token id (jti), empty for legacy tokens
+	TokenID string
+	// Access token issue and expiry time in unix milli, zero for legacy tokens
+	IssuedAt  int64
+	ExpiresAt int64
+	// Role ids and the union of their permissions at the time the access token was issued
+	Roles       []string
+	Permissions []string
+	// API key id and its scopes when the request is authenticated with an API key instead of an access token
+	ApiKeyID string
+	Scopes   []string
+	// Administrator acting as the user when the access token was issued for impersonation, ID is the impersonated user
+	ActorID string
+}
+
+func (a *Auth) HasPermission(permission string) bool {
+	return slices.Contains(a.Permissions, permission)
+}
+
+func (a *Auth) HasRole(role string) bool {
+	return slices.Contains(a.Roles, role)
+}
+
+// HasScope reports whether an API key may be used for the scope, access tokens are not limited by scopes
+func (a *Auth) HasScope(scope string) bool {
+	return a.ApiKeyID == "" || slices.Contains(a.Scopes, scope)
+}
+
+// Impersonated reports whether an administrator is acting as the user
+func (a *Auth) Impersonated() bool {
+	return a.ActorID != ""
 }
@@ -9,6 +9,10 @@ type ContactEvent struct {
 	Phone     string `json:"phone"`
 	CreatedAt int64  `json:"created_at"`
 	UpdatedAt int64  `json:"updated_at"`
+	// DeletedAt is set when the event reports the contact deleted, consumers should drop their copies
+	DeletedAt int64 `json:"deleted_at,omitempty"`
+	// AddressIds lists the addresses deleted along with the contact
+	AddressIds []string `json:"address_ids,omitempty"`
 }
 
 func (c *ContactEvent) GetId() string {
@@ -1,5 +1,10 @@
 package model
 
+import (
+	"encoding/base64"
+	"encoding/json"
+)
+
 type ContactResponse struct {
 	ID        string            `json:"id"`
 	FirstName string            `json:"first_name"`
@@ -8,9 +13,32 @@ type ContactResponse struct {
 	Phone     string            `json:"phone"`
 	CreatedAt int64             `json:"created_at"`
 	UpdatedAt int64             `json:"updated_at"`
+	DeletedAt int64             `json:"deleted_at,omitempty"`
 	Addresses []AddressResponse `json:"addresses,omitempty"`
 }
 
+// Trim keeps the id and the listed fields of the response, named as in its JSON
+func (c *ContactResponse) Trim(fields []string) map[string]any {
+	trimmed := map[string]any{"id": c.ID}
+	for _, field := range fields {
+		switch field {
+		case "first_name":
+			trimmed[field] = c.FirstName
+		case "last_name":
+			trimmed[field] = c.LastName
+		case "email":
+			trimmed[field] = c.Email
+		case "phone":
+			trimmed[field] = c.Phone
+		case "created_at":
+			trimmed[field] = c.CreatedAt
+		case "updated_at":
+			trimmed[field] = c.UpdatedAt
+		}
+	}
+	return trimmed
+}
+
 type CreateContactRequest struct {
 	UserId    string `json:"-" validate:"required"`
 	FirstName string `json:"first_name" validate:"required,max=100"`
@@ -30,11 +58,50 @@ type UpdateContactRequest struct {
 
 type SearchContactRequest struct {
 	UserId string `json:"-" validate:"required"`
+	Query  string `json:"q" validate:"max=100"`
 	Name   string `json:"name" validate:"max=100"`
 	Email  string `json:"email" validate:"max=200"`
 	Phone  string `json:"phone" validate:"max=20"`
 	Page   int    `json:"page" validate:"min=1"`
 	Size   int    `json:"size" validate:"min=1,max=100"`
+	// Sort lists the keys to sort by in order of precedence, a key prefixed with - sorts descending
+	Sort []string `json:"sort" validate:"max=7,unique,unique_sort,dive,oneof=first_name -first_name last_name -last_name email -email phone -phone created_at -created_at updated_at -updated_at"`
+	// Fields trims the responses to the listed fields, the id is always kept
+	Fields []string `json:"fields" validate:"max=7,unique,dive,oneof=id first_name last_name email phone created_at updated_at"`
+	// After and Before are cursors from the paging of a previous response, either one takes the place of Page
+	After  string `json:"after" validate:"max=2000"`
+	Before string `json:"before" validate:"max=2000,excluded_with=After"`
+	// CountTotal counts the matching contacts for total_item and total_page, which takes a query of its own
+	CountTotal bool `json:"total"`
+}
+
+// ContactCursor marks a contact in a sorted list by the values of its sort keys and its id,
+// Sort tells which sort it belongs to
+type ContactCursor struct {
+	Sort   string            `json:"s"`
+	Values []json.RawMessage `json:"v"`
+	ID     string            `json:"id"`
+}
+
+func (c *ContactCursor) Encode() (string, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func DecodeContactCursor(cursor string) (*ContactCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	decoded := new(ContactCursor)
+	if err := json.Unmarshal(data, decoded); err != nil {
+		return nil, err
+	}
+	return decoded, nil
 }
 
 type GetContactRequest struct {
@@ -46,3 +113,14 @@ type DeleteContactRequest struct {
 	UserId string `json:"-" validate:"required"`
 	ID     string `json:"-" validate:"required,max=100,uuid"`
 }
+
+type SearchTrashedContactRequest struct {
+	UserId string `json:"-" validate:"required"`
+	Page   int    `json:"page" validate:"min=1"`
+	Size   int    `json:"size" validate:"min=1,max=100"`
+}
+
+type RestoreContactRequest struct {
+	UserId string `json:"-" validate:"required"`
+	ID     string `json:"-" validate:"required,max=100,uuid"`
+}
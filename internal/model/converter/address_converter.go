@@ -15,6 +15,7 @@ func AddressToResponse(address *entity.Address) *model.AddressResponse {
 		Country:    address.Country,
 		CreatedAt:  address.CreatedAt,
 		UpdatedAt:  address.UpdatedAt,
+		DeletedAt:  address.DeletedAt,
 	}
 }
 
@@ -29,5 +30,6 @@ func AddressToEvent(address *entity.Address) *model.AddressEvent {
 		Country:    address.Country,
 		CreatedAt:  address.CreatedAt,
 		UpdatedAt:  address.UpdatedAt,
+		DeletedAt:  address.DeletedAt,
 	}
 }
@@ -0,0 +1,28 @@
+package converter
+
+import (
+	"strings"
+
+	"go-clean-template/internal/entity"
+	"go-clean-template/internal/model"
+)
+
+func ApiKeyToResponse(apiKey *entity.ApiKey) *model.ApiKeyResponse {
+	return &model.ApiKeyResponse{
+		ID:         apiKey.ID,
+		Name:       apiKey.Name,
+		Prefix:     apiKey.Prefix,
+		Scopes:     strings.Split(apiKey.Scopes, ","),
+		ExpiresAt:  apiKey.ExpiresAt,
+		LastUsedAt: apiKey.LastUsedAt,
+		CreatedAt:  apiKey.CreatedAt,
+	}
+}
+
+func ApiKeyToAuth(apiKey *entity.ApiKey) *model.Auth {
+	return &model.Auth{
+		ID:       apiKey.UserId,
+		ApiKeyID: apiKey.ID,
+		Scopes:   strings.Split(apiKey.Scopes, ","),
+	}
+}
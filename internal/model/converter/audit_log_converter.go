@@ -0,0 +1,18 @@
+package converter
+
+import (
+	"go-clean-template/internal/entity"
+	"go-clean-template/internal/model"
+)
+
+func AuditLogToResponse(auditLog *entity.AuditLog) *model.AuditLogResponse {
+	return &model.AuditLogResponse{
+		ID:        auditLog.ID,
+		ActorID:   auditLog.ActorId,
+		Action:    auditLog.Action,
+		Detail:    auditLog.Detail,
+		UserAgent: auditLog.UserAgent,
+		IPAddress: auditLog.IPAddress,
+		CreatedAt: auditLog.CreatedAt,
+	}
+}
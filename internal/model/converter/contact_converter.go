@@ -14,6 +14,7 @@ func ContactToResponse(contact *entity.Contact) *model.ContactResponse {
 		Phone:     contact.Phone,
 		CreatedAt: contact.CreatedAt,
 		UpdatedAt: contact.UpdatedAt,
+		DeletedAt: contact.DeletedAt,
 	}
 }
 
@@ -27,5 +28,6 @@ func ContactToEvent(contact *entity.Contact) *model.ContactEvent {
 		Phone:     contact.Phone,
 		CreatedAt: contact.CreatedAt,
 		UpdatedAt: contact.UpdatedAt,
+		DeletedAt: contact.DeletedAt,
 	}
 }
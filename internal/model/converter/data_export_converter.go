@@ -0,0 +1,16 @@
+package converter
+
+import (
+	"go-clean-template/internal/entity"
+	"go-clean-template/internal/model"
+)
+
+func DataExportToResponse(dataExport *entity.DataExport) *model.DataExportResponse {
+	return &model.DataExportResponse{
+		ID:          dataExport.ID,
+		Status:      dataExport.Status,
+		CreatedAt:   dataExport.CreatedAt,
+		CompletedAt: dataExport.CompletedAt,
+		ExpiresAt:   dataExport.ExpiresAt,
+	}
+}
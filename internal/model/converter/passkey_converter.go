@@ -0,0 +1,15 @@
+package converter
+
+import (
+	"go-clean-template/internal/entity"
+	"go-clean-template/internal/model"
+)
+
+func PasskeyToResponse(passkey *entity.Passkey) *model.PasskeyResponse {
+	return &model.PasskeyResponse{
+		ID:         passkey.ID,
+		Name:       passkey.Name,
+		LastUsedAt: passkey.LastUsedAt,
+		CreatedAt:  passkey.CreatedAt,
+	}
+}
@@ -0,0 +1,39 @@
+package converter
+
+import (
+	"go-clean-template/internal/entity"
+	"go-clean-template/internal/model"
+)
+
+func RoleToResponse(role *entity.Role) *model.RoleResponse {
+	permissions := make([]string, len(role.Permissions))
+	for i, permission := range role.Permissions {
+		permissions[i] = permission.ID
+	}
+
+	return &model.RoleResponse{
+		ID:          role.ID,
+		Name:        role.Name,
+		Description: role.Description,
+		Permissions: permissions,
+	}
+}
+
+// RolesToGrants flattens roles into their ids and the union of their permissions
+func RolesToGrants(roles []entity.Role) ([]string, []string) {
+	roleIds := make([]string, 0, len(roles))
+	permissions := make([]string, 0)
+	seen := make(map[string]bool)
+
+	for _, role := range roles {
+		roleIds = append(roleIds, role.ID)
+		for _, permission := range role.Permissions {
+			if !seen[permission.ID] {
+				seen[permission.ID] = true
+				permissions = append(permissions, permission.ID)
+			}
+		}
+	}
+
+	return roleIds, permissions
+}
@@ -0,0 +1,18 @@
+package converter
+
+import (
+	"go-clean-template/internal/entity"
+	"go-clean-template/internal/model"
+)
+
+func SessionToResponse(session *entity.Session, currentSessionId string) *model.SessionResponse {
+	return &model.SessionResponse{
+		ID:         session.ID,
+		UserAgent:  session.UserAgent,
+		IPAddress:  session.IPAddress,
+		Current:    session.ID == currentSessionId,
+		CreatedAt:  session.CreatedAt,
+		LastSeenAt: session.LastSeenAt,
+		ExpiresAt:  session.ExpiresAt,
+	}
+}
@@ -0,0 +1,64 @@
+package converter
+
+import (
+	"strings"
+
+	"go-clean-template/internal/entity"
+)
+
+// vCardLineLength is the longest a vCard content line may be before it is folded, in octets
+const vCardLineLength = 75
+
+var vCardEscaper = strings.NewReplacer(`\`, `\\`, ",", `\,`, ";", `\;`, "\r\n", `\n`, "\n", `\n`)
+
+// ContactsToVCard renders contacts and their addresses as vCard 3.0 (RFC 2426) cards, cards can not tell
+// trashed ones apart so those are left out
+func ContactsToVCard(contacts []entity.Contact) []byte {
+	builder := new(strings.Builder)
+	for _, contact := range contacts {
+		if contact.DeletedAt != 0 {
+			continue
+		}
+		writeVCardLine(builder, "BEGIN:VCARD")
+		writeVCardLine(builder, "VERSION:3.0")
+		writeVCardLine(builder, "UID:"+vCardEscaper.Replace(contact.ID))
+		writeVCardLine(builder, "N:"+vCardEscaper.Replace(contact.LastName)+";"+vCardEscaper.Replace(contact.FirstName)+";;;")
+		writeVCardLine(builder, "FN:"+vCardEscaper.Replace(strings.TrimSpace(contact.FirstName+" "+contact.LastName)))
+		if contact.Email != "" {
+			writeVCardLine(builder, "EMAIL;TYPE=INTERNET:"+vCardEscaper.Replace(contact.Email))
+		}
+		if contact.Phone != "" {
+			writeVCardLine(builder, "TEL;TYPE=VOICE:"+vCardEscaper.Replace(contact.Phone))
+		}
+		for _, address := range contact.Addresses {
+			if address.DeletedAt != 0 {
+				continue
+			}
+			writeVCardLine(builder, "ADR:;;"+strings.Join([]string{
+				vCardEscaper.Replace(address.Street),
+				vCardEscaper.Replace(address.City),
+				vCardEscaper.Replace(address.Province),
+				vCardEscaper.Replace(address.PostalCode),
+				vCardEscaper.Replace(address.Country),
+			}, ";"))
+		}
+		writeVCardLine(builder, "END:VCARD")
+	}
+	return []byte(builder.String())
+}
+
+// writeVCardLine folds long lines by continuing them on lines that start with a space, without
+// splitting a UTF-8 character
+func writeVCardLine(builder *strings.Builder, line string) {
+	limit := vCardLineLength
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		builder.WriteString(line[:cut] + "\r\n ")
+		line = line[cut:]
+		limit = vCardLineLength - 1
+	}
+	builder.WriteString(line + "\r\n")
+}
@@ -0,0 +1,25 @@
+package model
+
+const (
+	DataExportPending = "pending"
+	DataExportReady   = "ready"
+)
+
+type DataExportResponse struct {
+	ID          string `json:"id"`
+	Status      string `json:"status"`
+	CreatedAt   int64  `json:"created_at"`
+	CompletedAt int64  `json:"completed_at,omitempty"`
+	ExpiresAt   int64  `json:"expires_at,omitempty"`
+}
+
+type CreateDataExportRequest struct {
+	UserId    string `json:"-" validate:"required"`
+	UserAgent string `json:"-"`
+	IPAddress string `json:"-"`
+}
+
+type GetDataExportRequest struct {
+	UserId string `json:"-" validate:"required"`
+	ID     string `json:"-" validate:"required,max=100,uuid"`
+}
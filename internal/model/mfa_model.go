@@ -0,0 +1,32 @@
+package model
+
+type TotpEnrollmentResponse struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+type RecoveryCodesResponse struct {
+	Codes []string `json:"codes"`
+}
+
+type EnrollTotpRequest struct {
+	ID string `json:"-" validate:"required,max=100"`
+}
+
+type ConfirmTotpRequest struct {
+	ID   string `json:"-" validate:"required,max=100"`
+	Code string `json:"code" validate:"required,numeric,len=6"`
+}
+
+// DisableTotpRequest and RegenerateRecoveryCodesRequest accept either a TOTP code or a recovery code
+type DisableTotpRequest struct {
+	ID        string `json:"-" validate:"required,max=100"`
+	Code      string `json:"code" validate:"required,max=20"`
+	IPAddress string `json:"-"`
+}
+
+type RegenerateRecoveryCodesRequest struct {
+	ID        string `json:"-" validate:"required,max=100"`
+	Code      string `json:"code" validate:"required,max=20"`
+	IPAddress string `json:"-"`
+}
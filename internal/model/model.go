@@ -6,6 +6,8 @@ type WebResponse[T any] struct {
 
 type ErrorResponse struct {
 	Errors string `json:"errors"`
+	// Fields lists the reasons per rejected field, only for field validation errors
+	Fields map[string][]string `json:"fields,omitempty"`
 }
 
 type PageResponse[T any] struct {
@@ -18,4 +20,7 @@ type PageMetadata struct {
 	Size      int   `json:"size"`
 	TotalItem int64 `json:"total_item"`
 	TotalPage int64 `json:"total_page"`
+	// Next and Previous are cursors to the neighbouring pages where the list supports them
+	Next     string `json:"next,omitempty"`
+	Previous string `json:"previous,omitempty"`
 }
@@ -0,0 +1,12 @@
+package model
+
+type OidcAuthorizationResponse struct {
+	AuthorizationURL string `json:"authorization_url"`
+}
+
+type OidcCallbackRequest struct {
+	Code      string `json:"code" validate:"required,max=2048"`
+	State     string `json:"state" validate:"required,max=100"`
+	UserAgent string `json:"-" validate:"max=255"`
+	IPAddress string `json:"-" validate:"max=45"`
+}
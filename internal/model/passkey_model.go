@@ -0,0 +1,125 @@
+package model
+
+// WebAuthn ceremonies
+const (
+	WebAuthnRegistration = "registration"
+	WebAuthnLogin        = "login"
+)
+
+type PasskeyResponse struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	LastUsedAt int64  `json:"last_used_at,omitempty"`
+	CreatedAt  int64  `json:"created_at"`
+}
+
+// The options and credentials below mirror the JSON of the WebAuthn browser API with binary fields base64url
+// encoded, so they pass to and from navigator.credentials unchanged
+
+// PasskeyCreationOptions is the publicKey argument of navigator.credentials.create
+type PasskeyCreationOptions struct {
+	Challenge              string                        `json:"challenge"`
+	RP                     PasskeyRelyingParty           `json:"rp"`
+	User                   PasskeyUser                   `json:"user"`
+	PubKeyCredParams       []PasskeyCredentialParameters `json:"pubKeyCredParams"`
+	Timeout                int64                         `json:"timeout"`
+	ExcludeCredentials     []PasskeyDescriptor           `json:"excludeCredentials"`
+	AuthenticatorSelection PasskeyAuthenticatorSelection `json:"authenticatorSelection"`
+	Attestation            string                        `json:"attestation"`
+}
+
+type PasskeyRelyingParty struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type PasskeyUser struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+}
+
+type PasskeyCredentialParameters struct {
+	Type string `json:"type"`
+	Alg  int64  `json:"alg"`
+}
+
+type PasskeyDescriptor struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+type PasskeyAuthenticatorSelection struct {
+	ResidentKey      string `json:"residentKey"`
+	UserVerification string `json:"userVerification"`
+}
+
+// PasskeyRequestOptions is the publicKey argument of navigator.credentials.get
+type PasskeyRequestOptions struct {
+	Challenge        string              `json:"challenge"`
+	RPID             string              `json:"rpId"`
+	Timeout          int64               `json:"timeout"`
+	AllowCredentials []PasskeyDescriptor `json:"allowCredentials,omitempty"`
+	UserVerification string              `json:"userVerification"`
+}
+
+// PasskeyAttestation is the credential returned by navigator.credentials.create
+type PasskeyAttestation struct {
+	ID       string                     `json:"id" validate:"required,max=1400"`
+	Type     string                     `json:"type" validate:"required,eq=public-key"`
+	Response PasskeyAttestationResponse `json:"response" validate:"required"`
+}
+
+type PasskeyAttestationResponse struct {
+	ClientDataJSON    string `json:"clientDataJSON" validate:"required,max=4096"`
+	AttestationObject string `json:"attestationObject" validate:"required,max=65536"`
+}
+
+// PasskeyAssertion is the credential returned by navigator.credentials.get
+type PasskeyAssertion struct {
+	ID       string                   `json:"id" validate:"required,max=1400"`
+	Type     string                   `json:"type" validate:"required,eq=public-key"`
+	Response PasskeyAssertionResponse `json:"response" validate:"required"`
+}
+
+type PasskeyAssertionResponse struct {
+	ClientDataJSON    string `json:"clientDataJSON" validate:"required,max=4096"`
+	AuthenticatorData string `json:"authenticatorData" validate:"required,max=4096"`
+	Signature         string `json:"signature" validate:"required,max=1024"`
+	UserHandle        string `json:"userHandle" validate:"max=100"`
+}
+
+type BeginPasskeyRegistrationRequest struct {
+	UserId string `json:"-" validate:"required,max=100"`
+}
+
+type FinishPasskeyRegistrationRequest struct {
+	UserId     string             `json:"-" validate:"required,max=100"`
+	Name       string             `json:"name" validate:"max=100"`
+	Credential PasskeyAttestation `json:"credential" validate:"required"`
+	UserAgent  string             `json:"-"`
+	IPAddress  string             `json:"-"`
+}
+
+type ListPasskeyRequest struct {
+	UserId string `json:"-" validate:"required"`
+}
+
+type DeletePasskeyRequest struct {
+	UserId    string `json:"-" validate:"required"`
+	ID        string `json:"-" validate:"required,max=100,uuid"`
+	UserAgent string `json:"-"`
+	IPAddress string `json:"-"`
+}
+
+// BeginPasskeyLoginRequest starts a login, ID is optional and limits it to the passkeys of that account,
+// without it any discoverable passkey of the relying party can answer
+type BeginPasskeyLoginRequest struct {
+	ID string `json:"id" validate:"max=100"`
+}
+
+type LoginPasskeyRequest struct {
+	Credential PasskeyAssertion `json:"credential" validate:"required"`
+	UserAgent  string           `json:"-"`
+	IPAddress  string           `json:"-"`
+}
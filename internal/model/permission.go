@@ -0,0 +1,19 @@
+package model
+
+// Permissions checked by routes and use cases, they are seeded by the roles migration
+const (
+	PermissionUsersRead        = "users:read"
+	PermissionUsersWrite       = "users:write"
+	PermissionUsersImpersonate = "users:impersonate"
+	PermissionRolesRead        = "roles:read"
+	PermissionRolesWrite       = "roles:write"
+)
+
+// RoleAdmin is the seeded role holding every permission
+const RoleAdmin = "admin"
+
+// Scopes an API key can be limited to, keep them in sync with the validation of CreateApiKeyRequest
+const (
+	ScopeContactsRead  = "contacts:read"
+	ScopeContactsWrite = "contacts:write"
+)
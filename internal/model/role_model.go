@@ -0,0 +1,26 @@
+package model
+
+type RoleResponse struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+}
+
+type ListUserRoleRequest struct {
+	UserId string `json:"-" validate:"required,max=100"`
+}
+
+// GrantRoleRequest and RevokeRoleRequest carry the acting admin, who may only hand out
+// permissions they hold themselves
+type GrantRoleRequest struct {
+	Actor  *Auth  `json:"-" validate:"required"`
+	UserId string `json:"-" validate:"required,max=100"`
+	RoleId string `json:"role_id" validate:"required,max=100"`
+}
+
+type RevokeRoleRequest struct {
+	Actor  *Auth  `json:"-" validate:"required"`
+	UserId string `json:"-" validate:"required,max=100"`
+	RoleId string `json:"-" validate:"required,max=100"`
+}
@@ -0,0 +1,25 @@
+package model
+
+type SessionResponse struct {
+	ID         string `json:"id"`
+	UserAgent  string `json:"user_agent"`
+	IPAddress  string `json:"ip_address"`
+	Current    bool   `json:"current"`
+	CreatedAt  int64  `json:"created_at"`
+	LastSeenAt int64  `json:"last_seen_at"`
+	ExpiresAt  int64  `json:"expires_at"`
+}
+
+type ListSessionRequest struct {
+	UserId    string `json:"-" validate:"required"`
+	SessionId string `json:"-"`
+}
+
+type RevokeSessionRequest struct {
+	UserId string `json:"-" validate:"required"`
+	ID     string `json:"-" validate:"required,max=100,uuid"`
+}
+
+type RevokeAllSessionRequest struct {
+	UserId string `json:"-" validate:"required"`
+}
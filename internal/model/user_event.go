@@ -1,10 +1,20 @@
 package model
 
 type UserEvent struct {
-	ID        string `json:"id,omitempty"`
-	Name      string `json:"name,omitempty"`
-	CreatedAt int64  `json:"created_at,omitempty"`
-	UpdatedAt int64  `json:"updated_at,omitempty"`
+	ID         string `json:"id,omitempty"`
+	Name       string `json:"name,omitempty"`
+	Email      string `json:"email,omitempty"`
+	DisabledAt int64  `json:"disabled_at,omitempty"`
+	// DeleteAfter is set while the account is scheduled for deletion
+	DeleteAfter int64 `json:"delete_after,omitempty"`
+	// DeletedAt is set when the event reports the account deleted, consumers should drop their copies
+	DeletedAt int64 `json:"deleted_at,omitempty"`
+	CreatedAt int64 `json:"created_at,omitempty"`
+	UpdatedAt int64 `json:"updated_at,omitempty"`
+	// LockedUntil is set when the event reports an account locked out after too many failed logins
+	LockedUntil int64 `json:"locked_until,omitempty"`
+	// PasswordChangedAt is set when the event reports the user changed their password
+	PasswordChangedAt int64 `json:"password_changed_at,omitempty"`
 }
 
 func (u *UserEvent) GetId() string {
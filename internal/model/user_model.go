@@ -1,38 +1,134 @@
 package model
 
 type UserResponse struct {
-	ID        string `json:"id,omitempty"`
-	Name      string `json:"name,omitempty"`
-	Token     string `json:"token,omitempty"`
-	CreatedAt int64  `json:"created_at,omitempty"`
-	UpdatedAt int64  `json:"updated_at,omitempty"`
+	ID              string `json:"id,omitempty"`
+	Name            string `json:"name,omitempty"`
+	Email           string `json:"email,omitempty"`
+	EmailVerifiedAt int64  `json:"email_verified_at,omitempty"`
+	DisabledAt      int64  `json:"disabled_at,omitempty"`
+	DeleteAfter     int64  `json:"delete_after,omitempty"`
+	Token           string `json:"token,omitempty"`
+	RefreshToken    string `json:"refresh_token,omitempty"`
+	ExpiresAt       int64  `json:"expires_at,omitempty"`
+	MfaRequired     bool   `json:"mfa_required,omitempty"`
+	MfaToken        string `json:"mfa_token,omitempty"`
+	CreatedAt       int64  `json:"created_at,omitempty"`
+	UpdatedAt       int64  `json:"updated_at,omitempty"`
 }
 
 type VerifyUserRequest struct {
-	Token string `validate:"required,max=100"`
+	Token string `validate:"required,max=2048"`
 }
 
 type RegisterUserRequest struct {
 	ID       string `json:"id" validate:"required,max=100"`
 	Password string `json:"password" validate:"required,max=100"`
 	Name     string `json:"name" validate:"required,max=100"`
+	Email    string `json:"email" validate:"required,max=200,email"`
 }
 
+// UpdateUserRequest changes the profile, Password is only read to refuse clients that still send one here
 type UpdateUserRequest struct {
-	ID       string `json:"-" validate:"required,max=100"`
-	Password string `json:"password,omitempty" validate:"max=100"`
-	Name     string `json:"name,omitempty" validate:"max=100"`
+	ID       string  `json:"-" validate:"required,max=100"`
+	Name     string  `json:"name,omitempty" validate:"max=100"`
+	Password *string `json:"password,omitempty" swaggerignore:"true"`
+}
+
+// ChangePasswordRequest sets a new password, confirmed with the current one, SessionId is the session
+// that stays logged in
+type ChangePasswordRequest struct {
+	ID              string `json:"-" validate:"required,max=100"`
+	SessionId       string `json:"-" validate:"max=100"`
+	CurrentPassword string `json:"current_password" validate:"required,max=100"`
+	Password        string `json:"password" validate:"required,max=100"`
+	UserAgent       string `json:"-"`
+	IPAddress       string `json:"-"`
+}
+
+// DeleteUserRequest asks for the account to be deleted, confirmed with the password or a two-factor code
+type DeleteUserRequest struct {
+	ID        string `json:"-" validate:"required,max=100"`
+	SessionId string `json:"-" validate:"max=100"`
+	Password  string `json:"password,omitempty" validate:"max=100"`
+	Code      string `json:"code,omitempty" validate:"max=20"`
+	UserAgent string `json:"-"`
+	IPAddress string `json:"-"`
+}
+
+type CancelUserDeletionRequest struct {
+	ID        string `json:"-" validate:"required,max=100"`
+	UserAgent string `json:"-"`
+	IPAddress string `json:"-"`
 }
 
 type LoginUserRequest struct {
-	ID       string `json:"id" validate:"required,max=100"`
-	Password string `json:"password" validate:"required,max=100"`
+	ID        string `json:"id" validate:"required,max=100"`
+	Password  string `json:"password" validate:"required,max=100"`
+	UserAgent string `json:"-"`
+	IPAddress string `json:"-"`
+}
+
+// LoginMfaUserRequest completes a login that is waiting for its second factor,
+// Code is either a TOTP code or a recovery code
+type LoginMfaUserRequest struct {
+	MfaToken  string `json:"mfa_token" validate:"required,max=100"`
+	Code      string `json:"code" validate:"required,max=20"`
+	UserAgent string `json:"-"`
+	IPAddress string `json:"-"`
+}
+
+type RefreshUserRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required,max=200"`
+	UserAgent    string `json:"-"`
+	IPAddress    string `json:"-"`
+}
+
+type RenewUserRequest struct {
+	ID        string `validate:"required,max=100"`
+	SessionId string `validate:"required,max=100"`
 }
 
 type LogoutUserRequest struct {
-	ID string `json:"id" validate:"required,max=100"`
+	ID        string `json:"id" validate:"required,max=100"`
+	SessionId string `json:"-"`
 }
 
 type GetUserRequest struct {
 	ID string `json:"id" validate:"required,max=100"`
 }
+
+type ForgotPasswordRequest struct {
+	ID string `json:"id" validate:"required,max=100"`
+}
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token" validate:"required,max=100"`
+	Password string `json:"password" validate:"required,max=100"`
+}
+
+type VerifyEmailRequest struct {
+	Token string `json:"token" validate:"required,max=100"`
+}
+
+type ResendVerificationRequest struct {
+	Email string `json:"email" validate:"required,max=200,email"`
+}
+
+// RequestMagicLinkRequest asks for a login link to be sent to the email of the account
+type RequestMagicLinkRequest struct {
+	ID        string `json:"id" validate:"required,max=100"`
+	IPAddress string `json:"-"`
+}
+
+// MagicLinkResponse hands the requesting client the device token the link has to be redeemed with
+type MagicLinkResponse struct {
+	DeviceToken string `json:"device_token"`
+	ExpiresAt   int64  `json:"expires_at"`
+}
+
+type LoginMagicLinkRequest struct {
+	Token       string `json:"token" validate:"required,max=100"`
+	DeviceToken string `json:"device_token" validate:"required,max=100"`
+	UserAgent   string `json:"-"`
+	IPAddress   string `json:"-"`
+}
@@ -5,6 +5,7 @@ import (
 
 	"go.uber.org/zap"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type AddressRepository struct {
@@ -19,13 +20,55 @@ func NewAddressRepository(log *zap.SugaredLogger) *AddressRepository {
 }
 
 func (r *AddressRepository) FindByIdAndContactId(tx *gorm.DB, address *entity.Address, id string, contactId string) error {
-	return tx.Where("id = ? AND contact_id = ?", id, contactId).First(address).Error
+	return tx.Where("id = ? AND contact_id = ? AND deleted_at = 0", id, contactId).First(address).Error
 }
 
 func (r *AddressRepository) FindAllByContactId(tx *gorm.DB, contactId string) ([]entity.Address, error) {
 	var addresses []entity.Address
-	if err := tx.Where("contact_id = ?", contactId).Find(&addresses).Error; err != nil {
+	if err := tx.Where("contact_id = ? AND deleted_at = 0", contactId).Find(&addresses).Error; err != nil {
 		return nil, err
 	}
 	return addresses, nil
 }
+
+func (r *AddressRepository) FindTrashedByIdAndContactId(tx *gorm.DB, address *entity.Address, id string, contactId string) error {
+	return tx.Where("id = ? AND contact_id = ? AND deleted_at <> 0", id, contactId).First(address).Error
+}
+
+// FindAllTrashedByContactId returns the trashed addresses of the contact, the last trashed first
+func (r *AddressRepository) FindAllTrashedByContactId(tx *gorm.DB, contactId string) ([]entity.Address, error) {
+	var addresses []entity.Address
+	if err := tx.Where("contact_id = ? AND deleted_at <> 0", contactId).Order("deleted_at desc, id").Find(&addresses).Error; err != nil {
+		return nil, err
+	}
+	return addresses, nil
+}
+
+// SetDeletedAt moves the address to the trash, or out of it with zero, leaving updated_at as it is
+func (r *AddressRepository) SetDeletedAt(tx *gorm.DB, address *entity.Address, deletedAt int64) error {
+	return tx.Model(address).UpdateColumn("deleted_at", deletedAt).Error
+}
+
+// UpdateDeletedAtByContactId moves the addresses of the contact deleted at from to deleted at to, so they go to
+// the trash with the contact and come back with it, and returns the addresses it moved
+func (r *AddressRepository) UpdateDeletedAtByContactId(tx *gorm.DB, contactId string, from int64, to int64) ([]entity.Address, error) {
+	var addresses []entity.Address
+	err := tx.Model(&addresses).Clauses(clause.Returning{}).Where("contact_id = ? AND deleted_at = ?", contactId, from).
+		UpdateColumn("deleted_at", to).Error
+	return addresses, err
+}
+
+// DeleteTrashedBefore permanently deletes the addresses trashed before the time, along with every address
+// of the contacts trashed before it
+func (r *AddressRepository) DeleteTrashedBefore(db *gorm.DB, before int64) (int64, error) {
+	contactIds := db.Model(new(entity.Contact)).Select("id").Where("deleted_at <> 0 AND deleted_at < ?", before)
+	result := db.Where("(deleted_at <> 0 AND deleted_at < ?) OR contact_id IN (?)", before, contactIds).Delete(new(entity.Address))
+	return result.RowsAffected, result.Error
+}
+
+// DeleteAllByUserId removes the addresses of every contact the user owns
+func (r *AddressRepository) DeleteAllByUserId(db *gorm.DB, userId string) (int64, error) {
+	contactIds := db.Model(new(entity.Contact)).Select("id").Where("user_id = ?", userId)
+	result := db.Where("contact_id IN (?)", contactIds).Delete(new(entity.Address))
+	return result.RowsAffected, result.Error
+}
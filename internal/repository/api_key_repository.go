@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"go-clean-template/internal/entity"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type ApiKeyRepository struct {
+	Repository[entity.ApiKey]
+	Log *zap.SugaredLogger
+}
+
+func NewApiKeyRepository(log *zap.SugaredLogger) *ApiKeyRepository {
+	return &ApiKeyRepository{
+		Log: log,
+	}
+}
+
+func (r *ApiKeyRepository) FindByKeyHash(db *gorm.DB, apiKey *entity.ApiKey, keyHash string) error {
+	return db.Where("key_hash = ?", keyHash).Take(apiKey).Error
+}
+
+func (r *ApiKeyRepository) FindAllByUserId(db *gorm.DB, userId string) ([]entity.ApiKey, error) {
+	var apiKeys []entity.ApiKey
+	err := db.Where("user_id = ?", userId).Order("created_at desc").Find(&apiKeys).Error
+	return apiKeys, err
+}
+
+// CountActiveByUserId counts the keys of the user that have not expired
+func (r *ApiKeyRepository) CountActiveByUserId(db *gorm.DB, userId string, now int64) (int64, error) {
+	var total int64
+	err := db.Model(new(entity.ApiKey)).Where("user_id = ? AND (expires_at = 0 OR expires_at > ?)", userId, now).Count(&total).Error
+	return total, err
+}
+
+func (r *ApiKeyRepository) UpdateLastUsedAt(db *gorm.DB, id string, lastUsedAt int64) error {
+	return db.Model(new(entity.ApiKey)).Where("id = ?", id).Update("last_used_at", lastUsedAt).Error
+}
+
+func (r *ApiKeyRepository) DeleteByIdAndUserId(db *gorm.DB, id string, userId string) (int64, error) {
+	result := db.Where("id = ? AND user_id = ?", id, userId).Delete(new(entity.ApiKey))
+	return result.RowsAffected, result.Error
+}
+
+func (r *ApiKeyRepository) DeleteByUserId(db *gorm.DB, userId string) error {
+	return db.Where("user_id = ?", userId).Delete(new(entity.ApiKey)).Error
+}
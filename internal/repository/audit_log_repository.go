@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"go-clean-template/internal/entity"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type AuditLogRepository struct {
+	Repository[entity.AuditLog]
+	Log *zap.SugaredLogger
+}
+
+func NewAuditLogRepository(log *zap.SugaredLogger) *AuditLogRepository {
+	return &AuditLogRepository{
+		Log: log,
+	}
+}
+
+func (r *AuditLogRepository) FindAllByUserId(db *gorm.DB, userId string) ([]entity.AuditLog, error) {
+	var auditLogs []entity.AuditLog
+	err := db.Where("user_id = ?", userId).Order("created_at").Find(&auditLogs).Error
+	return auditLogs, err
+}
@@ -1,13 +1,33 @@
 package repository
 
 import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"unicode"
+
 	"go-clean-template/internal/entity"
 	"go-clean-template/internal/model"
 
 	"go.uber.org/zap"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// A q search matches contacts three ways: every word as a prefix of a word in the search vector, the whole
+// query by trigram similarity to the name to catch typos, and any word of letters sounding like the first
+// or last name
+const (
+	contactSearchPhonetic = "array_remove(array(select dmetaphone(word) from unnest(string_to_array(@names, ' ')) word), '')"
+	contactSearchMatch    = "(search_vector @@ to_tsquery('simple', @tsquery) OR @query <% search_name OR search_phonetic && " + contactSearchPhonetic + ")"
+	contactSearchRank     = "ts_rank(search_vector, to_tsquery('simple', @tsquery)) + word_similarity(@query, search_name)" +
+		" + case when search_phonetic && " + contactSearchPhonetic + " then 0.1 else 0 end"
 )
 
+// ErrInvalidCursor is returned for a cursor that was not issued for the sort of the request
+var ErrInvalidCursor = errors.New("invalid cursor")
+
 type ContactRepository struct {
 	Repository[entity.Contact]
 	Log *zap.SugaredLogger
@@ -20,26 +40,115 @@ func NewContactRepository(log *zap.SugaredLogger) *ContactRepository {
 }
 
 func (r *ContactRepository) FindByIdAndUserId(db *gorm.DB, contact *entity.Contact, id string, userId string) error {
-	return db.Where("id = ? AND user_id = ?", id, userId).Take(contact).Error
+	return db.Where("id = ? AND user_id = ? AND deleted_at = 0", id, userId).Take(contact).Error
+}
+
+func (r *ContactRepository) FindTrashedByIdAndUserId(db *gorm.DB, contact *entity.Contact, id string, userId string) error {
+	return db.Where("id = ? AND user_id = ? AND deleted_at <> 0", id, userId).Take(contact).Error
 }
 
-func (r *ContactRepository) Search(db *gorm.DB, request *model.SearchContactRequest) ([]entity.Contact, int64, error) {
+// SetDeletedAt moves the contact to the trash, or out of it with zero, leaving updated_at as it is
+func (r *ContactRepository) SetDeletedAt(db *gorm.DB, contact *entity.Contact, deletedAt int64) error {
+	return db.Model(contact).UpdateColumn("deleted_at", deletedAt).Error
+}
+
+// SearchTrash returns a page of the trashed contacts of the user, the last trashed first, and their total
+func (r *ContactRepository) SearchTrash(db *gorm.DB, request *model.SearchTrashedContactRequest) ([]entity.Contact, int64, error) {
 	var contacts []entity.Contact
-	if err := db.Scopes(r.FilterContact(request)).Offset((request.Page - 1) * request.Size).Limit(request.Size).Find(&contacts).Error; err != nil {
+	if err := db.Where("user_id = ? AND deleted_at <> 0", request.UserId).Order("deleted_at desc, id").
+		Offset((request.Page - 1) * request.Size).Limit(request.Size).Find(&contacts).Error; err != nil {
 		return nil, 0, err
 	}
 
 	var total int64 = 0
-	if err := db.Model(&entity.Contact{}).Scopes(r.FilterContact(request)).Count(&total).Error; err != nil {
+	if err := db.Model(&entity.Contact{}).Where("user_id = ? AND deleted_at <> 0", request.UserId).Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
 	return contacts, total, nil
 }
 
+// Search returns a page of contacts and whether more follow it, one contact beyond the page is read to tell
+func (r *ContactRepository) Search(db *gorm.DB, request *model.SearchContactRequest) ([]entity.Contact, bool, error) {
+	var contacts []entity.Contact
+	if err := db.Scopes(r.FilterContact(request), r.OrderContact(request)).Offset((request.Page - 1) * request.Size).Limit(request.Size + 1).Find(&contacts).Error; err != nil {
+		return nil, false, err
+	}
+
+	if len(contacts) > request.Size {
+		return contacts[:request.Size], true, nil
+	}
+	return contacts, false, nil
+}
+
+// SearchByCursor returns the page of contacts right after the cursor, or right before it, and whether more
+// follow in that direction. It seeks on the sort keys instead of skipping rows, so pages stay consistent
+// while contacts are added or removed
+func (r *ContactRepository) SearchByCursor(db *gorm.DB, request *model.SearchContactRequest, cursor *model.ContactCursor, before bool) ([]entity.Contact, bool, error) {
+	keys := contactSortKeys(request)
+	if cursor.ID == "" || cursor.Sort != strings.Join(request.Sort, ",") || len(cursor.Values) != len(keys)-1 {
+		return nil, false, ErrInvalidCursor
+	}
+
+	// the contact the cursor marks, with the values decoded into its fields
+	marked := &entity.Contact{ID: cursor.ID}
+	for i, key := range keys[:len(keys)-1] {
+		if err := json.Unmarshal(cursor.Values[i], contactSortField(marked, key.Column.Name)); err != nil {
+			return nil, false, ErrInvalidCursor
+		}
+	}
+
+	// a page before the cursor is read backwards from it and turned around
+	if before {
+		for i := range keys {
+			keys[i].Desc = !keys[i].Desc
+		}
+	}
+
+	var contacts []entity.Contact
+	if err := db.Scopes(r.FilterContact(request)).Where(contactKeyset(keys, marked)).
+		Clauses(contactOrderBy(keys)).Limit(request.Size + 1).Find(&contacts).Error; err != nil {
+		return nil, false, err
+	}
+
+	more := len(contacts) > request.Size
+	if more {
+		contacts = contacts[:request.Size]
+	}
+	if before {
+		slices.Reverse(contacts)
+	}
+
+	return contacts, more, nil
+}
+
+func (r *ContactRepository) Count(db *gorm.DB, request *model.SearchContactRequest) (int64, error) {
+	var total int64 = 0
+	err := db.Model(&entity.Contact{}).Scopes(r.FilterContact(request)).Count(&total).Error
+	return total, err
+}
+
+// Cursor returns the cursor marking the contact in the sort of the request
+func (r *ContactRepository) Cursor(contact *entity.Contact, request *model.SearchContactRequest) (*model.ContactCursor, error) {
+	keys := contactSortKeys(request)
+	cursor := &model.ContactCursor{Sort: strings.Join(request.Sort, ","), ID: contact.ID}
+	for _, key := range keys[:len(keys)-1] {
+		value, err := json.Marshal(contactSortField(contact, key.Column.Name))
+		if err != nil {
+			return nil, err
+		}
+		cursor.Values = append(cursor.Values, value)
+	}
+	return cursor, nil
+}
+
 func (r *ContactRepository) FilterContact(request *model.SearchContactRequest) func(tx *gorm.DB) *gorm.DB {
 	return func(tx *gorm.DB) *gorm.DB {
-		tx = tx.Where("user_id = ?", request.UserId)
+		tx = tx.Where("user_id = ? AND deleted_at = 0", request.UserId)
+
+		if vars := contactSearchVars(request.Query); vars != nil {
+			tx = tx.Where(contactSearchMatch, vars)
+		}
 
 		if name := request.Name; name != "" {
 			name = "%" + name + "%"
@@ -59,3 +168,147 @@ func (r *ContactRepository) FilterContact(request *model.SearchContactRequest) f
 		return tx
 	}
 }
+
+// OrderContact sorts by the keys of the request, without any the most relevant contacts come first when
+// searching with q and the oldest otherwise. The id breaks ties so pages do not overlap
+func (r *ContactRepository) OrderContact(request *model.SearchContactRequest) func(tx *gorm.DB) *gorm.DB {
+	return func(tx *gorm.DB) *gorm.DB {
+		if len(request.Sort) == 0 {
+			if vars := contactSearchVars(request.Query); vars != nil {
+				return tx.Clauses(clause.OrderBy{Expression: clause.NamedExpr{SQL: contactSearchRank + " desc, id", Vars: []any{vars}}})
+			}
+		}
+
+		return tx.Clauses(contactOrderBy(contactSortKeys(request)))
+	}
+}
+
+// contactSortKeys returns the columns contacts are sorted by, the id always comes last
+func contactSortKeys(request *model.SearchContactRequest) []clause.OrderByColumn {
+	keys := make([]clause.OrderByColumn, 0, len(request.Sort)+2)
+	for _, key := range request.Sort {
+		column, desc := strings.CutPrefix(key, "-")
+		keys = append(keys, clause.OrderByColumn{Column: clause.Column{Name: column}, Desc: desc})
+	}
+	if len(keys) == 0 {
+		keys = append(keys, clause.OrderByColumn{Column: clause.Column{Name: "created_at"}})
+	}
+	return append(keys, clause.OrderByColumn{Column: clause.Column{Name: "id"}})
+}
+
+// contactSortColumn returns the column as contacts are sorted on it. last_name, email and phone may be NULL
+// and are read as an empty string, so a cursor seeks on them in the same order the page was sorted
+func contactSortColumn(column clause.Column) clause.Column {
+	switch column.Name {
+	case "last_name", "email", "phone":
+		return clause.Column{Name: "coalesce(" + column.Name + ", '')", Raw: true}
+	}
+	return column
+}
+
+func contactOrderBy(keys []clause.OrderByColumn) clause.OrderBy {
+	columns := make([]clause.OrderByColumn, len(keys))
+	for i, key := range keys {
+		columns[i] = clause.OrderByColumn{Column: contactSortColumn(key.Column), Desc: key.Desc}
+	}
+	return clause.OrderBy{Columns: columns}
+}
+
+// contactSortField returns a pointer to the field of the contact stored in the sort column
+func contactSortField(contact *entity.Contact, column string) any {
+	switch column {
+	case "first_name":
+		return &contact.FirstName
+	case "last_name":
+		return &contact.LastName
+	case "email":
+		return &contact.Email
+	case "phone":
+		return &contact.Phone
+	case "created_at":
+		return &contact.CreatedAt
+	case "updated_at":
+		return &contact.UpdatedAt
+	}
+	return &contact.ID
+}
+
+// contactKeyset matches the contacts sorted after the marked one: those past it on the first key, or level
+// on the first key and past it on the second, and so on
+func contactKeyset(keys []clause.OrderByColumn, marked *entity.Contact) clause.Expression {
+	conditions := make([]clause.Expression, len(keys))
+	for i, key := range keys {
+		level := make([]clause.Expression, 0, i+1)
+		for _, previous := range keys[:i] {
+			level = append(level, clause.Eq{Column: contactSortColumn(previous.Column), Value: contactSortValue(marked, previous.Column.Name)})
+		}
+
+		column, value := contactSortColumn(key.Column), contactSortValue(marked, key.Column.Name)
+		if key.Desc {
+			level = append(level, clause.Lt{Column: column, Value: value})
+		} else {
+			level = append(level, clause.Gt{Column: column, Value: value})
+		}
+		conditions[i] = clause.And(level...)
+	}
+	return clause.Or(conditions...)
+}
+
+func contactSortValue(contact *entity.Contact, column string) any {
+	switch field := contactSortField(contact, column).(type) {
+	case *int64:
+		return *field
+	case *string:
+		return *field
+	}
+	return nil
+}
+
+// contactSearchVars returns the bind variables of a q search, or nil when there is nothing to search for
+func contactSearchVars(query string) map[string]any {
+	words := strings.Fields(strings.ToLower(query))
+	if len(words) == 0 {
+		return nil
+	}
+
+	// words are quoted as lexemes so characters with a meaning in tsquery syntax are searched for as they are
+	escaper := strings.NewReplacer(`\`, `\\`, "'", "''")
+	terms := make([]string, len(words))
+	var names []string
+	for i, word := range words {
+		terms[i] = "'" + escaper.Replace(word) + "':*"
+
+		// emails and phone numbers do not sound like anything
+		if !strings.ContainsFunc(word, func(r rune) bool { return !unicode.IsLetter(r) }) {
+			names = append(names, word)
+		}
+	}
+
+	return map[string]any{
+		"query":   strings.Join(words, " "),
+		"tsquery": strings.Join(terms, " & "),
+		"names":   strings.Join(names, " "),
+	}
+}
+
+func (r *ContactRepository) DeleteAllByUserId(db *gorm.DB, userId string) (int64, error) {
+	result := db.Where("user_id = ?", userId).Delete(new(entity.Contact))
+	return result.RowsAffected, result.Error
+}
+
+// DeleteTrashedBefore permanently deletes the contacts trashed before the time, their addresses have to be
+// deleted first
+func (r *ContactRepository) DeleteTrashedBefore(db *gorm.DB, before int64) (int64, error) {
+	result := db.Where("deleted_at <> 0 AND deleted_at < ?", before).Delete(new(entity.Contact))
+	return result.RowsAffected, result.Error
+}
+
+// FindAllByUserIdWithAddresses loads every contact of the user along with its addresses, the trash included
+// since it is still held until the purge
+func (r *ContactRepository) FindAllByUserIdWithAddresses(db *gorm.DB, userId string) ([]entity.Contact, error) {
+	var contacts []entity.Contact
+	err := db.Preload("Addresses", func(tx *gorm.DB) *gorm.DB {
+		return tx.Order("created_at")
+	}).Where("user_id = ?", userId).Order("created_at").Find(&contacts).Error
+	return contacts, err
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"go-clean-template/internal/entity"
+	"go-clean-template/internal/model"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type DataExportRepository struct {
+	Repository[entity.DataExport]
+	Log *zap.SugaredLogger
+}
+
+func NewDataExportRepository(log *zap.SugaredLogger) *DataExportRepository {
+	return &DataExportRepository{
+		Log: log,
+	}
+}
+
+// FindByIdAndUserId loads the export without its archive
+func (r *DataExportRepository) FindByIdAndUserId(db *gorm.DB, dataExport *entity.DataExport, id string, userId string) error {
+	return db.Omit("archive").Where("id = ? AND user_id = ?", id, userId).Take(dataExport).Error
+}
+
+func (r *DataExportRepository) FindArchiveByIdAndUserId(db *gorm.DB, dataExport *entity.DataExport, id string, userId string) error {
+	return db.Where("id = ? AND user_id = ?", id, userId).Take(dataExport).Error
+}
+
+// FindPendingByUserId finds an export the user requested that is not built yet
+func (r *DataExportRepository) FindPendingByUserId(db *gorm.DB, dataExport *entity.DataExport, userId string) error {
+	return db.Omit("archive").Where("user_id = ? AND status = ?", userId, model.DataExportPending).Take(dataExport).Error
+}
+
+// FindPendingForUpdate claims the oldest pending export, exports other workers are building are skipped
+func (r *DataExportRepository) FindPendingForUpdate(db *gorm.DB, dataExport *entity.DataExport) error {
+	return db.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).Omit("archive").
+		Where("status = ?", model.DataExportPending).Order("created_at").Take(dataExport).Error
+}
+
+func (r *DataExportRepository) Complete(db *gorm.DB, id string, archive []byte, completedAt int64, expiresAt int64) error {
+	return db.Model(new(entity.DataExport)).Where("id = ?", id).Updates(map[string]any{
+		"status":       model.DataExportReady,
+		"archive":      archive,
+		"completed_at": completedAt,
+		"expires_at":   expiresAt,
+	}).Error
+}
+
+// DeleteExpired removes exports whose archive may no longer be downloaded
+func (r *DataExportRepository) DeleteExpired(db *gorm.DB, now int64) (int64, error) {
+	result := db.Where("status = ? AND expires_at <= ?", model.DataExportReady, now).Delete(new(entity.DataExport))
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"go-clean-template/internal/entity"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type EmailVerificationRepository struct {
+	Repository[entity.EmailVerification]
+	Log *zap.SugaredLogger
+}
+
+func NewEmailVerificationRepository(log *zap.SugaredLogger) *EmailVerificationRepository {
+	return &EmailVerificationRepository{
+		Log: log,
+	}
+}
+
+// FindByTokenHashForUpdate locks the row so a token can not be redeemed twice concurrently
+func (r *EmailVerificationRepository) FindByTokenHashForUpdate(db *gorm.DB, emailVerification *entity.EmailVerification, tokenHash string) error {
+	return db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("token_hash = ?", tokenHash).Take(emailVerification).Error
+}
+
+func (r *EmailVerificationRepository) FindLatestByUserId(db *gorm.DB, emailVerification *entity.EmailVerification, userId string) error {
+	return db.Where("user_id = ?", userId).Order("created_at DESC").Take(emailVerification).Error
+}
+
+// MarkUsedByUserId invalidates every outstanding token of a user
+func (r *EmailVerificationRepository) MarkUsedByUserId(db *gorm.DB, userId string, usedAt int64) error {
+	return db.Model(new(entity.EmailVerification)).
+		Where("user_id = ? AND used_at = 0", userId).
+		Update("used_at", usedAt).Error
+}
@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"go-clean-template/internal/entity"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type LoginAttemptRepository struct {
+	Repository[entity.LoginAttempt]
+	Log *zap.SugaredLogger
+}
+
+func NewLoginAttemptRepository(log *zap.SugaredLogger) *LoginAttemptRepository {
+	return &LoginAttemptRepository{
+		Log: log,
+	}
+}
+
+func (r *LoginAttemptRepository) FindByKey(db *gorm.DB, attempt *entity.LoginAttempt, key string) error {
+	return db.Where("key = ?", key).Take(attempt).Error
+}
+
+// FindByKeyForUpdate locks the row so concurrent failures are all counted
+func (r *LoginAttemptRepository) FindByKeyForUpdate(db *gorm.DB, attempt *entity.LoginAttempt, key string) error {
+	return db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("key = ?", key).Take(attempt).Error
+}
+
+func (r *LoginAttemptRepository) DeleteByKey(db *gorm.DB, key string) error {
+	return db.Where("key = ?", key).Delete(new(entity.LoginAttempt)).Error
+}
+
+// DeleteStale removes counters that are neither locked nor recent enough to count anymore
+func (r *LoginAttemptRepository) DeleteStale(db *gorm.DB, now int64, failedBefore int64) (int64, error) {
+	result := db.Where("locked_until <= ? AND last_failure_at < ?", now, failedBefore).Delete(new(entity.LoginAttempt))
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"go-clean-template/internal/entity"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type MagicLinkRepository struct {
+	Repository[entity.MagicLink]
+	Log *zap.SugaredLogger
+}
+
+func NewMagicLinkRepository(log *zap.SugaredLogger) *MagicLinkRepository {
+	return &MagicLinkRepository{
+		Log: log,
+	}
+}
+
+// FindByTokenHashForUpdate locks the row so a link can not be redeemed twice concurrently
+func (r *MagicLinkRepository) FindByTokenHashForUpdate(db *gorm.DB, magicLink *entity.MagicLink, tokenHash string) error {
+	return db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("token_hash = ?", tokenHash).Take(magicLink).Error
+}
+
+// MarkUsedByUserId invalidates every outstanding link of a user
+func (r *MagicLinkRepository) MarkUsedByUserId(db *gorm.DB, userId string, usedAt int64) error {
+	return db.Model(new(entity.MagicLink)).
+		Where("user_id = ? AND used_at = 0", userId).
+		Update("used_at", usedAt).Error
+}
@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"go-clean-template/internal/entity"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type MfaChallengeRepository struct {
+	Repository[entity.MfaChallenge]
+	Log *zap.SugaredLogger
+}
+
+func NewMfaChallengeRepository(log *zap.SugaredLogger) *MfaChallengeRepository {
+	return &MfaChallengeRepository{
+		Log: log,
+	}
+}
+
+// FindByTokenHashForUpdate locks the row so attempts are counted and the challenge is completed only once
+func (r *MfaChallengeRepository) FindByTokenHashForUpdate(db *gorm.DB, challenge *entity.MfaChallenge, tokenHash string) error {
+	return db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("token_hash = ?", tokenHash).Take(challenge).Error
+}
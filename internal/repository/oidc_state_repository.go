@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"go-clean-template/internal/entity"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OidcStateRepository struct {
+	Repository[entity.OidcState]
+	Log *zap.SugaredLogger
+}
+
+func NewOidcStateRepository(log *zap.SugaredLogger) *OidcStateRepository {
+	return &OidcStateRepository{
+		Log: log,
+	}
+}
+
+// FindByStateHashForUpdate locks the row so a state is redeemed only once
+func (r *OidcStateRepository) FindByStateHashForUpdate(db *gorm.DB, state *entity.OidcState, stateHash string) error {
+	return db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("state_hash = ?", stateHash).Take(state).Error
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"go-clean-template/internal/entity"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PasskeyRepository struct {
+	Repository[entity.Passkey]
+	Log *zap.SugaredLogger
+}
+
+func NewPasskeyRepository(log *zap.SugaredLogger) *PasskeyRepository {
+	return &PasskeyRepository{
+		Log: log,
+	}
+}
+
+func (r *PasskeyRepository) FindAllByUserId(db *gorm.DB, userId string) ([]entity.Passkey, error) {
+	var passkeys []entity.Passkey
+	err := db.Where("user_id = ?", userId).Order("created_at asc").Find(&passkeys).Error
+	return passkeys, err
+}
+
+func (r *PasskeyRepository) FindByIdAndUserId(db *gorm.DB, passkey *entity.Passkey, id string, userId string) error {
+	return db.Where("id = ? AND user_id = ?", id, userId).Take(passkey).Error
+}
+
+// FindByCredentialIdForUpdate locks the row so concurrent logins can not reuse a signature counter
+func (r *PasskeyRepository) FindByCredentialIdForUpdate(db *gorm.DB, passkey *entity.Passkey, credentialId string) error {
+	return db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("credential_id = ?", credentialId).Take(passkey).Error
+}
+
+func (r *PasskeyRepository) CountByCredentialId(db *gorm.DB, credentialId string) (int64, error) {
+	var total int64
+	err := db.Model(new(entity.Passkey)).Where("credential_id = ?", credentialId).Count(&total).Error
+	return total, err
+}
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"go-clean-template/internal/entity"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PasswordResetRepository struct {
+	Repository[entity.PasswordReset]
+	Log *zap.SugaredLogger
+}
+
+func NewPasswordResetRepository(log *zap.SugaredLogger) *PasswordResetRepository {
+	return &PasswordResetRepository{
+		Log: log,
+	}
+}
+
+// FindByTokenHashForUpdate locks the row so a token can not be redeemed twice concurrently
+func (r *PasswordResetRepository) FindByTokenHashForUpdate(db *gorm.DB, passwordReset *entity.PasswordReset, tokenHash string) error {
+	return db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("token_hash = ?", tokenHash).Take(passwordReset).Error
+}
+
+// MarkUsedByUserId invalidates every outstanding token of a user
+func (r *PasswordResetRepository) MarkUsedByUserId(db *gorm.DB, userId string, usedAt int64) error {
+	return db.Model(new(entity.PasswordReset)).
+		Where("user_id = ? AND used_at = 0", userId).
+		Update("used_at", usedAt).Error
+}
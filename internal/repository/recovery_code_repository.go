@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"go-clean-template/internal/entity"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RecoveryCodeRepository struct {
+	Repository[entity.RecoveryCode]
+	Log *zap.SugaredLogger
+}
+
+func NewRecoveryCodeRepository(log *zap.SugaredLogger) *RecoveryCodeRepository {
+	return &RecoveryCodeRepository{
+		Log: log,
+	}
+}
+
+// FindUnusedByUserIdAndCodeHashForUpdate locks the row so a code can not be redeemed twice concurrently
+func (r *RecoveryCodeRepository) FindUnusedByUserIdAndCodeHashForUpdate(db *gorm.DB, recoveryCode *entity.RecoveryCode, userId string, codeHash string) error {
+	return db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id = ? AND code_hash = ? AND used_at = 0", userId, codeHash).
+		Take(recoveryCode).Error
+}
+
+func (r *RecoveryCodeRepository) CountUnusedByUserId(db *gorm.DB, userId string) (int64, error) {
+	var total int64
+	err := db.Model(new(entity.RecoveryCode)).Where("user_id = ? AND used_at = 0", userId).Count(&total).Error
+	return total, err
+}
+
+func (r *RecoveryCodeRepository) DeleteByUserId(db *gorm.DB, userId string) error {
+	return db.Where("user_id = ?", userId).Delete(new(entity.RecoveryCode)).Error
+}
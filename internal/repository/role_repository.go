@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"go-clean-template/internal/entity"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type RoleRepository struct {
+	Repository[entity.Role]
+	Log *zap.SugaredLogger
+}
+
+func NewRoleRepository(log *zap.SugaredLogger) *RoleRepository {
+	return &RoleRepository{
+		Log: log,
+	}
+}
+
+func (r *RoleRepository) FindAll(db *gorm.DB, roles *[]entity.Role) error {
+	return db.Preload("Permissions").Order("id").Find(roles).Error
+}
+
+func (r *RoleRepository) FindByIdWithPermissions(db *gorm.DB, role *entity.Role, id string) error {
+	return db.Preload("Permissions").Where("id = ?", id).Take(role).Error
+}
+
+// FindAllByUserId returns the roles granted to a user together with their permissions
+func (r *RoleRepository) FindAllByUserId(db *gorm.DB, roles *[]entity.Role, userId string) error {
+	return db.Preload("Permissions").
+		Joins("JOIN user_roles ON user_roles.role_id = roles.id").
+		Where("user_roles.user_id = ?", userId).
+		Order("roles.id").
+		Find(roles).Error
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"go-clean-template/internal/entity"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SessionRepository struct {
+	Repository[entity.Session]
+	Log *zap.SugaredLogger
+}
+
+func NewSessionRepository(log *zap.SugaredLogger) *SessionRepository {
+	return &SessionRepository{
+		Log: log,
+	}
+}
+
+func (r *SessionRepository) FindByTokenHash(db *gorm.DB, session *entity.Session, tokenHash string) error {
+	return db.Where("token_hash = ?", tokenHash).Take(session).Error
+}
+
+// FindByTokenHashForUpdate locks the row so concurrent refreshes of the same session are serialized
+func (r *SessionRepository) FindByTokenHashForUpdate(db *gorm.DB, session *entity.Session, tokenHash string) error {
+	return db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("token_hash = ?", tokenHash).Take(session).Error
+}
+
+// FindByPreviousTokenHash finds the session whose refresh token was last rotated away from tokenHash
+func (r *SessionRepository) FindByPreviousTokenHash(db *gorm.DB, session *entity.Session, tokenHash string) error {
+	return db.Where("previous_token_hash = ?", tokenHash).Take(session).Error
+}
+
+func (r *SessionRepository) FindByIdAndUserId(db *gorm.DB, session *entity.Session, id string, userId string) error {
+	return db.Where("id = ? AND user_id = ?", id, userId).Take(session).Error
+}
+
+func (r *SessionRepository) FindAllActiveByUserId(db *gorm.DB, userId string, now int64, idleBefore int64) ([]entity.Session, error) {
+	var sessions []entity.Session
+	if err := db.Where("user_id = ? AND revoked_at = 0 AND expires_at > ? AND last_seen_at > ?", userId, now, idleBefore).
+		Order("last_seen_at DESC").Find(&sessions).Error; err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// FindAllByUserId returns every session of the user, ended ones included
+func (r *SessionRepository) FindAllByUserId(db *gorm.DB, userId string) ([]entity.Session, error) {
+	var sessions []entity.Session
+	err := db.Where("user_id = ?", userId).Order("created_at").Find(&sessions).Error
+	return sessions, err
+}
+
+func (r *SessionRepository) UpdateTokenHash(db *gorm.DB, id string, tokenHash string) error {
+	return db.Model(new(entity.Session)).Where("id = ?", id).Update("token_hash", tokenHash).Error
+}
+
+// UpdateLastSeenAt only touches last_seen_at so it never overwrites a concurrent refresh token rotation
+func (r *SessionRepository) UpdateLastSeenAt(db *gorm.DB, id string, lastSeenAt int64) error {
+	return db.Model(new(entity.Session)).Where("id = ?", id).Update("last_seen_at", lastSeenAt).Error
+}
+
+// DeleteInactive removes revoked, expired and idle sessions
+func (r *SessionRepository) DeleteInactive(db *gorm.DB, now int64, idleBefore int64) (int64, error) {
+	result := db.Where("revoked_at <> 0 OR expires_at <= ? OR last_seen_at <= ?", now, idleBefore).Delete(new(entity.Session))
+	return result.RowsAffected, result.Error
+}
+
+func (r *SessionRepository) RevokeById(db *gorm.DB, id string, revokedAt int64) (int64, error) {
+	result := db.Model(new(entity.Session)).
+		Where("id = ? AND revoked_at = 0", id).
+		Update("revoked_at", revokedAt)
+	return result.RowsAffected, result.Error
+}
+
+func (r *SessionRepository) RevokeByIdAndUserId(db *gorm.DB, id string, userId string, revokedAt int64) (int64, error) {
+	result := db.Model(new(entity.Session)).
+		Where("id = ? AND user_id = ? AND revoked_at = 0", id, userId).
+		Update("revoked_at", revokedAt)
+	return result.RowsAffected, result.Error
+}
+
+func (r *SessionRepository) RevokeByUserId(db *gorm.DB, userId string, revokedAt int64) error {
+	return db.Model(new(entity.Session)).
+		Where("user_id = ? AND revoked_at = 0", userId).
+		Update("revoked_at", revokedAt).Error
+}
+
+// RevokeOthersByUserId revokes every session of the user but the one with keepId
+func (r *SessionRepository) RevokeOthersByUserId(db *gorm.DB, userId string, keepId string, revokedAt int64) error {
+	return db.Model(new(entity.Session)).
+		Where("user_id = ? AND id <> ? AND revoked_at = 0", userId, keepId).
+		Update("revoked_at", revokedAt).Error
+}
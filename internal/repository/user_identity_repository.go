@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"go-clean-template/internal/entity"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type UserIdentityRepository struct {
+	Repository[entity.UserIdentity]
+	Log *zap.SugaredLogger
+}
+
+func NewUserIdentityRepository(log *zap.SugaredLogger) *UserIdentityRepository {
+	return &UserIdentityRepository{
+		Log: log,
+	}
+}
+
+func (r *UserIdentityRepository) FindByIssuerAndSubject(db *gorm.DB, identity *entity.UserIdentity, issuer string, subject string) error {
+	return db.Where("issuer = ? AND subject = ?", issuer, subject).Take(identity).Error
+}
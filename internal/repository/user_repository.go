@@ -2,9 +2,11 @@ package repository
 
 import (
 	"go-clean-template/internal/entity"
+	"go-clean-template/internal/model"
 
 	"go.uber.org/zap"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type UserRepository struct {
@@ -18,6 +20,73 @@ func NewUserRepository(log *zap.SugaredLogger) *UserRepository {
 	}
 }
 
-func (r *UserRepository) FindByToken(db *gorm.DB, user *entity.User, token string) error {
-	return db.Where("token = ?", token).First(user).Error
+func (r *UserRepository) FindByEmail(db *gorm.DB, user *entity.User, email string) error {
+	return db.Where("lower(email) = lower(?)", email).Take(user).Error
+}
+
+func (r *UserRepository) CountByEmail(db *gorm.DB, email string) (int64, error) {
+	var total int64
+	err := db.Model(new(entity.User)).Where("lower(email) = lower(?)", email).Count(&total).Error
+	return total, err
+}
+
+// UpdatePassword replaces the password hash alone, leaving the rest of the row to concurrent writers
+func (r *UserRepository) UpdatePassword(db *gorm.DB, id string, password string) error {
+	return db.Model(new(entity.User)).Where("id = ?", id).Update("password", password).Error
+}
+
+// FindIdsDueForDeletion returns users whose deletion grace period is over, oldest first
+func (r *UserRepository) FindIdsDueForDeletion(db *gorm.DB, now int64, limit int) ([]string, error) {
+	var ids []string
+	err := db.Model(new(entity.User)).Where("delete_after <> 0 AND delete_after <= ?", now).
+		Order("delete_after").Limit(limit).Pluck("id", &ids).Error
+	return ids, err
+}
+
+// FindByIdForUpdate locks the row so a cancelled deletion and the purge do not race
+func (r *UserRepository) FindByIdForUpdate(db *gorm.DB, user *entity.User, id string) error {
+	return db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).Take(user).Error
+}
+
+func (r *UserRepository) Search(db *gorm.DB, request *model.SearchUserRequest) ([]entity.User, int64, error) {
+	var users []entity.User
+	if err := db.Scopes(r.FilterUser(request)).Order("id").Offset((request.Page - 1) * request.Size).Limit(request.Size).Find(&users).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var total int64 = 0
+	if err := db.Model(&entity.User{}).Scopes(r.FilterUser(request)).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return users, total, nil
+}
+
+func (r *UserRepository) FilterUser(request *model.SearchUserRequest) func(tx *gorm.DB) *gorm.DB {
+	return func(tx *gorm.DB) *gorm.DB {
+		if id := request.ID; id != "" {
+			id = "%" + id + "%"
+			tx = tx.Where("id ILIKE ?", id)
+		}
+
+		if name := request.Name; name != "" {
+			name = "%" + name + "%"
+			tx = tx.Where("name ILIKE ?", name)
+		}
+
+		if email := request.Email; email != "" {
+			email = "%" + email + "%"
+			tx = tx.Where("email ILIKE ?", email)
+		}
+
+		if disabled := request.Disabled; disabled != nil {
+			if *disabled {
+				tx = tx.Where("disabled_at <> 0")
+			} else {
+				tx = tx.Where("disabled_at = 0")
+			}
+		}
+
+		return tx
+	}
 }
@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"go-clean-template/internal/entity"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type UserRoleRepository struct {
+	Repository[entity.UserRole]
+	Log *zap.SugaredLogger
+}
+
+func NewUserRoleRepository(log *zap.SugaredLogger) *UserRoleRepository {
+	return &UserRoleRepository{
+		Log: log,
+	}
+}
+
+func (r *UserRoleRepository) CountByUserIdAndRoleId(db *gorm.DB, userId string, roleId string) (int64, error) {
+	var total int64
+	err := db.Model(new(entity.UserRole)).Where("user_id = ? AND role_id = ?", userId, roleId).Count(&total).Error
+	return total, err
+}
+
+func (r *UserRoleRepository) DeleteByUserIdAndRoleId(db *gorm.DB, userId string, roleId string) (int64, error) {
+	result := db.Where("user_id = ? AND role_id = ?", userId, roleId).Delete(new(entity.UserRole))
+	return result.RowsAffected, result.Error
+}
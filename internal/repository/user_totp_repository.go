@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"go-clean-template/internal/entity"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type UserTotpRepository struct {
+	Repository[entity.UserTotp]
+	Log *zap.SugaredLogger
+}
+
+func NewUserTotpRepository(log *zap.SugaredLogger) *UserTotpRepository {
+	return &UserTotpRepository{
+		Log: log,
+	}
+}
+
+func (r *UserTotpRepository) FindByUserId(db *gorm.DB, userTotp *entity.UserTotp, userId string) error {
+	return db.Where("user_id = ?", userId).Take(userTotp).Error
+}
+
+// FindByUserIdForUpdate locks the row so the same code can not be accepted twice concurrently
+func (r *UserTotpRepository) FindByUserIdForUpdate(db *gorm.DB, userTotp *entity.UserTotp, userId string) error {
+	return db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("user_id = ?", userId).Take(userTotp).Error
+}
+
+func (r *UserTotpRepository) CountConfirmedByUserId(db *gorm.DB, userId string) (int64, error) {
+	var total int64
+	err := db.Model(new(entity.UserTotp)).Where("user_id = ? AND confirmed_at > 0", userId).Count(&total).Error
+	return total, err
+}
+
+func (r *UserTotpRepository) DeleteByUserId(db *gorm.DB, userId string) error {
+	return db.Where("user_id = ?", userId).Delete(new(entity.UserTotp)).Error
+}
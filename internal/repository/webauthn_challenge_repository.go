@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"go-clean-template/internal/entity"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WebAuthnChallengeRepository struct {
+	Repository[entity.WebAuthnChallenge]
+	Log *zap.SugaredLogger
+}
+
+func NewWebAuthnChallengeRepository(log *zap.SugaredLogger) *WebAuthnChallengeRepository {
+	return &WebAuthnChallengeRepository{
+		Log: log,
+	}
+}
+
+// FindByChallengeHashForUpdate locks the row so a challenge is answered only once
+func (r *WebAuthnChallengeRepository) FindByChallengeHashForUpdate(db *gorm.DB, challenge *entity.WebAuthnChallenge, challengeHash string) error {
+	return db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("challenge_hash = ?", challengeHash).Take(challenge).Error
+}
+
+func (r *WebAuthnChallengeRepository) DeleteExpired(db *gorm.DB, now int64) error {
+	return db.Where("expires_at < ?", now).Delete(new(entity.WebAuthnChallenge)).Error
+}
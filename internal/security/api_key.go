@@ -0,0 +1,25 @@
+package security
+
+import "strings"
+
+// APIKeyPrefix marks API keys so they are told apart from access tokens and can be spotted by secret scanners
+const APIKeyPrefix = "gct_"
+
+// apiKeyDisplayLength is how much of a key is kept in clear to recognise it in listings
+const apiKeyDisplayLength = len(APIKeyPrefix) + 8
+
+// GenerateAPIKey returns a new API key and the part of it that may be shown again later
+func GenerateAPIKey() (string, string, error) {
+	secret, err := GenerateOpaqueToken()
+	if err != nil {
+		return "", "", err
+	}
+
+	key := APIKeyPrefix + secret
+	return key, key[:apiKeyDisplayLength], nil
+}
+
+// IsAPIKey tells API keys apart from access tokens
+func IsAPIKey(token string) bool {
+	return strings.HasPrefix(token, APIKeyPrefix)
+}
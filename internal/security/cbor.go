@@ -0,0 +1,121 @@
+package security
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// cborMaxDepth bounds the nesting of decoded items, authenticator data is never nested deeper than a few levels
+const cborMaxDepth = 16
+
+var errInvalidCBOR = errors.New("invalid cbor")
+
+// decodeCBOR decodes the first CBOR item of data and returns it with the number of bytes it took. It covers
+// the subset WebAuthn authenticators emit: definite length integers, byte and text strings, arrays, maps and
+// simple values. Integers are returned as int64, maps as map[any]any
+func decodeCBOR(data []byte) (any, int, error) {
+	return decodeCBORItem(data, 0)
+}
+
+func decodeCBORItem(data []byte, depth int) (any, int, error) {
+	if depth > cborMaxDepth || len(data) == 0 {
+		return nil, 0, errInvalidCBOR
+	}
+
+	major := data[0] >> 5
+	argument, offset, err := decodeCBORArgument(data)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	switch major {
+	case 0:
+		if argument > 1<<63-1 {
+			return nil, 0, errInvalidCBOR
+		}
+		return int64(argument), offset, nil
+	case 1:
+		if argument > 1<<63-1 {
+			return nil, 0, errInvalidCBOR
+		}
+		return -1 - int64(argument), offset, nil
+	case 2, 3:
+		if argument > uint64(len(data)-offset) {
+			return nil, 0, errInvalidCBOR
+		}
+		end := offset + int(argument)
+		if major == 2 {
+			return append([]byte(nil), data[offset:end]...), end, nil
+		}
+		return string(data[offset:end]), end, nil
+	case 4:
+		if argument > uint64(len(data)-offset) {
+			return nil, 0, errInvalidCBOR
+		}
+		items := make([]any, 0, argument)
+		for i := uint64(0); i < argument; i++ {
+			item, n, err := decodeCBORItem(data[offset:], depth+1)
+			if err != nil {
+				return nil, 0, err
+			}
+			items = append(items, item)
+			offset += n
+		}
+		return items, offset, nil
+	case 5:
+		if argument > uint64(len(data)-offset) {
+			return nil, 0, errInvalidCBOR
+		}
+		items := make(map[any]any, argument)
+		for i := uint64(0); i < argument; i++ {
+			key, n, err := decodeCBORItem(data[offset:], depth+1)
+			if err != nil {
+				return nil, 0, err
+			}
+			offset += n
+			switch key.(type) {
+			case int64, string:
+			default:
+				return nil, 0, errInvalidCBOR
+			}
+
+			value, n, err := decodeCBORItem(data[offset:], depth+1)
+			if err != nil {
+				return nil, 0, err
+			}
+			offset += n
+			items[key] = value
+		}
+		return items, offset, nil
+	case 7:
+		switch data[0] & 0x1f {
+		case 20:
+			return false, offset, nil
+		case 21:
+			return true, offset, nil
+		case 22, 23:
+			return nil, offset, nil
+		}
+	}
+
+	// tags, floats and indefinite lengths are not used by authenticators
+	return nil, 0, errInvalidCBOR
+}
+
+// decodeCBORArgument reads the argument of the item head, returning it and the length of the head
+func decodeCBORArgument(data []byte) (uint64, int, error) {
+	info := data[0] & 0x1f
+	switch {
+	case info < 24:
+		return uint64(info), 1, nil
+	case info == 24 && len(data) >= 2:
+		return uint64(data[1]), 2, nil
+	case info == 25 && len(data) >= 3:
+		return uint64(binary.BigEndian.Uint16(data[1:])), 3, nil
+	case info == 26 && len(data) >= 5:
+		return uint64(binary.BigEndian.Uint32(data[1:])), 5, nil
+	case info == 27 && len(data) >= 9:
+		return binary.BigEndian.Uint64(data[1:]), 9, nil
+	}
+	return 0, 0, errInvalidCBOR
+}
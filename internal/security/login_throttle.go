@@ -0,0 +1,53 @@
+package security
+
+import (
+	"time"
+
+	"go-clean-template/internal/entity"
+)
+
+// LoginThrottle locks a login key out after too many failures, every further failure doubles the lockout
+type LoginThrottle struct {
+	// Threshold is the number of failures that triggers the first lockout
+	Threshold int
+	// Lockout is the length of the first lockout
+	Lockout time.Duration
+	// MaxLockout caps the doubling lockout
+	MaxLockout time.Duration
+	// ResetAfter forgets earlier failures once no failure happened for this long
+	ResetAfter time.Duration
+}
+
+// RetryAfter returns how long the key is still locked out, zero when it is not
+func (t *LoginThrottle) RetryAfter(attempt *entity.LoginAttempt, now time.Time) time.Duration {
+	retryAfter := time.UnixMilli(attempt.LockedUntil).Sub(now)
+	if retryAfter < 0 {
+		return 0
+	}
+	return retryAfter
+}
+
+// Fail counts a failure and reports whether it started a new lockout, a zero threshold never locks
+func (t *LoginThrottle) Fail(attempt *entity.LoginAttempt, now time.Time) bool {
+	if attempt.LockedUntil <= now.UnixMilli() && now.Sub(time.UnixMilli(attempt.LastFailureAt)) > t.ResetAfter {
+		attempt.Failures = 0
+	}
+
+	attempt.Failures++
+	attempt.LastFailureAt = now.UnixMilli()
+
+	if t.Threshold <= 0 || attempt.Failures < t.Threshold {
+		return false
+	}
+
+	lockout := t.Lockout
+	for i := t.Threshold; i < attempt.Failures && lockout < t.MaxLockout; i++ {
+		lockout *= 2
+	}
+	if lockout > t.MaxLockout {
+		lockout = t.MaxLockout
+	}
+
+	attempt.LockedUntil = now.Add(lockout).UnixMilli()
+	return true
+}
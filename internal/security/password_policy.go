@@ -0,0 +1,114 @@
+package security
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// PasswordPolicy decides which new passwords are accepted
+type PasswordPolicy struct {
+	MinLength int
+	// MinClasses is how many of lowercase, uppercase, digits and symbols a password must mix
+	MinClasses int
+	// RejectUserInfo rejects passwords equal to the user id or name
+	RejectUserInfo bool
+	// Breached is nil when no breached password list is configured
+	Breached *BreachedPasswords
+}
+
+// Check returns the reasons the password is rejected, none when it is accepted
+func (p *PasswordPolicy) Check(password string, userInfo ...string) []string {
+	reasons := make([]string, 0)
+
+	if utf8.RuneCountInString(password) < p.MinLength {
+		reasons = append(reasons, fmt.Sprintf("must be at least %d characters long", p.MinLength))
+	}
+
+	if passwordClasses(password) < p.MinClasses {
+		reasons = append(reasons, fmt.Sprintf("must mix at least %d of lowercase letters, uppercase letters, digits and symbols", p.MinClasses))
+	}
+
+	if p.RejectUserInfo {
+		for _, info := range userInfo {
+			if info != "" && strings.EqualFold(password, info) {
+				reasons = append(reasons, "must not be your user id or name")
+				break
+			}
+		}
+	}
+
+	if p.Breached != nil && p.Breached.Contains(password) {
+		reasons = append(reasons, "has appeared in a data breach, choose another one")
+	}
+
+	return reasons
+}
+
+func passwordClasses(password string) int {
+	var lower, upper, digit, symbol int
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = 1
+		case unicode.IsUpper(r):
+			upper = 1
+		case unicode.IsDigit(r):
+			digit = 1
+		default:
+			symbol = 1
+		}
+	}
+	return lower + upper + digit + symbol
+}
+
+// BreachedPasswords is a local list of SHA-1 hashes of breached passwords, bucketed by the first five hex
+// characters of the hash the way k-anonymity range APIs answer, so a lookup only ever matches within one range
+type BreachedPasswords struct {
+	ranges map[string]map[string]struct{}
+}
+
+// LoadBreachedPasswords reads one uppercase or lowercase SHA-1 hex hash per line, optionally followed by
+// ":<count>" as in downloaded range files, blank lines and lines starting with # are skipped
+func LoadBreachedPasswords(reader io.Reader) (*BreachedPasswords, error) {
+	breached := &BreachedPasswords{ranges: map[string]map[string]struct{}{}}
+
+	scanner := bufio.NewScanner(reader)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		hash, _, _ := strings.Cut(text, ":")
+		hash = strings.ToUpper(hash)
+		if _, err := hex.DecodeString(hash); err != nil || len(hash) != sha1.Size*2 {
+			return nil, fmt.Errorf("line %d is not a SHA-1 hash", line)
+		}
+
+		prefix, suffix := hash[:5], hash[5:]
+		if breached.ranges[prefix] == nil {
+			breached.ranges[prefix] = map[string]struct{}{}
+		}
+		breached.ranges[prefix][suffix] = struct{}{}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return breached, nil
+}
+
+func (b *BreachedPasswords) Contains(password string) bool {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	_, found := b.ranges[hash[:5]][hash[5:]]
+	return found
+}
@@ -0,0 +1,17 @@
+package security
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+)
+
+// GeneratePKCE returns a PKCE code verifier and its S256 code challenge
+func GeneratePKCE() (string, string, error) {
+	verifier, err := GenerateOpaqueToken()
+	if err != nil {
+		return "", "", err
+	}
+
+	sum := sha256.Sum256([]byte(verifier))
+	return verifier, base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,30 @@
+package security
+
+import (
+	"time"
+
+	"go-clean-template/internal/entity"
+)
+
+// SessionPolicy bounds how long a session stays usable
+type SessionPolicy struct {
+	// AbsoluteTTL is counted from login and never extended
+	AbsoluteTTL time.Duration
+	// IdleTTL is counted from the last time the session was seen
+	IdleTTL time.Duration
+}
+
+func (p *SessionPolicy) ExpiresAt(now time.Time) int64 {
+	return now.Add(p.AbsoluteTTL).UnixMilli()
+}
+
+// IdleBefore returns the last seen time under which sessions are considered idle
+func (p *SessionPolicy) IdleBefore(now time.Time) int64 {
+	return now.Add(-p.IdleTTL).UnixMilli()
+}
+
+func (p *SessionPolicy) Active(session *entity.Session, now time.Time) bool {
+	return session.RevokedAt == 0 &&
+		session.ExpiresAt > now.UnixMilli() &&
+		session.LastSeenAt > p.IdleBefore(now)
+}
@@ -0,0 +1,173 @@
+package security
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid token")
+	ErrExpiredToken = errors.New("expired token")
+)
+
+// AccessClaims is the payload of a signed access token
+type AccessClaims struct {
+	SessionID   string      `json:"sid,omitempty"`
+	Roles       []string    `json:"roles,omitempty"`
+	Permissions []string    `json:"perms,omitempty"`
+	Actor       *ActorClaim `json:"act,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// ActorClaim names who is acting as the subject of the token, as the act claim of RFC 8693
+type ActorClaim struct {
+	Subject string `json:"sub"`
+}
+
+// TokenProvider signs and verifies access tokens
+type TokenProvider struct {
+	Method      jwt.SigningMethod
+	SignKey     any
+	VerifyKey   any
+	Issuer      string
+	AccessTTL   time.Duration
+	LegacyUntil time.Time
+}
+
+func (p *TokenProvider) GenerateAccessToken(userId string, sessionId string, roles []string, permissions []string) (string, *AccessClaims, error) {
+	claims := &AccessClaims{
+		SessionID:        sessionId,
+		Roles:            roles,
+		Permissions:      permissions,
+		RegisteredClaims: p.registeredClaims(userId, p.AccessTTL),
+	}
+
+	return p.sign(claims)
+}
+
+// GenerateImpersonationToken lets actorId act as userId for ttl, the token belongs to no session and carries
+// no roles, so it is neither renewed nor refreshed and does not reach admin routes
+func (p *TokenProvider) GenerateImpersonationToken(userId string, actorId string, ttl time.Duration) (string, *AccessClaims, error) {
+	claims := &AccessClaims{
+		Actor:            &ActorClaim{Subject: actorId},
+		RegisteredClaims: p.registeredClaims(userId, ttl),
+	}
+
+	return p.sign(claims)
+}
+
+func (p *TokenProvider) registeredClaims(userId string, ttl time.Duration) jwt.RegisteredClaims {
+	now := time.Now()
+	return jwt.RegisteredClaims{
+		ID:        uuid.NewString(),
+		Issuer:    p.Issuer,
+		Subject:   userId,
+		IssuedAt:  jwt.NewNumericDate(now),
+		NotBefore: jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+	}
+}
+
+func (p *TokenProvider) sign(claims *AccessClaims) (string, *AccessClaims, error) {
+	token, err := jwt.NewWithClaims(p.Method, claims).SignedString(p.SignKey)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return token, claims, nil
+}
+
+func (p *TokenProvider) ParseAccessToken(token string) (*AccessClaims, error) {
+	claims := new(AccessClaims)
+	_, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (any, error) {
+		return p.VerifyKey, nil
+	},
+		jwt.WithValidMethods([]string{p.Method.Alg()}),
+		jwt.WithIssuer(p.Issuer),
+		jwt.WithExpirationRequired(),
+	)
+	if errors.Is(err, jwt.ErrTokenExpired) {
+		return nil, ErrExpiredToken
+	}
+	if err != nil {
+		return nil, errors.Join(ErrInvalidToken, err)
+	}
+
+	return claims, nil
+}
+
+// NeedsRenewal reports whether an access token has passed half of its lifetime
+func (p *TokenProvider) NeedsRenewal(expiresAt int64, now time.Time) bool {
+	return time.UnixMilli(expiresAt).Sub(now) < p.AccessTTL/2
+}
+
+// AcceptsLegacyTokens reports whether opaque tokens issued before signed tokens are still honoured
+func (p *TokenProvider) AcceptsLegacyTokens(now time.Time) bool {
+	return now.Before(p.LegacyUntil)
+}
+
+// IsJWT tells signed tokens apart from the legacy opaque UUID tokens
+func IsJWT(token string) bool {
+	return strings.Count(token, ".") == 2
+}
+
+// IsLegacyToken reports whether a token has the shape of the UUID login tokens issued before signed tokens,
+// refresh tokens of either generation never do
+func IsLegacyToken(token string) bool {
+	return len(token) == 36 && uuid.Validate(token) == nil
+}
+
+// GenerateOpaqueToken returns a random URL-safe token
+func GenerateOpaqueToken() (string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(bytes), nil
+}
+
+// GenerateRefreshToken returns a refresh token prefixed with the session it belongs to
+func GenerateRefreshToken(sessionId string) (string, error) {
+	secret, err := GenerateOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+	return sessionId + "." + secret, nil
+}
+
+// HashToken returns the digest stored in the database in place of the raw token
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// TokenHasher digests session tokens before they are stored, keyed with a server-side pepper so a
+// database dump alone is not enough to look sessions up by their tokens
+type TokenHasher struct {
+	Pepper []byte
+}
+
+// Hash returns the HMAC-SHA256 of the token, or its plain SHA-256 when no pepper is configured
+func (h *TokenHasher) Hash(token string) string {
+	if !h.Peppered() {
+		return HashToken(token)
+	}
+
+	mac := hmac.New(sha256.New, h.Pepper)
+	mac.Write([]byte(token))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Peppered reports whether tokens stored with a plain SHA-256 may still need upgrading
+func (h *TokenHasher) Peppered() bool {
+	return len(h.Pepper) > 0
+}
@@ -0,0 +1,273 @@
+package security
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+)
+
+// COSE algorithms accepted for passkeys, in order of preference
+const (
+	COSEAlgES256 = -7
+	COSEAlgEdDSA = -8
+	COSEAlgRS256 = -257
+)
+
+// WebAuthnAlgorithms are offered to authenticators when a passkey is registered
+var WebAuthnAlgorithms = []int64{COSEAlgES256, COSEAlgEdDSA, COSEAlgRS256}
+
+// authenticator data flags, see https://www.w3.org/TR/webauthn-2/#authenticator-data
+const (
+	webAuthnFlagUserPresent  = 0x01
+	webAuthnFlagUserVerified = 0x04
+	webAuthnFlagAttestedData = 0x40
+)
+
+// webAuthnMaxCredentialIdLength is the longest credential id the specification allows
+const webAuthnMaxCredentialIdLength = 1023
+
+var ErrInvalidWebAuthn = errors.New("invalid webauthn response")
+
+// WebAuthn verifies the passkey ceremonies of one relying party. Attestation is not asked for, so a registered
+// passkey is trusted as far as the session that registered it, and user verification is always required
+type WebAuthn struct {
+	RPID    string
+	RPName  string
+	Origins []string
+}
+
+// WebAuthnCredential is a passkey created in a registration ceremony, PublicKey is its COSE_Key
+type WebAuthnCredential struct {
+	ID        []byte
+	PublicKey []byte
+	SignCount uint32
+	AAGUID    []byte
+}
+
+type webAuthnClientData struct {
+	Type        string `json:"type"`
+	Challenge   string `json:"challenge"`
+	Origin      string `json:"origin"`
+	CrossOrigin bool   `json:"crossOrigin"`
+}
+
+// WebAuthnUserHandle returns the user handle passkeys of a user are created with, user ids may be longer than
+// the 64 bytes a handle can hold and are not meant to be stored on authenticators as they are
+func WebAuthnUserHandle(userId string) []byte {
+	sum := sha256.Sum256([]byte(userId))
+	return sum[:]
+}
+
+// WebAuthnChallenge reads the challenge out of clientDataJSON, it tells which ceremony a response belongs to
+func WebAuthnChallenge(clientDataJSON []byte) (string, error) {
+	clientData := new(webAuthnClientData)
+	if err := json.Unmarshal(clientDataJSON, clientData); err != nil {
+		return "", errors.Join(ErrInvalidWebAuthn, err)
+	}
+	if clientData.Challenge == "" {
+		return "", fmt.Errorf("%w: missing challenge", ErrInvalidWebAuthn)
+	}
+	return clientData.Challenge, nil
+}
+
+// VerifyRegistration checks the response of navigator.credentials.create, the attestation statement is
+// not verified as the ceremony asks for none
+func (w *WebAuthn) VerifyRegistration(clientDataJSON []byte, attestationObject []byte, challenge string) (*WebAuthnCredential, error) {
+	if err := w.verifyClientData(clientDataJSON, "webauthn.create", challenge); err != nil {
+		return nil, err
+	}
+
+	decoded, _, err := decodeCBOR(attestationObject)
+	if err != nil {
+		return nil, errors.Join(ErrInvalidWebAuthn, err)
+	}
+	attestation, ok := decoded.(map[any]any)
+	if !ok {
+		return nil, fmt.Errorf("%w: attestation object is not a map", ErrInvalidWebAuthn)
+	}
+	authenticatorData, ok := attestation["authData"].([]byte)
+	if !ok {
+		return nil, fmt.Errorf("%w: missing authenticator data", ErrInvalidWebAuthn)
+	}
+
+	flags, signCount, rest, err := w.parseAuthenticatorData(authenticatorData)
+	if err != nil {
+		return nil, err
+	}
+	if flags&webAuthnFlagAttestedData == 0 || len(rest) < 18 {
+		return nil, fmt.Errorf("%w: missing attested credential data", ErrInvalidWebAuthn)
+	}
+
+	aaguid := rest[:16]
+	idLength := int(binary.BigEndian.Uint16(rest[16:18]))
+	rest = rest[18:]
+	if idLength == 0 || idLength > webAuthnMaxCredentialIdLength || idLength > len(rest) {
+		return nil, fmt.Errorf("%w: invalid credential id", ErrInvalidWebAuthn)
+	}
+	id := rest[:idLength]
+	rest = rest[idLength:]
+
+	_, keyLength, err := decodeCBOR(rest)
+	if err != nil {
+		return nil, errors.Join(ErrInvalidWebAuthn, err)
+	}
+	publicKey := rest[:keyLength]
+	if _, _, err := parseCOSEKey(publicKey); err != nil {
+		return nil, err
+	}
+
+	return &WebAuthnCredential{
+		ID:        bytes.Clone(id),
+		PublicKey: bytes.Clone(publicKey),
+		SignCount: signCount,
+		AAGUID:    bytes.Clone(aaguid),
+	}, nil
+}
+
+// VerifyAssertion checks the response of navigator.credentials.get against a stored passkey and returns
+// its new signature counter
+func (w *WebAuthn) VerifyAssertion(clientDataJSON []byte, authenticatorData []byte, signature []byte, challenge string,
+	publicKey []byte, signCount uint32,
+) (uint32, error) {
+	if err := w.verifyClientData(clientDataJSON, "webauthn.get", challenge); err != nil {
+		return 0, err
+	}
+
+	_, counter, _, err := w.parseAuthenticatorData(authenticatorData)
+	if err != nil {
+		return 0, err
+	}
+
+	key, algorithm, err := parseCOSEKey(publicKey)
+	if err != nil {
+		return 0, err
+	}
+
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	signed := append(bytes.Clone(authenticatorData), clientDataHash[:]...)
+	if err := verifyCOSESignature(key, algorithm, signed, signature); err != nil {
+		return 0, err
+	}
+
+	// a counter that does not move forward hints at a cloned authenticator, authenticators that do not count stay at zero
+	if (counter != 0 || signCount != 0) && counter <= signCount {
+		return 0, fmt.Errorf("%w: signature counter did not increase", ErrInvalidWebAuthn)
+	}
+
+	return counter, nil
+}
+
+func (w *WebAuthn) verifyClientData(clientDataJSON []byte, ceremony string, challenge string) error {
+	clientData := new(webAuthnClientData)
+	if err := json.Unmarshal(clientDataJSON, clientData); err != nil {
+		return errors.Join(ErrInvalidWebAuthn, err)
+	}
+
+	if clientData.Type != ceremony {
+		return fmt.Errorf("%w: unexpected type %q", ErrInvalidWebAuthn, clientData.Type)
+	}
+	if subtle.ConstantTimeCompare([]byte(clientData.Challenge), []byte(challenge)) != 1 {
+		return fmt.Errorf("%w: challenge mismatch", ErrInvalidWebAuthn)
+	}
+	if !slices.Contains(w.Origins, clientData.Origin) || clientData.CrossOrigin {
+		return fmt.Errorf("%w: unexpected origin %q", ErrInvalidWebAuthn, clientData.Origin)
+	}
+
+	return nil
+}
+
+// parseAuthenticatorData checks the relying party and the user flags, and returns the flags, the signature
+// counter and what follows them
+func (w *WebAuthn) parseAuthenticatorData(data []byte) (byte, uint32, []byte, error) {
+	if len(data) < 37 {
+		return 0, 0, nil, fmt.Errorf("%w: authenticator data too short", ErrInvalidWebAuthn)
+	}
+
+	rpIdHash := sha256.Sum256([]byte(w.RPID))
+	if subtle.ConstantTimeCompare(data[:32], rpIdHash[:]) != 1 {
+		return 0, 0, nil, fmt.Errorf("%w: relying party mismatch", ErrInvalidWebAuthn)
+	}
+
+	flags := data[32]
+	if flags&webAuthnFlagUserPresent == 0 || flags&webAuthnFlagUserVerified == 0 {
+		return 0, 0, nil, fmt.Errorf("%w: user not present or not verified", ErrInvalidWebAuthn)
+	}
+
+	return flags, binary.BigEndian.Uint32(data[33:37]), data[37:], nil
+}
+
+// parseCOSEKey returns the public key and algorithm of a COSE_Key (RFC 9053) in one of the WebAuthnAlgorithms
+func parseCOSEKey(data []byte) (crypto.PublicKey, int64, error) {
+	decoded, _, err := decodeCBOR(data)
+	if err != nil {
+		return nil, 0, errors.Join(ErrInvalidWebAuthn, err)
+	}
+	key, ok := decoded.(map[any]any)
+	if !ok {
+		return nil, 0, fmt.Errorf("%w: public key is not a map", ErrInvalidWebAuthn)
+	}
+
+	keyType, _ := key[int64(1)].(int64)
+	algorithm, _ := key[int64(3)].(int64)
+	switch {
+	case keyType == 2 && algorithm == COSEAlgES256:
+		curve, _ := key[int64(-1)].(int64)
+		x, _ := key[int64(-2)].([]byte)
+		y, _ := key[int64(-3)].([]byte)
+		if curve != 1 || len(x) != 32 || len(y) != 32 {
+			break
+		}
+		publicKey, err := ecdsa.ParseUncompressedPublicKey(elliptic.P256(), append(append([]byte{4}, x...), y...))
+		if err != nil {
+			return nil, 0, errors.Join(ErrInvalidWebAuthn, err)
+		}
+		return publicKey, algorithm, nil
+	case keyType == 1 && algorithm == COSEAlgEdDSA:
+		curve, _ := key[int64(-1)].(int64)
+		x, _ := key[int64(-2)].([]byte)
+		if curve != 6 || len(x) != ed25519.PublicKeySize {
+			break
+		}
+		return ed25519.PublicKey(x), algorithm, nil
+	case keyType == 3 && algorithm == COSEAlgRS256:
+		n, _ := key[int64(-1)].([]byte)
+		e, _ := key[int64(-2)].([]byte)
+		if len(n) < 256 || len(e) == 0 || len(e) > 4 {
+			break
+		}
+		exponent := new(big.Int).SetBytes(e)
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}, algorithm, nil
+	}
+
+	return nil, 0, fmt.Errorf("%w: unsupported public key type %d with algorithm %d", ErrInvalidWebAuthn, keyType, algorithm)
+}
+
+func verifyCOSESignature(key crypto.PublicKey, algorithm int64, data []byte, signature []byte) error {
+	digest := sha256.Sum256(data)
+
+	var valid bool
+	switch algorithm {
+	case COSEAlgES256:
+		valid = ecdsa.VerifyASN1(key.(*ecdsa.PublicKey), digest[:], signature)
+	case COSEAlgEdDSA:
+		valid = ed25519.Verify(key.(ed25519.PublicKey), data, signature)
+	case COSEAlgRS256:
+		valid = rsa.VerifyPKCS1v15(key.(*rsa.PublicKey), crypto.SHA256, digest[:], signature) == nil
+	}
+
+	if !valid {
+		return fmt.Errorf("%w: invalid signature", ErrInvalidWebAuthn)
+	}
+	return nil
+}
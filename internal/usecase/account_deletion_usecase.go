@@ -0,0 +1,335 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"go-clean-template/internal/entity"
+	"go-clean-template/internal/gateway/messaging"
+	"go-clean-template/internal/model"
+	"go-clean-template/internal/model/converter"
+	"go-clean-template/internal/repository"
+	"go-clean-template/internal/security"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// accountDeletionBatchSize bounds how many accounts a single purge run deletes
+const accountDeletionBatchSize = 100
+
+type AccountDeletionUseCase struct {
+	DB                 *gorm.DB
+	Log                *zap.SugaredLogger
+	Validate           *validator.Validate
+	UserRepository     *repository.UserRepository
+	SessionRepository  *repository.SessionRepository
+	ContactRepository  *repository.ContactRepository
+	AddressRepository  *repository.AddressRepository
+	PasswordHasher     security.PasswordHasher
+	UserProducer       *messaging.UserProducer
+	AuditLogRepository *repository.AuditLogRepository
+	ApiKeyRepository   *repository.ApiKeyRepository
+	// MfaUseCase confirms the deletion with a two-factor code, it is nil in the worker which only purges
+	MfaUseCase *MfaUseCase
+	// LoginProtectionUseCase counts wrong passwords against the same lockout as failed logins, nil in the worker
+	LoginProtectionUseCase *LoginProtectionUseCase
+	// GracePeriod is how long a scheduled deletion can be cancelled, zero deletes accounts right away
+	GracePeriod time.Duration
+	// ReauthWindow is how recent a login confirms the deletion for users with neither a password nor TOTP
+	ReauthWindow time.Duration
+}
+
+func NewAccountDeletionUseCase(db *gorm.DB, logger *zap.SugaredLogger, validate *validator.Validate,
+	userRepository *repository.UserRepository, sessionRepository *repository.SessionRepository,
+	contactRepository *repository.ContactRepository, addressRepository *repository.AddressRepository,
+	passwordHasher security.PasswordHasher, userProducer *messaging.UserProducer,
+	auditLogRepository *repository.AuditLogRepository, apiKeyRepository *repository.ApiKeyRepository,
+	mfaUseCase *MfaUseCase, loginProtectionUseCase *LoginProtectionUseCase, gracePeriod time.Duration, reauthWindow time.Duration,
+) *AccountDeletionUseCase {
+	return &AccountDeletionUseCase{
+		DB:                     db,
+		Log:                    logger,
+		Validate:               validate,
+		UserRepository:         userRepository,
+		SessionRepository:      sessionRepository,
+		ContactRepository:      contactRepository,
+		AddressRepository:      addressRepository,
+		PasswordHasher:         passwordHasher,
+		UserProducer:           userProducer,
+		AuditLogRepository:     auditLogRepository,
+		ApiKeyRepository:       apiKeyRepository,
+		MfaUseCase:             mfaUseCase,
+		LoginProtectionUseCase: loginProtectionUseCase,
+		GracePeriod:            gracePeriod,
+		ReauthWindow:           reauthWindow,
+	}
+}
+
+// Schedule confirms the deletion and schedules the account for deletion after the grace period,
+// the user is logged out everywhere and its API keys are revoked, but it may log in again to cancel
+func (c *AccountDeletionUseCase) Schedule(ctx context.Context, request *model.DeleteUserRequest) (*model.UserResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request body : %+v", err)
+		return nil, fiber.ErrBadRequest
+	}
+
+	user := new(entity.User)
+	if err := c.UserRepository.FindByIdForUpdate(tx, user, request.ID); err != nil {
+		c.Log.Warnf("Failed find user by id : %+v", err)
+		return nil, fiber.ErrNotFound
+	}
+
+	now := time.Now()
+	if err := c.confirm(tx, user, request, now); err != nil {
+		return nil, err
+	}
+
+	if c.GracePeriod == 0 {
+		if err := c.delete(tx, user); err != nil {
+			return nil, err
+		}
+
+		if err := tx.Commit().Error; err != nil {
+			c.Log.Warnf("Failed commit transaction : %+v", err)
+			return nil, fiber.ErrInternalServerError
+		}
+
+		if err := c.publishDeleted(user, now.UnixMilli()); err != nil {
+			return nil, err
+		}
+
+		user.DeleteAfter = now.UnixMilli()
+		return converter.UserToResponse(user), nil
+	}
+
+	// asking again does not push the deletion further out
+	if user.DeleteAfter == 0 {
+		user.DeleteAfter = now.Add(c.GracePeriod).UnixMilli()
+		if err := c.UserRepository.Update(tx, user); err != nil {
+			c.Log.Warnf("Failed save user : %+v", err)
+			return nil, fiber.ErrInternalServerError
+		}
+	}
+
+	if err := c.SessionRepository.RevokeByUserId(tx, user.ID, now.UnixMilli()); err != nil {
+		c.Log.Warnf("Failed revoke sessions : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := c.ApiKeyRepository.DeleteByUserId(tx, user.ID); err != nil {
+		c.Log.Warnf("Failed delete API keys : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	auditLog := newAuditLog(user.ID, user.ID, model.AuditDeletionScheduled, request.UserAgent, request.IPAddress)
+	if err := c.AuditLogRepository.Create(tx, auditLog); err != nil {
+		c.Log.Warnf("Failed create audit log : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := c.publish(user, "user deletion scheduled"); err != nil {
+		return nil, err
+	}
+
+	return converter.UserToResponse(user), nil
+}
+
+// confirm checks that the owner asks for the deletion: the password when one is given, otherwise a TOTP or
+// recovery code, and for users provisioned without a password who have no TOTP either, a login of the current
+// session within ReauthWindow
+func (c *AccountDeletionUseCase) confirm(tx *gorm.DB, user *entity.User, request *model.DeleteUserRequest, now time.Time) error {
+	if request.Password != "" {
+		return c.LoginProtectionUseCase.ConfirmPassword(tx, c.PasswordHasher, user, request.IPAddress, request.Password)
+	}
+
+	if request.Code != "" {
+		return c.MfaUseCase.verifyCodeThrottled(tx, user.ID, request.IPAddress, request.Code)
+	}
+
+	mfaEnabled, err := c.MfaUseCase.Enabled(tx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	if user.Password != "" || mfaEnabled {
+		c.Log.Warnf("Account deletion without confirmation : %s", user.ID)
+		return ErrPasswordMismatch
+	}
+
+	session := new(entity.Session)
+	if err := c.SessionRepository.FindByIdAndUserId(tx, session, request.SessionId, user.ID); err != nil {
+		c.Log.Warnf("Failed find session by id : %+v", err)
+		return ErrReauthenticationRequired
+	}
+
+	if session.CreatedAt < now.Add(-c.ReauthWindow).UnixMilli() {
+		c.Log.Warnf("Login too old to confirm account deletion : %s", user.ID)
+		return ErrReauthenticationRequired
+	}
+
+	return nil
+}
+
+// Cancel keeps an account that is scheduled for deletion
+func (c *AccountDeletionUseCase) Cancel(ctx context.Context, request *model.CancelUserDeletionRequest) (*model.UserResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request body : %+v", err)
+		return nil, fiber.ErrBadRequest
+	}
+
+	user := new(entity.User)
+	if err := c.UserRepository.FindByIdForUpdate(tx, user, request.ID); err != nil {
+		c.Log.Warnf("Failed find user by id : %+v", err)
+		return nil, fiber.ErrNotFound
+	}
+
+	if user.DeleteAfter == 0 {
+		return converter.UserToResponse(user), nil
+	}
+
+	user.DeleteAfter = 0
+	if err := c.UserRepository.Update(tx, user); err != nil {
+		c.Log.Warnf("Failed save user : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	auditLog := newAuditLog(user.ID, user.ID, model.AuditDeletionCancelled, request.UserAgent, request.IPAddress)
+	if err := c.AuditLogRepository.Create(tx, auditLog); err != nil {
+		c.Log.Warnf("Failed create audit log : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := c.publish(user, "user deletion cancelled"); err != nil {
+		return nil, err
+	}
+
+	return converter.UserToResponse(user), nil
+}
+
+// Purge deletes the accounts whose grace period is over, each in a transaction of its own
+func (c *AccountDeletionUseCase) Purge(ctx context.Context) (int64, error) {
+	now := time.Now().UnixMilli()
+
+	ids, err := c.UserRepository.FindIdsDueForDeletion(c.DB.WithContext(ctx), now, accountDeletionBatchSize)
+	if err != nil {
+		c.Log.Warnf("Failed find users due for deletion : %+v", err)
+		return 0, fiber.ErrInternalServerError
+	}
+
+	var total int64
+	for _, id := range ids {
+		deleted, err := c.purge(ctx, id, now)
+		if err != nil {
+			return total, err
+		}
+		if deleted {
+			total++
+		}
+	}
+
+	return total, nil
+}
+
+func (c *AccountDeletionUseCase) purge(ctx context.Context, id string, now int64) (bool, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	user := new(entity.User)
+	if err := c.UserRepository.FindByIdForUpdate(tx, user, id); err != nil {
+		c.Log.Warnf("Failed find user by id : %+v", err)
+		return false, fiber.ErrInternalServerError
+	}
+
+	// the deletion may have been cancelled since the batch was read
+	if user.DeleteAfter == 0 || user.DeleteAfter > now {
+		return false, nil
+	}
+
+	if err := c.delete(tx, user); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return false, fiber.ErrInternalServerError
+	}
+
+	if err := c.publishDeleted(user, now); err != nil {
+		return true, err
+	}
+
+	return true, nil
+}
+
+// delete removes the user with their contacts and addresses, sessions, credentials and grants
+// are removed by the database along with the user
+func (c *AccountDeletionUseCase) delete(tx *gorm.DB, user *entity.User) error {
+	addresses, err := c.AddressRepository.DeleteAllByUserId(tx, user.ID)
+	if err != nil {
+		c.Log.Warnf("Failed delete addresses : %+v", err)
+		return fiber.ErrInternalServerError
+	}
+
+	contacts, err := c.ContactRepository.DeleteAllByUserId(tx, user.ID)
+	if err != nil {
+		c.Log.Warnf("Failed delete contacts : %+v", err)
+		return fiber.ErrInternalServerError
+	}
+
+	if err := c.UserRepository.Delete(tx, user); err != nil {
+		c.Log.Warnf("Failed delete user : %+v", err)
+		return fiber.ErrInternalServerError
+	}
+
+	c.Log.Infof("User %s deleted with %d contacts and %d addresses", user.ID, contacts, addresses)
+	return nil
+}
+
+func (c *AccountDeletionUseCase) publishDeleted(user *entity.User, deletedAt int64) error {
+	if c.UserProducer != nil {
+		event := &model.UserEvent{ID: user.ID, DeletedAt: deletedAt}
+		c.Log.Info("Publishing user deleted event")
+		if err := c.UserProducer.Send(event); err != nil {
+			c.Log.Warnf("Failed publish user deleted event : %+v", err)
+			return fiber.ErrInternalServerError
+		}
+	} else {
+		c.Log.Info("Kafka producer is disabled, skipping user deleted event")
+	}
+
+	return nil
+}
+
+func (c *AccountDeletionUseCase) publish(user *entity.User, action string) error {
+	if c.UserProducer != nil {
+		event := converter.UserToEvent(user)
+		c.Log.Infof("Publishing %s event", action)
+		if err := c.UserProducer.Send(event); err != nil {
+			c.Log.Warnf("Failed publish %s event : %+v", action, err)
+			return fiber.ErrInternalServerError
+		}
+	} else {
+		c.Log.Infof("Kafka producer is disabled, skipping %s event", action)
+	}
+
+	return nil
+}
@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"time"
 
 	"go-clean-template/internal/entity"
 	"go-clean-template/internal/gateway/messaging"
@@ -179,7 +180,8 @@ func (c *AddressUseCase) Delete(ctx context.Context, request *model.DeleteAddres
 		return fiber.ErrNotFound
 	}
 
-	if err := c.AddressRepository.Delete(tx, address); err != nil {
+	// the address goes to the trash, it is purged once the retention is over
+	if err := c.AddressRepository.SetDeletedAt(tx, address, time.Now().UnixMilli()); err != nil {
 		c.Log.Errorw("failed to delete address", "error", err)
 		return fiber.ErrInternalServerError
 	}
@@ -189,6 +191,17 @@ func (c *AddressUseCase) Delete(ctx context.Context, request *model.DeleteAddres
 		return fiber.ErrInternalServerError
 	}
 
+	if c.AddressProducer != nil {
+		event := converter.AddressToEvent(address)
+		if err := c.AddressProducer.Send(event); err != nil {
+			c.Log.Errorw("failed to publish address deleted event", "error", err)
+			return fiber.ErrInternalServerError
+		}
+		c.Log.Info("Published address deleted event")
+	} else {
+		c.Log.Info("Kafka producer is disabled, skipping address deleted event")
+	}
+
 	return nil
 }
 
@@ -220,3 +233,84 @@ func (c *AddressUseCase) List(ctx context.Context, request *model.ListAddressReq
 
 	return responses, nil
 }
+
+// Trash lists the deleted addresses of the contact that can still be restored, the last deleted first
+func (c *AddressUseCase) Trash(ctx context.Context, request *model.ListTrashedAddressRequest) ([]model.AddressResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Errorw("failed to validate request body", "error", err)
+		return nil, fiber.ErrBadRequest
+	}
+
+	contact := new(entity.Contact)
+	if err := c.ContactRepository.FindByIdAndUserId(tx, contact, request.ContactId, request.UserId); err != nil {
+		c.Log.Errorw("failed to find contact", "error", err)
+		return nil, fiber.ErrNotFound
+	}
+
+	addresses, err := c.AddressRepository.FindAllTrashedByContactId(tx, contact.ID)
+	if err != nil {
+		c.Log.Errorw("failed to find trashed addresses", "error", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Errorw("failed to commit transaction", "error", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	responses := make([]model.AddressResponse, len(addresses))
+	for i, address := range addresses {
+		responses[i] = *converter.AddressToResponse(&address)
+	}
+
+	return responses, nil
+}
+
+// Restore takes an address out of the trash, the contact has to be restored first when it was deleted too
+func (c *AddressUseCase) Restore(ctx context.Context, request *model.RestoreAddressRequest) (*model.AddressResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Errorw("failed to validate request body", "error", err)
+		return nil, fiber.ErrBadRequest
+	}
+
+	contact := new(entity.Contact)
+	if err := c.ContactRepository.FindByIdAndUserId(tx, contact, request.ContactId, request.UserId); err != nil {
+		c.Log.Errorw("failed to find contact", "error", err)
+		return nil, fiber.ErrNotFound
+	}
+
+	address := new(entity.Address)
+	if err := c.AddressRepository.FindTrashedByIdAndContactId(tx, address, request.ID, contact.ID); err != nil {
+		c.Log.Errorw("failed to find trashed address", "error", err)
+		return nil, fiber.ErrNotFound
+	}
+
+	if err := c.AddressRepository.SetDeletedAt(tx, address, 0); err != nil {
+		c.Log.Errorw("failed to restore address", "error", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Errorw("failed to commit transaction", "error", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if c.AddressProducer != nil {
+		event := converter.AddressToEvent(address)
+		if err := c.AddressProducer.Send(event); err != nil {
+			c.Log.Errorw("failed to publish address restored event", "error", err)
+			return nil, fiber.ErrInternalServerError
+		}
+		c.Log.Info("Published address restored event")
+	} else {
+		c.Log.Info("Kafka producer is disabled, skipping address restored event")
+	}
+
+	return converter.AddressToResponse(address), nil
+}
@@ -0,0 +1,344 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"go-clean-template/internal/entity"
+	"go-clean-template/internal/gateway/messaging"
+	"go-clean-template/internal/model"
+	"go-clean-template/internal/model/converter"
+	"go-clean-template/internal/repository"
+	"go-clean-template/internal/security"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type AdminUserUseCase struct {
+	DB                   *gorm.DB
+	Log                  *zap.SugaredLogger
+	Validate             *validator.Validate
+	UserRepository       *repository.UserRepository
+	SessionRepository    *repository.SessionRepository
+	PasswordResetUseCase *PasswordResetUseCase
+	UserProducer         *messaging.UserProducer
+	AuditLogRepository   *repository.AuditLogRepository
+	TokenProvider        *security.TokenProvider
+	// ImpersonationTTL is how long an impersonation token is valid, it is never renewed
+	ImpersonationTTL time.Duration
+}
+
+func NewAdminUserUseCase(db *gorm.DB, logger *zap.SugaredLogger, validate *validator.Validate,
+	userRepository *repository.UserRepository, sessionRepository *repository.SessionRepository,
+	passwordResetUseCase *PasswordResetUseCase, userProducer *messaging.UserProducer,
+	auditLogRepository *repository.AuditLogRepository, tokenProvider *security.TokenProvider, impersonationTTL time.Duration,
+) *AdminUserUseCase {
+	return &AdminUserUseCase{
+		DB:                   db,
+		Log:                  logger,
+		Validate:             validate,
+		UserRepository:       userRepository,
+		SessionRepository:    sessionRepository,
+		PasswordResetUseCase: passwordResetUseCase,
+		UserProducer:         userProducer,
+		AuditLogRepository:   auditLogRepository,
+		TokenProvider:        tokenProvider,
+		ImpersonationTTL:     impersonationTTL,
+	}
+}
+
+func (c *AdminUserUseCase) Search(ctx context.Context, request *model.SearchUserRequest) ([]model.UserResponse, int64, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request body : %+v", err)
+		return nil, 0, fiber.ErrBadRequest
+	}
+
+	users, total, err := c.UserRepository.Search(tx, request)
+	if err != nil {
+		c.Log.Warnf("Failed search users : %+v", err)
+		return nil, 0, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, 0, fiber.ErrInternalServerError
+	}
+
+	responses := make([]model.UserResponse, len(users))
+	for i, user := range users {
+		responses[i] = *converter.UserToResponse(&user)
+	}
+
+	return responses, total, nil
+}
+
+func (c *AdminUserUseCase) Get(ctx context.Context, request *model.GetAdminUserRequest) (*model.UserResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request body : %+v", err)
+		return nil, fiber.ErrBadRequest
+	}
+
+	user := new(entity.User)
+	if err := c.UserRepository.FindById(tx, user, request.ID); err != nil {
+		c.Log.Warnf("Failed find user by id : %+v", err)
+		return nil, fiber.ErrNotFound
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return converter.UserToResponse(user), nil
+}
+
+// Disable blocks the account from logging in and revokes its sessions, access tokens already
+// handed out are refused from their next request on
+func (c *AdminUserUseCase) Disable(ctx context.Context, request *model.DisableUserRequest) (*model.UserResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request body : %+v", err)
+		return nil, fiber.ErrBadRequest
+	}
+
+	if request.ID == request.Actor.ID {
+		c.Log.Warnf("Admin %s tried to disable their own account", request.Actor.ID)
+		return nil, ErrCannotModifySelf
+	}
+
+	user := new(entity.User)
+	if err := c.UserRepository.FindById(tx, user, request.ID); err != nil {
+		c.Log.Warnf("Failed find user by id : %+v", err)
+		return nil, fiber.ErrNotFound
+	}
+
+	now := time.Now().UnixMilli()
+	if user.DisabledAt == 0 {
+		user.DisabledAt = now
+		if err := c.UserRepository.Update(tx, user); err != nil {
+			c.Log.Warnf("Failed save user : %+v", err)
+			return nil, fiber.ErrInternalServerError
+		}
+	}
+
+	if err := c.SessionRepository.RevokeByUserId(tx, user.ID, now); err != nil {
+		c.Log.Warnf("Failed revoke sessions : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := c.AuditLogRepository.Create(tx, newAuditLog(user.ID, request.Actor.ID, model.AuditUserDisabled, "", "")); err != nil {
+		c.Log.Warnf("Failed create audit log : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	c.Log.Infof("User %s disabled by %s", user.ID, request.Actor.ID)
+	if err := c.publish(user, "user disabled"); err != nil {
+		return nil, err
+	}
+
+	return converter.UserToResponse(user), nil
+}
+
+func (c *AdminUserUseCase) Enable(ctx context.Context, request *model.EnableUserRequest) (*model.UserResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request body : %+v", err)
+		return nil, fiber.ErrBadRequest
+	}
+
+	user := new(entity.User)
+	if err := c.UserRepository.FindById(tx, user, request.ID); err != nil {
+		c.Log.Warnf("Failed find user by id : %+v", err)
+		return nil, fiber.ErrNotFound
+	}
+
+	if user.DisabledAt != 0 {
+		user.DisabledAt = 0
+		if err := c.UserRepository.Update(tx, user); err != nil {
+			c.Log.Warnf("Failed save user : %+v", err)
+			return nil, fiber.ErrInternalServerError
+		}
+	}
+
+	if err := c.AuditLogRepository.Create(tx, newAuditLog(user.ID, request.Actor.ID, model.AuditUserEnabled, "", "")); err != nil {
+		c.Log.Warnf("Failed create audit log : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	c.Log.Infof("User %s enabled by %s", user.ID, request.Actor.ID)
+	if err := c.publish(user, "user enabled"); err != nil {
+		return nil, err
+	}
+
+	return converter.UserToResponse(user), nil
+}
+
+// Logout revokes every session of the user
+func (c *AdminUserUseCase) Logout(ctx context.Context, request *model.ForceLogoutUserRequest) (bool, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request body : %+v", err)
+		return false, fiber.ErrBadRequest
+	}
+
+	user := new(entity.User)
+	if err := c.UserRepository.FindById(tx, user, request.ID); err != nil {
+		c.Log.Warnf("Failed find user by id : %+v", err)
+		return false, fiber.ErrNotFound
+	}
+
+	if err := c.SessionRepository.RevokeByUserId(tx, user.ID, time.Now().UnixMilli()); err != nil {
+		c.Log.Warnf("Failed revoke sessions : %+v", err)
+		return false, fiber.ErrInternalServerError
+	}
+
+	if err := c.AuditLogRepository.Create(tx, newAuditLog(user.ID, request.Actor.ID, model.AuditUserLoggedOut, "", "")); err != nil {
+		c.Log.Warnf("Failed create audit log : %+v", err)
+		return false, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return false, fiber.ErrInternalServerError
+	}
+
+	c.Log.Infof("User %s logged out by %s", user.ID, request.Actor.ID)
+	if err := c.publish(user, "user logout"); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// ResetPassword sends the user the same reset link as the forgot-password flow, the admin never sees the token
+func (c *AdminUserUseCase) ResetPassword(ctx context.Context, request *model.AdminResetPasswordRequest) (bool, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request body : %+v", err)
+		return false, fiber.ErrBadRequest
+	}
+
+	user := new(entity.User)
+	if err := c.UserRepository.FindById(tx, user, request.ID); err != nil {
+		c.Log.Warnf("Failed find user by id : %+v", err)
+		return false, fiber.ErrNotFound
+	}
+
+	if user.Email == "" {
+		c.Log.Warnf("User has no email to send the reset link to : %s", user.ID)
+		return false, fiber.ErrUnprocessableEntity
+	}
+
+	if err := c.AuditLogRepository.Create(tx, newAuditLog(user.ID, request.Actor.ID, model.AuditPasswordResetSent, "", "")); err != nil {
+		c.Log.Warnf("Failed create audit log : %+v", err)
+		return false, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return false, fiber.ErrInternalServerError
+	}
+
+	if err := c.PasswordResetUseCase.Send(ctx, user); err != nil {
+		return false, err
+	}
+
+	c.Log.Infof("Password reset for %s requested by %s", user.ID, request.Actor.ID)
+	if err := c.publish(user, "user password reset"); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Impersonate issues a short-lived access token to act as the user, the token names the admin as its actor
+// and every request made with it is recorded in the user's audit log
+func (c *AdminUserUseCase) Impersonate(ctx context.Context, request *model.ImpersonateUserRequest) (*model.UserResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request body : %+v", err)
+		return nil, fiber.ErrBadRequest
+	}
+
+	if request.ID == request.Actor.ID {
+		c.Log.Warnf("Admin %s tried to impersonate their own account", request.Actor.ID)
+		return nil, ErrCannotModifySelf
+	}
+
+	user := new(entity.User)
+	if err := c.UserRepository.FindById(tx, user, request.ID); err != nil {
+		c.Log.Warnf("Failed find user by id : %+v", err)
+		return nil, fiber.ErrNotFound
+	}
+
+	if user.DisabledAt != 0 {
+		c.Log.Warnf("Admin %s tried to impersonate disabled user %s", request.Actor.ID, user.ID)
+		return nil, ErrUserDisabled
+	}
+
+	auditLog := newAuditLog(user.ID, request.Actor.ID, model.AuditImpersonation, request.UserAgent, request.IPAddress)
+	if err := c.AuditLogRepository.Create(tx, auditLog); err != nil {
+		c.Log.Warnf("Failed create audit log : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	token, claims, err := c.TokenProvider.GenerateImpersonationToken(user.ID, request.Actor.ID, c.ImpersonationTTL)
+	if err != nil {
+		c.Log.Warnf("Failed generate impersonation token : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	c.Log.Infof("User %s impersonated by %s", user.ID, request.Actor.ID)
+	response := converter.UserToTokenResponse(token, claims.ExpiresAt.UnixMilli(), "")
+	response.ID = user.ID
+	return response, nil
+}
+
+func (c *AdminUserUseCase) publish(user *entity.User, action string) error {
+	if c.UserProducer != nil {
+		event := converter.UserToEvent(user)
+		c.Log.Infof("Publishing %s event", action)
+		if err := c.UserProducer.Send(event); err != nil {
+			c.Log.Warnf("Failed publish %s event : %+v", action, err)
+			return fiber.ErrInternalServerError
+		}
+	} else {
+		c.Log.Infof("Kafka producer is disabled, skipping %s event", action)
+	}
+
+	return nil
+}
@@ -0,0 +1,221 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"go-clean-template/internal/entity"
+	"go-clean-template/internal/model"
+	"go-clean-template/internal/model/converter"
+	"go-clean-template/internal/repository"
+	"go-clean-template/internal/security"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type ApiKeyUseCase struct {
+	DB               *gorm.DB
+	Log              *zap.SugaredLogger
+	Validate         *validator.Validate
+	UserRepository   *repository.UserRepository
+	ApiKeyRepository *repository.ApiKeyRepository
+	// LastUsedInterval limits how often last_used_at is written for a busy key
+	LastUsedInterval time.Duration
+	// MaxPerUser is how many keys that have not expired a user may hold at once
+	MaxPerUser int
+}
+
+func NewApiKeyUseCase(db *gorm.DB, logger *zap.SugaredLogger, validate *validator.Validate,
+	userRepository *repository.UserRepository, apiKeyRepository *repository.ApiKeyRepository,
+	lastUsedInterval time.Duration, maxPerUser int,
+) *ApiKeyUseCase {
+	return &ApiKeyUseCase{
+		DB:               db,
+		Log:              logger,
+		Validate:         validate,
+		UserRepository:   userRepository,
+		ApiKeyRepository: apiKeyRepository,
+		LastUsedInterval: lastUsedInterval,
+		MaxPerUser:       maxPerUser,
+	}
+}
+
+// Create issues a new API key, the key itself is only part of this response
+func (c *ApiKeyUseCase) Create(ctx context.Context, request *model.CreateApiKeyRequest) (*model.ApiKeyResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request body : %+v", err)
+		return nil, fiber.ErrBadRequest
+	}
+
+	if request.ExpiresAt != 0 && request.ExpiresAt <= time.Now().UnixMilli() {
+		c.Log.Warnf("API key expiry is in the past : %d", request.ExpiresAt)
+		return nil, fiber.ErrBadRequest
+	}
+
+	// the user row is locked so concurrent requests can not both take the last free slot
+	user := new(entity.User)
+	if err := c.UserRepository.FindByIdForUpdate(tx, user, request.UserId); err != nil {
+		c.Log.Warnf("Failed find user by id : %+v", err)
+		return nil, fiber.ErrNotFound
+	}
+
+	total, err := c.ApiKeyRepository.CountActiveByUserId(tx, user.ID, time.Now().UnixMilli())
+	if err != nil {
+		c.Log.Warnf("Failed count API keys : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if total >= int64(c.MaxPerUser) {
+		c.Log.Warnf("User %s already holds %d API keys", user.ID, total)
+		return nil, ErrTooManyApiKeys
+	}
+
+	key, prefix, err := security.GenerateAPIKey()
+	if err != nil {
+		c.Log.Warnf("Failed generate API key : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	apiKey := &entity.ApiKey{
+		ID:        uuid.NewString(),
+		UserId:    request.UserId,
+		Name:      request.Name,
+		Prefix:    prefix,
+		KeyHash:   security.HashToken(key),
+		Scopes:    strings.Join(request.Scopes, ","),
+		ExpiresAt: request.ExpiresAt,
+	}
+
+	if err := c.ApiKeyRepository.Create(tx, apiKey); err != nil {
+		c.Log.Warnf("Failed create API key : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	response := converter.ApiKeyToResponse(apiKey)
+	response.Key = key
+	return response, nil
+}
+
+func (c *ApiKeyUseCase) List(ctx context.Context, request *model.ListApiKeyRequest) ([]model.ApiKeyResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request body : %+v", err)
+		return nil, fiber.ErrBadRequest
+	}
+
+	apiKeys, err := c.ApiKeyRepository.FindAllByUserId(tx, request.UserId)
+	if err != nil {
+		c.Log.Warnf("Failed find API keys : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	responses := make([]model.ApiKeyResponse, len(apiKeys))
+	for i, apiKey := range apiKeys {
+		responses[i] = *converter.ApiKeyToResponse(&apiKey)
+	}
+
+	return responses, nil
+}
+
+func (c *ApiKeyUseCase) Delete(ctx context.Context, request *model.DeleteApiKeyRequest) error {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request body : %+v", err)
+		return fiber.ErrBadRequest
+	}
+
+	deleted, err := c.ApiKeyRepository.DeleteByIdAndUserId(tx, request.ID, request.UserId)
+	if err != nil {
+		c.Log.Warnf("Failed delete API key : %+v", err)
+		return fiber.ErrInternalServerError
+	}
+
+	if deleted == 0 {
+		c.Log.Warnf("API key not found : %s", request.ID)
+		return fiber.ErrNotFound
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return fiber.ErrInternalServerError
+	}
+
+	return nil
+}
+
+// Verify authenticates a request made with an API key, the returned auth carries the key's scopes
+// and no roles, so permission-checked routes stay closed to API keys
+func (c *ApiKeyUseCase) Verify(ctx context.Context, request *model.VerifyUserRequest) (*model.Auth, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request body : %+v", err)
+		return nil, fiber.ErrBadRequest
+	}
+
+	apiKey := new(entity.ApiKey)
+	if err := c.ApiKeyRepository.FindByKeyHash(tx, apiKey, security.HashToken(request.Token)); err != nil {
+		c.Log.Warnf("Failed find API key : %+v", err)
+		return nil, fiber.ErrUnauthorized
+	}
+
+	now := time.Now()
+	if apiKey.ExpiresAt != 0 && apiKey.ExpiresAt <= now.UnixMilli() {
+		c.Log.Warnf("API key expired : %s", apiKey.ID)
+		return nil, ErrApiKeyExpired
+	}
+
+	user := new(entity.User)
+	if err := c.UserRepository.FindById(tx, user, apiKey.UserId); err != nil {
+		c.Log.Warnf("Failed find user by id : %+v", err)
+		return nil, fiber.ErrUnauthorized
+	}
+
+	if user.DisabledAt != 0 {
+		c.Log.Warnf("User is disabled : %s", user.ID)
+		return nil, ErrUserDisabled
+	}
+
+	// an account scheduled for deletion only comes back through a login of its owner
+	if user.DeleteAfter != 0 {
+		c.Log.Warnf("User is scheduled for deletion : %s", user.ID)
+		return nil, fiber.ErrUnauthorized
+	}
+
+	if now.Sub(time.UnixMilli(apiKey.LastUsedAt)) >= c.LastUsedInterval {
+		if err := c.ApiKeyRepository.UpdateLastUsedAt(tx, apiKey.ID, now.UnixMilli()); err != nil {
+			c.Log.Warnf("Failed save API key : %+v", err)
+			return nil, fiber.ErrInternalServerError
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return converter.ApiKeyToAuth(apiKey), nil
+}
@@ -0,0 +1,71 @@
+package usecase
+
+import (
+	"context"
+
+	"go-clean-template/internal/entity"
+	"go-clean-template/internal/model"
+	"go-clean-template/internal/repository"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type AuditLogUseCase struct {
+	DB                 *gorm.DB
+	Log                *zap.SugaredLogger
+	Validate           *validator.Validate
+	AuditLogRepository *repository.AuditLogRepository
+}
+
+func NewAuditLogUseCase(db *gorm.DB, logger *zap.SugaredLogger, validate *validator.Validate,
+	auditLogRepository *repository.AuditLogRepository,
+) *AuditLogUseCase {
+	return &AuditLogUseCase{
+		DB:                 db,
+		Log:                logger,
+		Validate:           validate,
+		AuditLogRepository: auditLogRepository,
+	}
+}
+
+// Record writes an audit entry of its own, use cases that change the account write theirs in their own transaction
+func (c *AuditLogUseCase) Record(ctx context.Context, request *model.RecordAuditLogRequest) error {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request body : %+v", err)
+		return fiber.ErrBadRequest
+	}
+
+	auditLog := newAuditLog(request.UserId, request.ActorId, request.Action, request.UserAgent, request.IPAddress)
+	auditLog.Detail = request.Detail
+	if err := c.AuditLogRepository.Create(tx, auditLog); err != nil {
+		c.Log.Warnf("Failed create audit log : %+v", err)
+		return fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return fiber.ErrInternalServerError
+	}
+
+	return nil
+}
+
+// newAuditLog records an action on the user's account, actorId is the user themselves unless an
+// administrator acted on the account
+func newAuditLog(userId string, actorId string, action string, userAgent string, ipAddress string) *entity.AuditLog {
+	return &entity.AuditLog{
+		ID:        uuid.NewString(),
+		UserId:    userId,
+		ActorId:   actorId,
+		Action:    action,
+		UserAgent: userAgent,
+		IPAddress: ipAddress,
+	}
+}
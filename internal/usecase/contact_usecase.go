@@ -1,7 +1,11 @@
 package usecase
 
 import (
+	"cmp"
 	"context"
+	"errors"
+	"math"
+	"time"
 
 	"go-clean-template/internal/entity"
 	"go-clean-template/internal/gateway/messaging"
@@ -21,18 +25,32 @@ type ContactUseCase struct {
 	Log               *zap.SugaredLogger
 	Validate          *validator.Validate
 	ContactRepository *repository.ContactRepository
+	AddressRepository *repository.AddressRepository
+	UserRepository    *repository.UserRepository
 	ContactProducer   *messaging.ContactProducer
+	AddressProducer   *messaging.AddressProducer
+	// RequireVerifiedEmail blocks contact creation until the user has verified their email
+	RequireVerifiedEmail bool
+	// TrashRetention is how long deleted contacts and addresses can be restored before they are purged
+	TrashRetention time.Duration
 }
 
 func NewContactUseCase(db *gorm.DB, logger *zap.SugaredLogger, validate *validator.Validate,
-	contactRepository *repository.ContactRepository, contactProducer *messaging.ContactProducer,
+	contactRepository *repository.ContactRepository, addressRepository *repository.AddressRepository,
+	userRepository *repository.UserRepository, contactProducer *messaging.ContactProducer,
+	addressProducer *messaging.AddressProducer, requireVerifiedEmail bool, trashRetention time.Duration,
 ) *ContactUseCase {
 	return &ContactUseCase{
-		DB:                db,
-		Log:               logger,
-		Validate:          validate,
-		ContactRepository: contactRepository,
-		ContactProducer:   contactProducer,
+		DB:                   db,
+		Log:                  logger,
+		Validate:             validate,
+		ContactRepository:    contactRepository,
+		AddressRepository:    addressRepository,
+		UserRepository:       userRepository,
+		ContactProducer:      contactProducer,
+		AddressProducer:      addressProducer,
+		RequireVerifiedEmail: requireVerifiedEmail,
+		TrashRetention:       trashRetention,
 	}
 }
 
@@ -45,6 +63,18 @@ func (c *ContactUseCase) Create(ctx context.Context, request *model.CreateContac
 		return nil, fiber.ErrBadRequest
 	}
 
+	if c.RequireVerifiedEmail {
+		user := new(entity.User)
+		if err := c.UserRepository.FindById(tx, user, request.UserId); err != nil {
+			c.Log.Errorw("error getting user", "error", err)
+			return nil, fiber.ErrInternalServerError
+		}
+		if user.EmailVerifiedAt == 0 {
+			c.Log.Errorw("error creating contact, email is not verified", "user_id", user.ID)
+			return nil, ErrEmailNotVerified
+		}
+	}
+
 	contact := &entity.Contact{
 		ID:        uuid.New().String(),
 		FirstName: request.FirstName,
@@ -160,37 +190,255 @@ func (c *ContactUseCase) Delete(ctx context.Context, request *model.DeleteContac
 		return fiber.ErrNotFound
 	}
 
-	if err := c.ContactRepository.Delete(tx, contact); err != nil {
+	// the contact goes to the trash with its addresses, they are purged once the retention is over
+	deletedAt := time.Now().UnixMilli()
+	if err := c.ContactRepository.SetDeletedAt(tx, contact, deletedAt); err != nil {
 		c.Log.Errorw("error deleting contact", "error", err)
 		return fiber.ErrInternalServerError
 	}
 
+	addresses, err := c.AddressRepository.UpdateDeletedAtByContactId(tx, contact.ID, 0, deletedAt)
+	if err != nil {
+		c.Log.Errorw("error deleting addresses of contact", "error", err)
+		return fiber.ErrInternalServerError
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		c.Log.Errorw("error deleting contact", "error", err)
 		return fiber.ErrInternalServerError
 	}
 
-	return nil
+	if c.ContactProducer != nil {
+		event := converter.ContactToEvent(contact)
+		for _, address := range addresses {
+			event.AddressIds = append(event.AddressIds, address.ID)
+		}
+		if err := c.ContactProducer.Send(event); err != nil {
+			c.Log.Errorw("error publishing contact deleted event", "error", err)
+			return fiber.ErrInternalServerError
+		}
+		c.Log.Info("Published contact deleted event")
+	} else {
+		c.Log.Info("Kafka producer is disabled, skipping contact deleted event")
+	}
+
+	return c.publishAddresses(addresses, "deleted")
 }
 
-func (c *ContactUseCase) Search(ctx context.Context, request *model.SearchContactRequest) ([]model.ContactResponse, int64, error) {
+// Trash lists the deleted contacts of the user that can still be restored, the last deleted first
+func (c *ContactUseCase) Trash(ctx context.Context, request *model.SearchTrashedContactRequest) ([]model.ContactResponse, *model.PageMetadata, error) {
 	tx := c.DB.WithContext(ctx).Begin()
 	defer tx.Rollback()
 
 	if err := c.Validate.Struct(request); err != nil {
 		c.Log.Errorw("error validating request body", "error", err)
-		return nil, 0, fiber.ErrBadRequest
+		return nil, nil, fiber.ErrBadRequest
 	}
 
-	contacts, total, err := c.ContactRepository.Search(tx, request)
+	contacts, total, err := c.ContactRepository.SearchTrash(tx, request)
 	if err != nil {
-		c.Log.Errorw("error getting contacts", "error", err)
-		return nil, 0, fiber.ErrInternalServerError
+		c.Log.Errorw("error getting trashed contacts", "error", err)
+		return nil, nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Errorw("error getting trashed contacts", "error", err)
+		return nil, nil, fiber.ErrInternalServerError
+	}
+
+	responses := make([]model.ContactResponse, len(contacts))
+	for i, contact := range contacts {
+		responses[i] = *converter.ContactToResponse(&contact)
+	}
+
+	paging := &model.PageMetadata{
+		Page:      request.Page,
+		Size:      request.Size,
+		TotalItem: total,
+		TotalPage: int64(math.Ceil(float64(total) / float64(request.Size))),
+	}
+
+	return responses, paging, nil
+}
+
+// Restore takes a contact out of the trash along with the addresses deleted with it, addresses deleted
+// on their own before stay in the trash
+func (c *ContactUseCase) Restore(ctx context.Context, request *model.RestoreContactRequest) (*model.ContactResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Errorw("error validating request body", "error", err)
+		return nil, fiber.ErrBadRequest
+	}
+
+	contact := new(entity.Contact)
+	if err := c.ContactRepository.FindTrashedByIdAndUserId(tx, contact, request.ID, request.UserId); err != nil {
+		c.Log.Errorw("error getting trashed contact", "error", err)
+		return nil, fiber.ErrNotFound
+	}
+
+	// the addresses deleted with the contact were deleted at the same time
+	addresses, err := c.AddressRepository.UpdateDeletedAtByContactId(tx, contact.ID, contact.DeletedAt, 0)
+	if err != nil {
+		c.Log.Errorw("error restoring addresses of contact", "error", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := c.ContactRepository.SetDeletedAt(tx, contact, 0); err != nil {
+		c.Log.Errorw("error restoring contact", "error", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Errorw("error restoring contact", "error", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if c.ContactProducer != nil {
+		event := converter.ContactToEvent(contact)
+		if err := c.ContactProducer.Send(event); err != nil {
+			c.Log.Errorw("error publishing contact restored event", "error", err)
+			return nil, fiber.ErrInternalServerError
+		}
+		c.Log.Info("Published contact restored event")
+	} else {
+		c.Log.Info("Kafka producer is disabled, skipping contact restored event")
+	}
+
+	if err := c.publishAddresses(addresses, "restored"); err != nil {
+		return nil, err
+	}
+
+	return converter.ContactToResponse(contact), nil
+}
+
+// publishAddresses reports the addresses that went to the trash or came out of it along with their contact
+func (c *ContactUseCase) publishAddresses(addresses []entity.Address, action string) error {
+	if len(addresses) == 0 {
+		return nil
+	}
+
+	if c.AddressProducer == nil {
+		c.Log.Infof("Kafka producer is disabled, skipping address %s events", action)
+		return nil
+	}
+
+	for _, address := range addresses {
+		event := converter.AddressToEvent(&address)
+		if err := c.AddressProducer.Send(event); err != nil {
+			c.Log.Errorw("error publishing address "+action+" event", "error", err)
+			return fiber.ErrInternalServerError
+		}
+	}
+	c.Log.Infof("Published %d address %s events", len(addresses), action)
+
+	return nil
+}
+
+// Purge permanently deletes the contacts and addresses that have been in the trash longer than the retention,
+// and returns how many of each
+func (c *ContactUseCase) Purge(ctx context.Context) (int64, int64, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	before := time.Now().Add(-c.TrashRetention).UnixMilli()
+
+	// addresses go first, a trashed contact may still have addresses that were never trashed themselves
+	addresses, err := c.AddressRepository.DeleteTrashedBefore(tx, before)
+	if err != nil {
+		c.Log.Errorw("error purging addresses", "error", err)
+		return 0, 0, fiber.ErrInternalServerError
+	}
+
+	contacts, err := c.ContactRepository.DeleteTrashedBefore(tx, before)
+	if err != nil {
+		c.Log.Errorw("error purging contacts", "error", err)
+		return 0, 0, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Errorw("error purging trash", "error", err)
+		return 0, 0, fiber.ErrInternalServerError
+	}
+
+	return contacts, addresses, nil
+}
+
+// Search lists the contacts of the user, those matching q best come first. A page is picked either by
+// number or by a cursor from a previous page, which is cheaper on long lists and does not skip or repeat
+// contacts when the list changes in between
+func (c *ContactUseCase) Search(ctx context.Context, request *model.SearchContactRequest) ([]model.ContactResponse, *model.PageMetadata, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Errorw("error validating request body", "error", err)
+		return nil, nil, fiber.ErrBadRequest
+	}
+
+	// relevance has no column to seek on
+	byRelevance := request.Query != "" && len(request.Sort) == 0
+	paging := &model.PageMetadata{Size: request.Size}
+
+	var contacts []entity.Contact
+	var more bool
+	var err error
+	if cursor := cmp.Or(request.After, request.Before); cursor != "" {
+		if byRelevance {
+			c.Log.Warnf("Cursor used with a search by relevance")
+			return nil, nil, ErrCursorNeedsSort
+		}
+
+		decoded, err := model.DecodeContactCursor(cursor)
+		if err != nil {
+			c.Log.Warnf("Failed decode cursor : %+v", err)
+			return nil, nil, ErrInvalidCursor
+		}
+
+		before := request.Before != ""
+		contacts, more, err = c.ContactRepository.SearchByCursor(tx, request, decoded, before)
+		if errors.Is(err, repository.ErrInvalidCursor) {
+			c.Log.Warnf("Cursor does not match the sort : %+v", err)
+			return nil, nil, ErrInvalidCursor
+		}
+		if err != nil {
+			c.Log.Errorw("error getting contacts", "error", err)
+			return nil, nil, fiber.ErrInternalServerError
+		}
+
+		// the page the cursor came from lies the other way
+		if err := c.pageCursors(request, paging, contacts, more || before, more || !before); err != nil {
+			return nil, nil, err
+		}
+	} else {
+		paging.Page = request.Page
+		contacts, more, err = c.ContactRepository.Search(tx, request)
+		if err != nil {
+			c.Log.Errorw("error getting contacts", "error", err)
+			return nil, nil, fiber.ErrInternalServerError
+		}
+
+		if !byRelevance {
+			if err := c.pageCursors(request, paging, contacts, more, request.Page > 1); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	if request.CountTotal {
+		total, err := c.ContactRepository.Count(tx, request)
+		if err != nil {
+			c.Log.Errorw("error counting contacts", "error", err)
+			return nil, nil, fiber.ErrInternalServerError
+		}
+		paging.TotalItem = total
+		paging.TotalPage = int64(math.Ceil(float64(total) / float64(request.Size)))
 	}
 
 	if err := tx.Commit().Error; err != nil {
 		c.Log.Errorw("error getting contacts", "error", err)
-		return nil, 0, fiber.ErrInternalServerError
+		return nil, nil, fiber.ErrInternalServerError
 	}
 
 	responses := make([]model.ContactResponse, len(contacts))
@@ -198,5 +446,46 @@ func (c *ContactUseCase) Search(ctx context.Context, request *model.SearchContac
 		responses[i] = *converter.ContactToResponse(&contact)
 	}
 
-	return responses, total, nil
+	return responses, paging, nil
+}
+
+// pageCursors sets the cursors to the pages around the contacts, the next page starts after the last
+// contact and the previous one ends before the first
+func (c *ContactUseCase) pageCursors(request *model.SearchContactRequest, paging *model.PageMetadata, contacts []entity.Contact, hasNext bool, hasPrevious bool) error {
+	if len(contacts) == 0 {
+		return nil
+	}
+
+	if hasNext {
+		next, err := c.encodeCursor(request, &contacts[len(contacts)-1])
+		if err != nil {
+			return err
+		}
+		paging.Next = next
+	}
+
+	if hasPrevious {
+		previous, err := c.encodeCursor(request, &contacts[0])
+		if err != nil {
+			return err
+		}
+		paging.Previous = previous
+	}
+
+	return nil
+}
+
+func (c *ContactUseCase) encodeCursor(request *model.SearchContactRequest, contact *entity.Contact) (string, error) {
+	cursor, err := c.ContactRepository.Cursor(contact, request)
+	if err != nil {
+		c.Log.Errorw("error creating cursor", "error", err)
+		return "", fiber.ErrInternalServerError
+	}
+
+	encoded, err := cursor.Encode()
+	if err != nil {
+		c.Log.Errorw("error encoding cursor", "error", err)
+		return "", fiber.ErrInternalServerError
+	}
+	return encoded, nil
 }
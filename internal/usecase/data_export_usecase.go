@@ -0,0 +1,338 @@
+package usecase
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"go-clean-template/internal/entity"
+	"go-clean-template/internal/gateway/notifier"
+	"go-clean-template/internal/model"
+	"go-clean-template/internal/model/converter"
+	"go-clean-template/internal/repository"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// dataExportBatchSize bounds how many exports a single worker run builds
+const dataExportBatchSize = 10
+
+type DataExportUseCase struct {
+	DB                   *gorm.DB
+	Log                  *zap.SugaredLogger
+	Validate             *validator.Validate
+	UserRepository       *repository.UserRepository
+	SessionRepository    *repository.SessionRepository
+	ContactRepository    *repository.ContactRepository
+	AuditLogRepository   *repository.AuditLogRepository
+	DataExportRepository *repository.DataExportRepository
+	Notifier             notifier.Notifier
+	// TTL is how long a built archive can be downloaded
+	TTL time.Duration
+	// URL is where exports are downloaded from, the export id is appended to it
+	URL string
+}
+
+func NewDataExportUseCase(db *gorm.DB, logger *zap.SugaredLogger, validate *validator.Validate,
+	userRepository *repository.UserRepository, sessionRepository *repository.SessionRepository,
+	contactRepository *repository.ContactRepository, auditLogRepository *repository.AuditLogRepository,
+	dataExportRepository *repository.DataExportRepository, notifier notifier.Notifier, ttl time.Duration, url string,
+) *DataExportUseCase {
+	return &DataExportUseCase{
+		DB:                   db,
+		Log:                  logger,
+		Validate:             validate,
+		UserRepository:       userRepository,
+		SessionRepository:    sessionRepository,
+		ContactRepository:    contactRepository,
+		AuditLogRepository:   auditLogRepository,
+		DataExportRepository: dataExportRepository,
+		Notifier:             notifier,
+		TTL:                  ttl,
+		URL:                  url,
+	}
+}
+
+// Create queues an export for the worker, asking again while one is queued returns the queued one
+func (c *DataExportUseCase) Create(ctx context.Context, request *model.CreateDataExportRequest) (*model.DataExportResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request body : %+v", err)
+		return nil, fiber.ErrBadRequest
+	}
+
+	dataExport := new(entity.DataExport)
+	err := c.DataExportRepository.FindPendingByUserId(tx, dataExport, request.UserId)
+	if err == nil {
+		return converter.DataExportToResponse(dataExport), nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		c.Log.Warnf("Failed find pending data export : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	dataExport = &entity.DataExport{
+		ID:     uuid.NewString(),
+		UserId: request.UserId,
+		Status: model.DataExportPending,
+	}
+	if err := c.DataExportRepository.Create(tx, dataExport); err != nil {
+		c.Log.Warnf("Failed create data export : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	auditLog := newAuditLog(request.UserId, request.UserId, model.AuditDataExport, request.UserAgent, request.IPAddress)
+	if err := c.AuditLogRepository.Create(tx, auditLog); err != nil {
+		c.Log.Warnf("Failed create audit log : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return converter.DataExportToResponse(dataExport), nil
+}
+
+func (c *DataExportUseCase) Get(ctx context.Context, request *model.GetDataExportRequest) (*model.DataExportResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request body : %+v", err)
+		return nil, fiber.ErrBadRequest
+	}
+
+	dataExport := new(entity.DataExport)
+	if err := c.DataExportRepository.FindByIdAndUserId(tx, dataExport, request.ID, request.UserId); err != nil {
+		c.Log.Warnf("Failed find data export : %+v", err)
+		return nil, fiber.ErrNotFound
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return converter.DataExportToResponse(dataExport), nil
+}
+
+// Download returns the zip archive of a built export
+func (c *DataExportUseCase) Download(ctx context.Context, request *model.GetDataExportRequest) ([]byte, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request body : %+v", err)
+		return nil, fiber.ErrBadRequest
+	}
+
+	dataExport := new(entity.DataExport)
+	if err := c.DataExportRepository.FindArchiveByIdAndUserId(tx, dataExport, request.ID, request.UserId); err != nil {
+		c.Log.Warnf("Failed find data export : %+v", err)
+		return nil, fiber.ErrNotFound
+	}
+
+	if dataExport.Status != model.DataExportReady {
+		c.Log.Warnf("Data export is not built yet : %s", dataExport.ID)
+		return nil, ErrDataExportNotReady
+	}
+
+	if dataExport.ExpiresAt <= time.Now().UnixMilli() {
+		c.Log.Warnf("Data export expired : %s", dataExport.ID)
+		return nil, fiber.ErrGone
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return dataExport.Archive, nil
+}
+
+// Build builds queued exports and tells their users they are ready
+func (c *DataExportUseCase) Build(ctx context.Context) (int64, error) {
+	var total int64
+	for total < dataExportBatchSize {
+		built, err := c.buildNext(ctx)
+		if err != nil {
+			return total, err
+		}
+		if !built {
+			break
+		}
+		total++
+	}
+
+	return total, nil
+}
+
+func (c *DataExportUseCase) buildNext(ctx context.Context) (bool, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	dataExport := new(entity.DataExport)
+	if err := c.DataExportRepository.FindPendingForUpdate(tx, dataExport); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		c.Log.Warnf("Failed find pending data export : %+v", err)
+		return false, fiber.ErrInternalServerError
+	}
+
+	user := new(entity.User)
+	if err := c.UserRepository.FindById(tx, user, dataExport.UserId); err != nil {
+		c.Log.Warnf("Failed find user by id : %+v", err)
+		return false, fiber.ErrInternalServerError
+	}
+
+	archive, err := c.archive(tx, user)
+	if err != nil {
+		return false, err
+	}
+
+	now := time.Now()
+	dataExport.ExpiresAt = now.Add(c.TTL).UnixMilli()
+	if err := c.DataExportRepository.Complete(tx, dataExport.ID, archive, now.UnixMilli(), dataExport.ExpiresAt); err != nil {
+		c.Log.Warnf("Failed save data export : %+v", err)
+		return false, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return false, fiber.ErrInternalServerError
+	}
+
+	// the archive is ready either way, the user can still find it by polling the export
+	if user.Email == "" {
+		c.Log.Warnf("User has no email to send the data export notification to : %s", user.ID)
+		return true, nil
+	}
+
+	notification := &model.Notification{
+		To:      user.Email,
+		Subject: "Your data export is ready",
+		Body: fmt.Sprintf("Download your data export until %s: %s/%s/download",
+			time.UnixMilli(dataExport.ExpiresAt).UTC().Format(time.RFC1123), c.URL, dataExport.ID),
+	}
+	if err := c.Notifier.Send(ctx, notification); err != nil {
+		c.Log.Warnf("Failed send data export notification : %+v", err)
+	}
+
+	return true, nil
+}
+
+// archive collects the profile, contacts with their addresses, sessions and audit log of the user into a zip
+func (c *DataExportUseCase) archive(tx *gorm.DB, user *entity.User) ([]byte, error) {
+	contacts, err := c.ContactRepository.FindAllByUserIdWithAddresses(tx, user.ID)
+	if err != nil {
+		c.Log.Warnf("Failed find contacts by user id : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	sessions, err := c.SessionRepository.FindAllByUserId(tx, user.ID)
+	if err != nil {
+		c.Log.Warnf("Failed find sessions by user id : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	auditLogs, err := c.AuditLogRepository.FindAllByUserId(tx, user.ID)
+	if err != nil {
+		c.Log.Warnf("Failed find audit logs by user id : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	contactResponses := make([]model.ContactResponse, len(contacts))
+	for i, contact := range contacts {
+		contactResponses[i] = *converter.ContactToResponse(&contact)
+		contactResponses[i].Addresses = make([]model.AddressResponse, len(contact.Addresses))
+		for j, address := range contact.Addresses {
+			contactResponses[i].Addresses[j] = *converter.AddressToResponse(&address)
+		}
+	}
+
+	sessionResponses := make([]model.SessionResponse, len(sessions))
+	for i, session := range sessions {
+		sessionResponses[i] = *converter.SessionToResponse(&session, "")
+	}
+
+	auditLogResponses := make([]model.AuditLogResponse, len(auditLogs))
+	for i, auditLog := range auditLogs {
+		auditLogResponses[i] = *converter.AuditLogToResponse(&auditLog)
+	}
+
+	buffer := new(bytes.Buffer)
+	writer := zip.NewWriter(buffer)
+	files := []struct {
+		name    string
+		content any
+	}{
+		{"profile.json", converter.UserToResponse(user)},
+		{"contacts.json", contactResponses},
+		{"sessions.json", sessionResponses},
+		{"audit_log.json", auditLogResponses},
+	}
+	for _, file := range files {
+		content, err := json.MarshalIndent(file.content, "", "  ")
+		if err != nil {
+			c.Log.Warnf("Failed marshal %s : %+v", file.name, err)
+			return nil, fiber.ErrInternalServerError
+		}
+		if err := writeArchiveFile(writer, file.name, content); err != nil {
+			c.Log.Warnf("Failed write %s : %+v", file.name, err)
+			return nil, fiber.ErrInternalServerError
+		}
+	}
+
+	if err := writeArchiveFile(writer, "contacts.vcf", converter.ContactsToVCard(contacts)); err != nil {
+		c.Log.Warnf("Failed write contacts.vcf : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := writer.Close(); err != nil {
+		c.Log.Warnf("Failed close data export archive : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// DeleteExpired purges archives past their download window
+func (c *DataExportUseCase) DeleteExpired(ctx context.Context) (int64, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	total, err := c.DataExportRepository.DeleteExpired(tx, time.Now().UnixMilli())
+	if err != nil {
+		c.Log.Warnf("Failed delete expired data exports : %+v", err)
+		return 0, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return 0, fiber.ErrInternalServerError
+	}
+
+	return total, nil
+}
+
+func writeArchiveFile(writer *zip.Writer, name string, content []byte) error {
+	file, err := writer.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(content)
+	return err
+}
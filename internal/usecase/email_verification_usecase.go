@@ -0,0 +1,195 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"go-clean-template/internal/entity"
+	"go-clean-template/internal/gateway/notifier"
+	"go-clean-template/internal/model"
+	"go-clean-template/internal/repository"
+	"go-clean-template/internal/security"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type EmailVerificationUseCase struct {
+	DB                          *gorm.DB
+	Log                         *zap.SugaredLogger
+	Validate                    *validator.Validate
+	UserRepository              *repository.UserRepository
+	EmailVerificationRepository *repository.EmailVerificationRepository
+	Notifier                    notifier.Notifier
+	TokenTTL                    time.Duration
+	ResendInterval              time.Duration
+	VerifyURL                   string
+}
+
+func NewEmailVerificationUseCase(db *gorm.DB, logger *zap.SugaredLogger, validate *validator.Validate,
+	userRepository *repository.UserRepository, emailVerificationRepository *repository.EmailVerificationRepository,
+	notifier notifier.Notifier, tokenTTL time.Duration, resendInterval time.Duration, verifyURL string,
+) *EmailVerificationUseCase {
+	return &EmailVerificationUseCase{
+		DB:                          db,
+		Log:                         logger,
+		Validate:                    validate,
+		UserRepository:              userRepository,
+		EmailVerificationRepository: emailVerificationRepository,
+		Notifier:                    notifier,
+		TokenTTL:                    tokenTTL,
+		ResendInterval:              resendInterval,
+		VerifyURL:                   verifyURL,
+	}
+}
+
+// Send issues a new verification token for the user's email and delivers it, earlier tokens stop working
+func (c *EmailVerificationUseCase) Send(ctx context.Context, user *entity.User) error {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	now := time.Now()
+	if err := c.EmailVerificationRepository.MarkUsedByUserId(tx, user.ID, now.UnixMilli()); err != nil {
+		c.Log.Warnf("Failed invalidate previous email verifications : %+v", err)
+		return fiber.ErrInternalServerError
+	}
+
+	token, err := security.GenerateOpaqueToken()
+	if err != nil {
+		c.Log.Warnf("Failed generate email verification token : %+v", err)
+		return fiber.ErrInternalServerError
+	}
+
+	emailVerification := &entity.EmailVerification{
+		ID:        uuid.NewString(),
+		UserId:    user.ID,
+		Email:     user.Email,
+		TokenHash: security.HashToken(token),
+		ExpiresAt: now.Add(c.TokenTTL).UnixMilli(),
+	}
+
+	if err := c.EmailVerificationRepository.Create(tx, emailVerification); err != nil {
+		c.Log.Warnf("Failed create email verification : %+v", err)
+		return fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return fiber.ErrInternalServerError
+	}
+
+	notification := &model.Notification{
+		To:      user.Email,
+		Subject: "Verify your email",
+		Body: fmt.Sprintf("Use this link to verify your email, it expires in %s: %s?token=%s",
+			c.TokenTTL, c.VerifyURL, url.QueryEscape(token)),
+	}
+	if err := c.Notifier.Send(ctx, notification); err != nil {
+		c.Log.Warnf("Failed send email verification notification : %+v", err)
+		return fiber.ErrInternalServerError
+	}
+
+	return nil
+}
+
+// Resend sends a new verification link, it succeeds for unknown emails too so accounts can not be enumerated
+func (c *EmailVerificationUseCase) Resend(ctx context.Context, request *model.ResendVerificationRequest) (bool, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request body : %+v", err)
+		return false, fiber.ErrBadRequest
+	}
+
+	user := new(entity.User)
+	if err := c.UserRepository.FindByEmail(tx, user, request.Email); err != nil {
+		c.Log.Warnf("Failed find user by email : %+v", err)
+		return true, nil
+	}
+
+	if user.EmailVerifiedAt != 0 {
+		c.Log.Infof("Email is already verified : %s", user.ID)
+		return true, nil
+	}
+
+	latest := new(entity.EmailVerification)
+	if err := c.EmailVerificationRepository.FindLatestByUserId(tx, latest, user.ID); err == nil {
+		retryAfter := time.UnixMilli(latest.CreatedAt).Add(c.ResendInterval).Sub(time.Now())
+		if retryAfter > 0 {
+			c.Log.Warnf("Email verification resent too often : %s", user.ID)
+			return false, &RetryAfterError{
+				Err:        fiber.ErrTooManyRequests,
+				RetryAfter: retryAfterSeconds(retryAfter),
+			}
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return false, fiber.ErrInternalServerError
+	}
+
+	if err := c.Send(ctx, user); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (c *EmailVerificationUseCase) Verify(ctx context.Context, request *model.VerifyEmailRequest) (bool, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request body : %+v", err)
+		return false, fiber.ErrBadRequest
+	}
+
+	emailVerification := new(entity.EmailVerification)
+	if err := c.EmailVerificationRepository.FindByTokenHashForUpdate(tx, emailVerification, security.HashToken(request.Token)); err != nil {
+		c.Log.Warnf("Failed find email verification by token : %+v", err)
+		return false, fiber.ErrBadRequest
+	}
+
+	now := time.Now().UnixMilli()
+	if emailVerification.UsedAt != 0 || emailVerification.ExpiresAt <= now {
+		c.Log.Warnf("Email verification is used or expired : %s", emailVerification.ID)
+		return false, fiber.ErrBadRequest
+	}
+
+	user := new(entity.User)
+	if err := c.UserRepository.FindById(tx, user, emailVerification.UserId); err != nil {
+		c.Log.Warnf("Failed find user by id : %+v", err)
+		return false, fiber.ErrBadRequest
+	}
+
+	// the link only proves ownership of the address it was sent to
+	if user.Email != emailVerification.Email {
+		c.Log.Warnf("Email changed since verification was sent : %s", user.ID)
+		return false, fiber.ErrBadRequest
+	}
+
+	user.EmailVerifiedAt = now
+	if err := c.UserRepository.Update(tx, user); err != nil {
+		c.Log.Warnf("Failed save user : %+v", err)
+		return false, fiber.ErrInternalServerError
+	}
+
+	if err := c.EmailVerificationRepository.MarkUsedByUserId(tx, user.ID, now); err != nil {
+		c.Log.Warnf("Failed mark email verification as used : %+v", err)
+		return false, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return false, fiber.ErrInternalServerError
+	}
+
+	return true, nil
+}
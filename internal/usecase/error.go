@@ -0,0 +1,74 @@
+package usecase
+
+import "github.com/gofiber/fiber/v2"
+
+var (
+	// ErrAccessTokenExpired tells clients to exchange their refresh token for a new access token
+	ErrAccessTokenExpired = fiber.NewError(fiber.StatusUnauthorized, "Access token expired")
+	// ErrSessionExpired tells clients the session is over and the user has to log in again
+	ErrSessionExpired = fiber.NewError(fiber.StatusUnauthorized, "Session expired")
+	// ErrApiKeyExpired tells machine clients their API key has to be replaced
+	ErrApiKeyExpired = fiber.NewError(fiber.StatusUnauthorized, "API key expired")
+	// ErrTooManyApiKeys is returned when a user already holds as many API keys as allowed
+	ErrTooManyApiKeys = fiber.NewError(fiber.StatusConflict, "Too many API keys, delete one first")
+	// ErrEmailNotVerified is returned when an action requires a verified email address
+	ErrEmailNotVerified = fiber.NewError(fiber.StatusForbidden, "Email not verified")
+	// ErrInvalidMfaCode is returned when a two-factor code is wrong, the client may try again
+	ErrInvalidMfaCode = fiber.NewError(fiber.StatusUnauthorized, "Invalid two-factor code")
+	// ErrUserDisabled is returned when an administrator disabled the account
+	ErrUserDisabled = fiber.NewError(fiber.StatusForbidden, "Account disabled")
+	// ErrCannotModifySelf is returned when an administrator targets their own account with an admin action
+	ErrCannotModifySelf = fiber.NewError(fiber.StatusConflict, "Can not perform this action on your own account")
+	// ErrIdentityNotLinked is returned when an external identity belongs to no user and may not create one
+	ErrIdentityNotLinked = fiber.NewError(fiber.StatusForbidden, "No account is linked to this identity")
+	// ErrAccountLocked is returned while an account is locked out after too many failed logins
+	ErrAccountLocked = fiber.NewError(fiber.StatusLocked, "Account temporarily locked")
+	// ErrTooManyLoginAttempts is returned while a client is locked out after too many failed logins
+	ErrTooManyLoginAttempts = fiber.NewError(fiber.StatusTooManyRequests, "Too many login attempts")
+	// ErrMfaChallengeExpired tells clients the second login step is over and the user has to log in again
+	ErrMfaChallengeExpired = fiber.NewError(fiber.StatusUnauthorized, "Two-factor challenge expired")
+	// ErrPasswordMismatch is returned when the password confirming a sensitive action is wrong
+	ErrPasswordMismatch = fiber.NewError(fiber.StatusForbidden, "Password confirmation failed")
+	// ErrPasswordNotUpdatable is returned when a profile update carries a password, which has its own endpoint
+	ErrPasswordNotUpdatable = fiber.NewError(fiber.StatusBadRequest, "Change the password at /api/users/_current/_change-password")
+	// ErrReauthenticationRequired is returned when a sensitive action needs a login more recent than the current one
+	ErrReauthenticationRequired = fiber.NewError(fiber.StatusForbidden, "Log in again to confirm this action")
+	// ErrDataExportNotReady is returned when an export is downloaded before the worker built it
+	ErrDataExportNotReady = fiber.NewError(fiber.StatusConflict, "Data export is not ready yet")
+	// ErrWeakPassword is returned when a new password is rejected by the password policy
+	ErrWeakPassword = fiber.NewError(fiber.StatusBadRequest, "Password does not meet the password policy")
+	// ErrImpersonationForbidden is returned when an impersonating administrator tries to change the account itself
+	ErrImpersonationForbidden = fiber.NewError(fiber.StatusForbidden, "Not allowed while impersonating")
+	// ErrInvalidCursor is returned for a page cursor that is malformed or from a list sorted another way
+	ErrInvalidCursor = fiber.NewError(fiber.StatusBadRequest, "Invalid cursor")
+	// ErrCursorNeedsSort is returned for a page cursor of a search by relevance, which has no keys to seek on
+	ErrCursorNeedsSort = fiber.NewError(fiber.StatusBadRequest, "Cursors need a sort when searching with q")
+)
+
+// RetryAfterError is a throttling error that tells the client when to try again
+type RetryAfterError struct {
+	Err        *fiber.Error
+	RetryAfter string
+}
+
+func (e *RetryAfterError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RetryAfterError) Unwrap() error {
+	return e.Err
+}
+
+// FieldError is a validation error that tells the client what is wrong with each rejected field
+type FieldError struct {
+	Err    *fiber.Error
+	Fields map[string][]string
+}
+
+func (e *FieldError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,216 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"go-clean-template/internal/entity"
+	"go-clean-template/internal/repository"
+	"go-clean-template/internal/security"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// LoginProtectionUseCase counts failed logins per account and per client IP and locks them out
+type LoginProtectionUseCase struct {
+	DB                     *gorm.DB
+	Log                    *zap.SugaredLogger
+	LoginAttemptRepository *repository.LoginAttemptRepository
+	AccountThrottle        *security.LoginThrottle
+	IPThrottle             *security.LoginThrottle
+}
+
+func NewLoginProtectionUseCase(db *gorm.DB, logger *zap.SugaredLogger, loginAttemptRepository *repository.LoginAttemptRepository,
+	accountThrottle *security.LoginThrottle, ipThrottle *security.LoginThrottle,
+) *LoginProtectionUseCase {
+	return &LoginProtectionUseCase{
+		DB:                     db,
+		Log:                    logger,
+		LoginAttemptRepository: loginAttemptRepository,
+		AccountThrottle:        accountThrottle,
+		IPThrottle:             ipThrottle,
+	}
+}
+
+// Check refuses the login while the client IP or the account is locked out
+func (c *LoginProtectionUseCase) Check(tx *gorm.DB, userId string, ipAddress string) error {
+	now := time.Now()
+
+	if ipAddress != "" {
+		attempt := new(entity.LoginAttempt)
+		if err := c.LoginAttemptRepository.FindByKey(tx, attempt, ipKey(ipAddress)); err == nil {
+			if retryAfter := c.IPThrottle.RetryAfter(attempt, now); retryAfter > 0 {
+				c.Log.Warnf("Login from locked out ip : %s", ipAddress)
+				return &RetryAfterError{Err: ErrTooManyLoginAttempts, RetryAfter: retryAfterSeconds(retryAfter)}
+			}
+		}
+	}
+
+	attempt := new(entity.LoginAttempt)
+	if err := c.LoginAttemptRepository.FindByKey(tx, attempt, accountKey(userId)); err == nil {
+		if retryAfter := c.AccountThrottle.RetryAfter(attempt, now); retryAfter > 0 {
+			c.Log.Warnf("Login to locked out account : %s", userId)
+			return &RetryAfterError{Err: ErrAccountLocked, RetryAfter: retryAfterSeconds(retryAfter)}
+		}
+	}
+
+	return nil
+}
+
+// Fail counts a failed login and returns the end of the account lockout when this failure started one.
+// Unknown accounts are counted too so a lockout does not reveal whether an account exists
+func (c *LoginProtectionUseCase) Fail(tx *gorm.DB, userId string, ipAddress string) (int64, error) {
+	now := time.Now()
+
+	if ipAddress != "" {
+		if _, err := c.fail(tx, ipKey(ipAddress), c.IPThrottle, now); err != nil {
+			return 0, err
+		}
+	}
+
+	lockedUntil, err := c.fail(tx, accountKey(userId), c.AccountThrottle, now)
+	if err != nil {
+		return 0, err
+	}
+
+	return lockedUntil, nil
+}
+
+// Succeed forgets the failures of an account, the IP counter keeps running so one known
+// password can not be used to reset it while guessing others
+func (c *LoginProtectionUseCase) Succeed(tx *gorm.DB, userId string) error {
+	return c.LoginAttemptRepository.DeleteByKey(tx, accountKey(userId))
+}
+
+// ConfirmPassword checks the password a signed in user confirms a sensitive action with under the lockout of the
+// login, so a stolen access token can not be used to guess it. A wrong password is counted and committed right
+// away, the caller has nothing left to do with the transaction then
+func (c *LoginProtectionUseCase) ConfirmPassword(tx *gorm.DB, passwordHasher security.PasswordHasher, user *entity.User, ipAddress string, password string) error {
+	if err := c.Check(tx, user.ID, ipAddress); err != nil {
+		return err
+	}
+
+	matched, err := passwordHasher.Verify(user.Password, password)
+	if err != nil {
+		c.Log.Warnf("Failed to verify password : %+v", err)
+		return fiber.ErrInternalServerError
+	}
+
+	if !matched {
+		if _, err := c.Fail(tx, user.ID, ipAddress); err != nil {
+			return err
+		}
+
+		if err := tx.Commit().Error; err != nil {
+			c.Log.Warnf("Failed commit transaction : %+v", err)
+			return fiber.ErrInternalServerError
+		}
+
+		c.Log.Warnf("Password confirmation failed : %s", user.ID)
+		return ErrPasswordMismatch
+	}
+
+	if err := c.Succeed(tx, user.ID); err != nil {
+		c.Log.Warnf("Failed reset login attempts : %+v", err)
+		return fiber.ErrInternalServerError
+	}
+
+	return nil
+}
+
+// Throttle counts a request that makes an account send something, such as a login link, and refuses it while
+// the account or the client asked too often. The counters are kept under the action so these requests never lock
+// the password login, and unknown accounts are counted like real ones
+func (c *LoginProtectionUseCase) Throttle(tx *gorm.DB, action string, userId string, ipAddress string) error {
+	now := time.Now()
+
+	keys := map[string]*security.LoginThrottle{action + ":" + accountKey(userId): c.AccountThrottle}
+	if ipAddress != "" {
+		keys[action+":"+ipKey(ipAddress)] = c.IPThrottle
+	}
+
+	for key, throttle := range keys {
+		attempt := new(entity.LoginAttempt)
+		if err := c.LoginAttemptRepository.FindByKey(tx, attempt, key); err == nil {
+			if retryAfter := throttle.RetryAfter(attempt, now); retryAfter > 0 {
+				c.Log.Warnf("Throttled request : %s", key)
+				return &RetryAfterError{Err: fiber.ErrTooManyRequests, RetryAfter: retryAfterSeconds(retryAfter)}
+			}
+		}
+	}
+
+	for key, throttle := range keys {
+		if _, err := c.fail(tx, key, throttle, now); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeleteStale purges counters that no longer lock anything or count towards a lockout
+func (c *LoginProtectionUseCase) DeleteStale(ctx context.Context) (int64, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	resetAfter := max(c.AccountThrottle.ResetAfter, c.IPThrottle.ResetAfter)
+
+	now := time.Now()
+	total, err := c.LoginAttemptRepository.DeleteStale(tx, now.UnixMilli(), now.Add(-resetAfter).UnixMilli())
+	if err != nil {
+		c.Log.Warnf("Failed delete stale login attempts : %+v", err)
+		return 0, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return 0, err
+	}
+
+	return total, nil
+}
+
+func (c *LoginProtectionUseCase) fail(tx *gorm.DB, key string, throttle *security.LoginThrottle, now time.Time) (int64, error) {
+	attempt := new(entity.LoginAttempt)
+	err := c.LoginAttemptRepository.FindByKeyForUpdate(tx, attempt, key)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		c.Log.Warnf("Failed find login attempt : %+v", err)
+		return 0, fiber.ErrInternalServerError
+	}
+	exists := err == nil
+
+	attempt.Key = key
+	locked := throttle.Fail(attempt, now)
+
+	if exists {
+		err = c.LoginAttemptRepository.Update(tx, attempt)
+	} else {
+		err = c.LoginAttemptRepository.Create(tx, attempt)
+	}
+	if err != nil {
+		c.Log.Warnf("Failed save login attempt : %+v", err)
+		return 0, fiber.ErrInternalServerError
+	}
+
+	if !locked {
+		return 0, nil
+	}
+	return attempt.LockedUntil, nil
+}
+
+func accountKey(userId string) string {
+	return "user:" + userId
+}
+
+func ipKey(ipAddress string) string {
+	return "ip:" + ipAddress
+}
+
+// retryAfterSeconds rounds up so clients never retry while still locked out
+func retryAfterSeconds(retryAfter time.Duration) string {
+	return strconv.FormatInt(int64((retryAfter+time.Second-1)/time.Second), 10)
+}
@@ -0,0 +1,148 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"go-clean-template/internal/entity"
+	"go-clean-template/internal/gateway/notifier"
+	"go-clean-template/internal/model"
+	"go-clean-template/internal/repository"
+	"go-clean-template/internal/security"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type MagicLinkUseCase struct {
+	DB                  *gorm.DB
+	Log                 *zap.SugaredLogger
+	Validate            *validator.Validate
+	UserRepository      *repository.UserRepository
+	MagicLinkRepository *repository.MagicLinkRepository
+	Notifier            notifier.Notifier
+	TokenHasher         *security.TokenHasher
+	TokenTTL            time.Duration
+	LoginURL            string
+	// LoginProtectionUseCase limits how many links an account or a client can ask for
+	LoginProtectionUseCase *LoginProtectionUseCase
+}
+
+func NewMagicLinkUseCase(db *gorm.DB, logger *zap.SugaredLogger, validate *validator.Validate,
+	userRepository *repository.UserRepository, magicLinkRepository *repository.MagicLinkRepository,
+	notifier notifier.Notifier, tokenHasher *security.TokenHasher, tokenTTL time.Duration, loginURL string,
+	loginProtectionUseCase *LoginProtectionUseCase,
+) *MagicLinkUseCase {
+	return &MagicLinkUseCase{
+		DB:                     db,
+		Log:                    logger,
+		Validate:               validate,
+		UserRepository:         userRepository,
+		MagicLinkRepository:    magicLinkRepository,
+		Notifier:               notifier,
+		TokenHasher:            tokenHasher,
+		TokenTTL:               tokenTTL,
+		LoginURL:               loginURL,
+		LoginProtectionUseCase: loginProtectionUseCase,
+	}
+}
+
+// Request emails a login link to the account and returns the device token it has to be redeemed with,
+// the answer is the same whether the account exists or the email could be sent, so accounts can not be enumerated
+func (c *MagicLinkUseCase) Request(ctx context.Context, request *model.RequestMagicLinkRequest) (*model.MagicLinkResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request body : %+v", err)
+		return nil, fiber.ErrBadRequest
+	}
+
+	if err := c.LoginProtectionUseCase.Throttle(tx, "magic_link", request.ID, request.IPAddress); err != nil {
+		return nil, err
+	}
+
+	deviceToken, err := security.GenerateOpaqueToken()
+	if err != nil {
+		c.Log.Warnf("Failed generate magic link device token : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	now := time.Now()
+	response := &model.MagicLinkResponse{
+		DeviceToken: deviceToken,
+		ExpiresAt:   now.Add(c.TokenTTL).UnixMilli(),
+	}
+
+	// earlier links stay valid, each is bound to the device that asked for it and all of them end at the first login
+	notification, err := c.createMagicLink(tx, request.ID, deviceToken, response.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	// committed for unknown accounts too, the throttle counts every request
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if notification != nil {
+		if err := c.Notifier.Send(ctx, notification); err != nil {
+			c.Log.Warnf("Failed send magic link notification : %+v", err)
+		}
+	}
+
+	return response, nil
+}
+
+// createMagicLink stores a link for the account and returns the notification carrying it,
+// or no notification when the account does not exist or can not log in by email
+func (c *MagicLinkUseCase) createMagicLink(tx *gorm.DB, userId string, deviceToken string, expiresAt int64) (*model.Notification, error) {
+	user := new(entity.User)
+	if err := c.UserRepository.FindById(tx, user, userId); err != nil {
+		c.Log.Warnf("Failed find user by id : %+v", err)
+		return nil, nil
+	}
+
+	if user.Email == "" {
+		c.Log.Warnf("User has no email to send the magic link to : %s", user.ID)
+		return nil, nil
+	}
+
+	if user.DisabledAt != 0 {
+		c.Log.Warnf("User is disabled : %s", user.ID)
+		return nil, nil
+	}
+
+	token, err := security.GenerateOpaqueToken()
+	if err != nil {
+		c.Log.Warnf("Failed generate magic link token : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	magicLink := &entity.MagicLink{
+		ID:         uuid.NewString(),
+		UserId:     user.ID,
+		Email:      user.Email,
+		TokenHash:  c.TokenHasher.Hash(token),
+		DeviceHash: c.TokenHasher.Hash(deviceToken),
+		ExpiresAt:  expiresAt,
+	}
+
+	if err := c.MagicLinkRepository.Create(tx, magicLink); err != nil {
+		c.Log.Warnf("Failed create magic link : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return &model.Notification{
+		To:      user.Email,
+		Subject: "Your login link",
+		Body: fmt.Sprintf("Use this link on the device you asked for it from to log in, it expires in %s: %s?token=%s",
+			c.TokenTTL, c.LoginURL, url.QueryEscape(token)),
+	}, nil
+}
@@ -0,0 +1,356 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go-clean-template/internal/entity"
+	"go-clean-template/internal/model"
+	"go-clean-template/internal/repository"
+	"go-clean-template/internal/security"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// recoveryCodeCount is the number of recovery codes handed out at once
+const recoveryCodeCount = 10
+
+type MfaUseCase struct {
+	DB                     *gorm.DB
+	Log                    *zap.SugaredLogger
+	Validate               *validator.Validate
+	UserRepository         *repository.UserRepository
+	UserTotpRepository     *repository.UserTotpRepository
+	RecoveryCodeRepository *repository.RecoveryCodeRepository
+	SecretCipher           *security.SecretCipher
+	Issuer                 string
+	// LoginProtectionUseCase counts wrong codes against the same lockout as failed logins
+	LoginProtectionUseCase *LoginProtectionUseCase
+}
+
+func NewMfaUseCase(db *gorm.DB, logger *zap.SugaredLogger, validate *validator.Validate,
+	userRepository *repository.UserRepository, userTotpRepository *repository.UserTotpRepository,
+	recoveryCodeRepository *repository.RecoveryCodeRepository, secretCipher *security.SecretCipher, issuer string,
+	loginProtectionUseCase *LoginProtectionUseCase,
+) *MfaUseCase {
+	return &MfaUseCase{
+		DB:                     db,
+		Log:                    logger,
+		Validate:               validate,
+		UserRepository:         userRepository,
+		UserTotpRepository:     userTotpRepository,
+		RecoveryCodeRepository: recoveryCodeRepository,
+		SecretCipher:           secretCipher,
+		Issuer:                 issuer,
+		LoginProtectionUseCase: loginProtectionUseCase,
+	}
+}
+
+// Enroll starts TOTP enrollment, the secret only becomes active once a first code is confirmed
+func (c *MfaUseCase) Enroll(ctx context.Context, request *model.EnrollTotpRequest) (*model.TotpEnrollmentResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request body : %+v", err)
+		return nil, fiber.ErrBadRequest
+	}
+
+	user := new(entity.User)
+	if err := c.UserRepository.FindById(tx, user, request.ID); err != nil {
+		c.Log.Warnf("Failed find user by id : %+v", err)
+		return nil, fiber.ErrNotFound
+	}
+
+	userTotp := new(entity.UserTotp)
+	err := c.UserTotpRepository.FindByUserIdForUpdate(tx, userTotp, user.ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		c.Log.Warnf("Failed find totp by user id : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+	exists := err == nil
+
+	if userTotp.ConfirmedAt != 0 {
+		c.Log.Warnf("Totp is already enabled : %s", user.ID)
+		return nil, fiber.ErrConflict
+	}
+
+	secret, err := security.GenerateTOTPSecret()
+	if err != nil {
+		c.Log.Warnf("Failed generate totp secret : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	encrypted, err := c.SecretCipher.Encrypt(secret)
+	if err != nil {
+		c.Log.Warnf("Failed encrypt totp secret : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	// restarting enrollment replaces the unconfirmed secret
+	userTotp.UserId = user.ID
+	userTotp.Secret = encrypted
+	userTotp.LastUsedStep = 0
+	if exists {
+		err = c.UserTotpRepository.Update(tx, userTotp)
+	} else {
+		err = c.UserTotpRepository.Create(tx, userTotp)
+	}
+	if err != nil {
+		c.Log.Warnf("Failed save totp : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	account := user.Email
+	if account == "" {
+		account = user.ID
+	}
+
+	return &model.TotpEnrollmentResponse{
+		Secret: secret,
+		URI:    security.TOTPURI(c.Issuer, account, secret),
+	}, nil
+}
+
+// Confirm activates TOTP with a first code from the authenticator and hands out the recovery codes
+func (c *MfaUseCase) Confirm(ctx context.Context, request *model.ConfirmTotpRequest) (*model.RecoveryCodesResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request body : %+v", err)
+		return nil, fiber.ErrBadRequest
+	}
+
+	userTotp := new(entity.UserTotp)
+	if err := c.UserTotpRepository.FindByUserIdForUpdate(tx, userTotp, request.ID); err != nil {
+		c.Log.Warnf("Failed find totp by user id : %+v", err)
+		return nil, fiber.ErrNotFound
+	}
+
+	if userTotp.ConfirmedAt != 0 {
+		c.Log.Warnf("Totp is already enabled : %s", request.ID)
+		return nil, fiber.ErrConflict
+	}
+
+	secret, err := c.SecretCipher.Decrypt(userTotp.Secret)
+	if err != nil {
+		c.Log.Warnf("Failed decrypt totp secret : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	now := time.Now()
+	step, ok := security.ValidateTOTP(secret, request.Code, now, userTotp.LastUsedStep)
+	if !ok {
+		c.Log.Warnf("Invalid totp code : %s", request.ID)
+		return nil, ErrInvalidMfaCode
+	}
+
+	userTotp.ConfirmedAt = now.UnixMilli()
+	userTotp.LastUsedStep = step
+	if err := c.UserTotpRepository.Update(tx, userTotp); err != nil {
+		c.Log.Warnf("Failed save totp : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	codes, err := c.replaceRecoveryCodes(tx, request.ID)
+	if err != nil {
+		c.Log.Warnf("Failed create recovery codes : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return &model.RecoveryCodesResponse{Codes: codes}, nil
+}
+
+func (c *MfaUseCase) Disable(ctx context.Context, request *model.DisableTotpRequest) (bool, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request body : %+v", err)
+		return false, fiber.ErrBadRequest
+	}
+
+	if err := c.verifyCodeThrottled(tx, request.ID, request.IPAddress, request.Code); err != nil {
+		return false, err
+	}
+
+	if err := c.UserTotpRepository.DeleteByUserId(tx, request.ID); err != nil {
+		c.Log.Warnf("Failed delete totp : %+v", err)
+		return false, fiber.ErrInternalServerError
+	}
+
+	if err := c.RecoveryCodeRepository.DeleteByUserId(tx, request.ID); err != nil {
+		c.Log.Warnf("Failed delete recovery codes : %+v", err)
+		return false, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return false, fiber.ErrInternalServerError
+	}
+
+	return true, nil
+}
+
+// RegenerateRecoveryCodes replaces all recovery codes, the previous ones stop working
+func (c *MfaUseCase) RegenerateRecoveryCodes(ctx context.Context, request *model.RegenerateRecoveryCodesRequest) (*model.RecoveryCodesResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request body : %+v", err)
+		return nil, fiber.ErrBadRequest
+	}
+
+	if err := c.verifyCodeThrottled(tx, request.ID, request.IPAddress, request.Code); err != nil {
+		return nil, err
+	}
+
+	codes, err := c.replaceRecoveryCodes(tx, request.ID)
+	if err != nil {
+		c.Log.Warnf("Failed create recovery codes : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return &model.RecoveryCodesResponse{Codes: codes}, nil
+}
+
+// Enabled reports whether the user has a confirmed second factor
+func (c *MfaUseCase) Enabled(tx *gorm.DB, userId string) (bool, error) {
+	total, err := c.UserTotpRepository.CountConfirmedByUserId(tx, userId)
+	if err != nil {
+		c.Log.Warnf("Failed count totp by user id : %+v", err)
+		return false, fiber.ErrInternalServerError
+	}
+	return total > 0, nil
+}
+
+// VerifyCode checks a TOTP code or a recovery code inside the caller's transaction and consumes it
+func (c *MfaUseCase) VerifyCode(tx *gorm.DB, userId string, code string) (bool, error) {
+	userTotp := new(entity.UserTotp)
+	if err := c.UserTotpRepository.FindByUserIdForUpdate(tx, userTotp, userId); err != nil {
+		c.Log.Warnf("Failed find totp by user id : %+v", err)
+		return false, nil
+	}
+
+	if userTotp.ConfirmedAt == 0 {
+		return false, nil
+	}
+
+	now := time.Now()
+	if len(code) == security.TOTPDigits {
+		secret, err := c.SecretCipher.Decrypt(userTotp.Secret)
+		if err != nil {
+			c.Log.Warnf("Failed decrypt totp secret : %+v", err)
+			return false, fiber.ErrInternalServerError
+		}
+
+		step, ok := security.ValidateTOTP(secret, code, now, userTotp.LastUsedStep)
+		if !ok {
+			return false, nil
+		}
+
+		userTotp.LastUsedStep = step
+		if err := c.UserTotpRepository.Update(tx, userTotp); err != nil {
+			c.Log.Warnf("Failed save totp : %+v", err)
+			return false, fiber.ErrInternalServerError
+		}
+		return true, nil
+	}
+
+	recoveryCode := new(entity.RecoveryCode)
+	codeHash := security.HashToken(security.NormalizeRecoveryCode(code))
+	if err := c.RecoveryCodeRepository.FindUnusedByUserIdAndCodeHashForUpdate(tx, recoveryCode, userId, codeHash); err != nil {
+		c.Log.Warnf("Failed find recovery code : %+v", err)
+		return false, nil
+	}
+
+	recoveryCode.UsedAt = now.UnixMilli()
+	if err := c.RecoveryCodeRepository.Update(tx, recoveryCode); err != nil {
+		c.Log.Warnf("Failed save recovery code : %+v", err)
+		return false, fiber.ErrInternalServerError
+	}
+	return true, nil
+}
+
+// verifyCodeThrottled checks the code of a signed in user under the lockout of the login, a wrong code is
+// counted and committed right away so these endpoints give no more guesses than the login does
+func (c *MfaUseCase) verifyCodeThrottled(tx *gorm.DB, userId string, ipAddress string, code string) error {
+	if err := c.LoginProtectionUseCase.Check(tx, userId, ipAddress); err != nil {
+		return err
+	}
+
+	ok, err := c.VerifyCode(tx, userId, code)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		if _, err := c.LoginProtectionUseCase.Fail(tx, userId, ipAddress); err != nil {
+			return err
+		}
+
+		if err := tx.Commit().Error; err != nil {
+			c.Log.Warnf("Failed commit transaction : %+v", err)
+			return fiber.ErrInternalServerError
+		}
+
+		c.Log.Warnf("Invalid two-factor code : %s", userId)
+		return ErrInvalidMfaCode
+	}
+
+	if err := c.LoginProtectionUseCase.Succeed(tx, userId); err != nil {
+		c.Log.Warnf("Failed reset login attempts : %+v", err)
+		return fiber.ErrInternalServerError
+	}
+
+	return nil
+}
+
+func (c *MfaUseCase) replaceRecoveryCodes(tx *gorm.DB, userId string) ([]string, error) {
+	if err := c.RecoveryCodeRepository.DeleteByUserId(tx, userId); err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		code, err := security.GenerateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+
+		recoveryCode := &entity.RecoveryCode{
+			ID:       uuid.NewString(),
+			UserId:   userId,
+			CodeHash: security.HashToken(security.NormalizeRecoveryCode(code)),
+		}
+		if err := c.RecoveryCodeRepository.Create(tx, recoveryCode); err != nil {
+			return nil, err
+		}
+
+		codes = append(codes, code)
+	}
+
+	return codes, nil
+}
@@ -0,0 +1,345 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go-clean-template/internal/entity"
+	"go-clean-template/internal/gateway/messaging"
+	"go-clean-template/internal/gateway/oidc"
+	"go-clean-template/internal/model"
+	"go-clean-template/internal/model/converter"
+	"go-clean-template/internal/repository"
+	"go-clean-template/internal/security"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type OidcUseCase struct {
+	DB                     *gorm.DB
+	Log                    *zap.SugaredLogger
+	Validate               *validator.Validate
+	UserRepository         *repository.UserRepository
+	UserIdentityRepository *repository.UserIdentityRepository
+	OidcStateRepository    *repository.OidcStateRepository
+	// Provider is nil when OpenID Connect login is disabled
+	Provider     *oidc.Provider
+	UserUseCase  *UserUseCase
+	UserProducer *messaging.UserProducer
+	StateTTL     time.Duration
+	// AutoProvision creates a user on the first login of an unknown identity
+	AutoProvision bool
+	// LinkByEmail links an unknown identity to the user with the same email when both sides verified it
+	LinkByEmail bool
+}
+
+func NewOidcUseCase(db *gorm.DB, logger *zap.SugaredLogger, validate *validator.Validate,
+	userRepository *repository.UserRepository, userIdentityRepository *repository.UserIdentityRepository,
+	oidcStateRepository *repository.OidcStateRepository, provider *oidc.Provider, userUseCase *UserUseCase,
+	userProducer *messaging.UserProducer, stateTTL time.Duration, autoProvision bool, linkByEmail bool,
+) *OidcUseCase {
+	return &OidcUseCase{
+		DB:                     db,
+		Log:                    logger,
+		Validate:               validate,
+		UserRepository:         userRepository,
+		UserIdentityRepository: userIdentityRepository,
+		OidcStateRepository:    oidcStateRepository,
+		Provider:               provider,
+		UserUseCase:            userUseCase,
+		UserProducer:           userProducer,
+		StateTTL:               stateTTL,
+		AutoProvision:          autoProvision,
+		LinkByEmail:            linkByEmail,
+	}
+}
+
+// Authorize starts a login at the issuer, the state, nonce and PKCE verifier are kept until the callback
+func (c *OidcUseCase) Authorize(ctx context.Context) (*model.OidcAuthorizationResponse, error) {
+	if c.Provider == nil {
+		c.Log.Warnf("OpenID Connect login is disabled")
+		return nil, fiber.ErrNotFound
+	}
+
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	now := time.Now()
+	if err := tx.Where("expires_at < ?", now.UnixMilli()).Delete(new(entity.OidcState)).Error; err != nil {
+		c.Log.Warnf("Failed delete expired oidc states : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	state, err := security.GenerateOpaqueToken()
+	if err != nil {
+		c.Log.Warnf("Failed generate oidc state : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	nonce, err := security.GenerateOpaqueToken()
+	if err != nil {
+		c.Log.Warnf("Failed generate oidc nonce : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	codeVerifier, codeChallenge, err := security.GeneratePKCE()
+	if err != nil {
+		c.Log.Warnf("Failed generate pkce verifier : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	authorizationURL, err := c.Provider.AuthorizationURL(ctx, state, nonce, codeChallenge)
+	if err != nil {
+		c.Log.Warnf("Failed discover oidc issuer : %+v", err)
+		return nil, fiber.ErrBadGateway
+	}
+
+	oidcState := &entity.OidcState{
+		ID:           uuid.NewString(),
+		StateHash:    security.HashToken(state),
+		Nonce:        nonce,
+		CodeVerifier: codeVerifier,
+		ExpiresAt:    now.Add(c.StateTTL).UnixMilli(),
+	}
+	if err := c.OidcStateRepository.Create(tx, oidcState); err != nil {
+		c.Log.Warnf("Failed create oidc state : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return &model.OidcAuthorizationResponse{AuthorizationURL: authorizationURL}, nil
+}
+
+// Callback redeems the authorization code and logs in the user linked to the identity, users who enabled
+// TOTP still answer an mfa_token challenge since the issuer's own checks are not known to this service
+func (c *OidcUseCase) Callback(ctx context.Context, request *model.OidcCallbackRequest) (*model.UserResponse, error) {
+	if c.Provider == nil {
+		c.Log.Warnf("OpenID Connect login is disabled")
+		return nil, fiber.ErrNotFound
+	}
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request body : %+v", err)
+		return nil, fiber.ErrBadRequest
+	}
+
+	state, err := c.consumeState(ctx, request.State)
+	if err != nil {
+		return nil, err
+	}
+
+	// the issuer is called without a transaction, so a slow issuer holds no locks or connections
+	claims, err := c.Provider.Exchange(ctx, request.Code, state.CodeVerifier)
+	if err != nil {
+		c.Log.Warnf("Failed exchange authorization code : %+v", err)
+		return nil, fiber.ErrUnauthorized
+	}
+
+	if claims.Nonce != state.Nonce {
+		c.Log.Warnf("Id token nonce does not match : %s", state.ID)
+		return nil, fiber.ErrUnauthorized
+	}
+
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	user, err := c.findOrProvisionUser(tx, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.DisabledAt != 0 {
+		c.Log.Warnf("User is disabled : %s", user.ID)
+		return nil, ErrUserDisabled
+	}
+
+	if c.UserUseCase.RequireVerifiedEmail && user.EmailVerifiedAt == 0 {
+		c.Log.Warnf("Email is not verified : %s", user.ID)
+		return nil, ErrEmailNotVerified
+	}
+
+	mfaEnabled, err := c.UserUseCase.MfaUseCase.Enabled(tx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var response *model.UserResponse
+	if mfaEnabled {
+		response, err = c.UserUseCase.createMfaChallenge(tx, user, request.UserAgent, request.IPAddress)
+		if err != nil {
+			c.Log.Warnf("Failed create mfa challenge : %+v", err)
+			return nil, fiber.ErrInternalServerError
+		}
+	} else {
+		response, err = c.UserUseCase.StartSession(tx, user, request.UserAgent, request.IPAddress)
+		if err != nil {
+			c.Log.Warnf("Failed start session : %+v", err)
+			return nil, fiber.ErrInternalServerError
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if mfaEnabled {
+		return response, nil
+	}
+
+	if c.UserProducer != nil {
+		event := converter.UserToEvent(user)
+		c.Log.Info("Publishing user login event")
+		if err := c.UserProducer.Send(event); err != nil {
+			c.Log.Warnf("Failed publish user login event : %+v", err)
+			return nil, fiber.ErrInternalServerError
+		}
+	} else {
+		c.Log.Info("Kafka producer is disabled, skipping user login event")
+	}
+
+	return response, nil
+}
+
+// consumeState deletes the state of a callback in a transaction of its own, it is gone even when
+// the code exchange that follows fails
+func (c *OidcUseCase) consumeState(ctx context.Context, value string) (*entity.OidcState, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	state := new(entity.OidcState)
+	if err := c.OidcStateRepository.FindByStateHashForUpdate(tx, state, security.HashToken(value)); err != nil {
+		c.Log.Warnf("Failed find oidc state : %+v", err)
+		return nil, fiber.ErrUnauthorized
+	}
+
+	if err := c.OidcStateRepository.Delete(tx, state); err != nil {
+		c.Log.Warnf("Failed delete oidc state : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if state.UsedAt != 0 || state.ExpiresAt <= time.Now().UnixMilli() {
+		c.Log.Warnf("Oidc state is used or expired : %s", state.ID)
+		return nil, fiber.ErrUnauthorized
+	}
+
+	return state, nil
+}
+
+// findOrProvisionUser resolves the user of an identity, linking or creating one as the configuration allows
+func (c *OidcUseCase) findOrProvisionUser(tx *gorm.DB, claims *oidc.Claims) (*entity.User, error) {
+	now := time.Now().UnixMilli()
+
+	identity := new(entity.UserIdentity)
+	err := c.UserIdentityRepository.FindByIssuerAndSubject(tx, identity, c.Provider.Issuer, claims.Subject)
+	if err == nil {
+		identity.Email = claims.Email
+		identity.LastLoginAt = now
+		if err := c.UserIdentityRepository.Update(tx, identity); err != nil {
+			c.Log.Warnf("Failed save user identity : %+v", err)
+			return nil, fiber.ErrInternalServerError
+		}
+
+		user := new(entity.User)
+		if err := c.UserRepository.FindById(tx, user, identity.UserId); err != nil {
+			c.Log.Warnf("Failed find user by id : %+v", err)
+			return nil, fiber.ErrInternalServerError
+		}
+		return user, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		c.Log.Warnf("Failed find user identity : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	user := new(entity.User)
+	if claims.Email != "" && claims.EmailVerified && c.LinkByEmail {
+		// both sides must have verified the email, otherwise an unverified sign-up could capture the identity
+		if err := c.UserRepository.FindByEmail(tx, user, claims.Email); err == nil && user.EmailVerifiedAt != 0 {
+			c.Log.Infof("Linking identity %s of %s to user %s by email", claims.Subject, c.Provider.Issuer, user.ID)
+			return user, c.link(tx, user, claims, now)
+		}
+	}
+
+	if !c.AutoProvision {
+		c.Log.Warnf("No user linked to identity %s of %s", claims.Subject, c.Provider.Issuer)
+		return nil, ErrIdentityNotLinked
+	}
+
+	if claims.Email != "" {
+		total, err := c.UserRepository.CountByEmail(tx, claims.Email)
+		if err != nil {
+			c.Log.Warnf("Failed count user by email from database : %+v", err)
+			return nil, fiber.ErrInternalServerError
+		}
+
+		// taking over an existing account has to go through LinkByEmail
+		if total > 0 {
+			c.Log.Warnf("Email already registered : %s", claims.Email)
+			return nil, fiber.ErrConflict
+		}
+	}
+
+	// provisioned users have no password until they reset one
+	user = &entity.User{
+		ID:    uuid.NewString(),
+		Name:  provisionedName(claims),
+		Email: claims.Email,
+	}
+	if claims.Email != "" && claims.EmailVerified {
+		user.EmailVerifiedAt = now
+	}
+
+	if err := c.UserRepository.Create(tx, user); err != nil {
+		c.Log.Warnf("Failed create user to database : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	c.Log.Infof("Provisioned user %s for identity %s of %s", user.ID, claims.Subject, c.Provider.Issuer)
+	return user, c.link(tx, user, claims, now)
+}
+
+func (c *OidcUseCase) link(tx *gorm.DB, user *entity.User, claims *oidc.Claims, now int64) error {
+	identity := &entity.UserIdentity{
+		ID:          uuid.NewString(),
+		UserId:      user.ID,
+		Issuer:      c.Provider.Issuer,
+		Subject:     claims.Subject,
+		Email:       claims.Email,
+		LastLoginAt: now,
+	}
+	if err := c.UserIdentityRepository.Create(tx, identity); err != nil {
+		c.Log.Warnf("Failed create user identity : %+v", err)
+		return fiber.ErrInternalServerError
+	}
+	return nil
+}
+
+func provisionedName(claims *oidc.Claims) string {
+	name := claims.Name
+	if name == "" {
+		name = claims.Email
+	}
+	if name == "" {
+		name = claims.Subject
+	}
+
+	if runes := []rune(name); len(runes) > 100 {
+		name = string(runes[:100])
+	}
+	return name
+}
@@ -0,0 +1,519 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"strings"
+	"time"
+
+	"go-clean-template/internal/entity"
+	"go-clean-template/internal/gateway/messaging"
+	"go-clean-template/internal/model"
+	"go-clean-template/internal/model/converter"
+	"go-clean-template/internal/repository"
+	"go-clean-template/internal/security"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type PasskeyUseCase struct {
+	DB                          *gorm.DB
+	Log                         *zap.SugaredLogger
+	Validate                    *validator.Validate
+	UserRepository              *repository.UserRepository
+	PasskeyRepository           *repository.PasskeyRepository
+	WebAuthnChallengeRepository *repository.WebAuthnChallengeRepository
+	AuditLogRepository          *repository.AuditLogRepository
+	// WebAuthn is nil when passkeys are disabled
+	WebAuthn     *security.WebAuthn
+	UserUseCase  *UserUseCase
+	UserProducer *messaging.UserProducer
+	ChallengeTTL time.Duration
+}
+
+func NewPasskeyUseCase(db *gorm.DB, logger *zap.SugaredLogger, validate *validator.Validate,
+	userRepository *repository.UserRepository, passkeyRepository *repository.PasskeyRepository,
+	webAuthnChallengeRepository *repository.WebAuthnChallengeRepository, auditLogRepository *repository.AuditLogRepository,
+	webAuthn *security.WebAuthn, userUseCase *UserUseCase, userProducer *messaging.UserProducer, challengeTTL time.Duration,
+) *PasskeyUseCase {
+	return &PasskeyUseCase{
+		DB:                          db,
+		Log:                         logger,
+		Validate:                    validate,
+		UserRepository:              userRepository,
+		PasskeyRepository:           passkeyRepository,
+		WebAuthnChallengeRepository: webAuthnChallengeRepository,
+		AuditLogRepository:          auditLogRepository,
+		WebAuthn:                    webAuthn,
+		UserUseCase:                 userUseCase,
+		UserProducer:                userProducer,
+		ChallengeTTL:                challengeTTL,
+	}
+}
+
+// BeginRegistration returns the options to create a passkey with, passkeys the user already has are excluded
+func (c *PasskeyUseCase) BeginRegistration(ctx context.Context, request *model.BeginPasskeyRegistrationRequest) (*model.PasskeyCreationOptions, error) {
+	if c.WebAuthn == nil {
+		c.Log.Warnf("Passkeys are disabled")
+		return nil, fiber.ErrNotFound
+	}
+
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request body : %+v", err)
+		return nil, fiber.ErrBadRequest
+	}
+
+	user := new(entity.User)
+	if err := c.UserRepository.FindById(tx, user, request.UserId); err != nil {
+		c.Log.Warnf("Failed find user by id : %+v", err)
+		return nil, fiber.ErrNotFound
+	}
+
+	passkeys, err := c.PasskeyRepository.FindAllByUserId(tx, user.ID)
+	if err != nil {
+		c.Log.Warnf("Failed find passkeys : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	challenge, err := c.newChallenge(tx, model.WebAuthnRegistration, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	parameters := make([]model.PasskeyCredentialParameters, len(security.WebAuthnAlgorithms))
+	for i, algorithm := range security.WebAuthnAlgorithms {
+		parameters[i] = model.PasskeyCredentialParameters{Type: "public-key", Alg: algorithm}
+	}
+
+	return &model.PasskeyCreationOptions{
+		Challenge: challenge,
+		RP:        model.PasskeyRelyingParty{ID: c.WebAuthn.RPID, Name: c.WebAuthn.RPName},
+		User: model.PasskeyUser{
+			ID:          base64.RawURLEncoding.EncodeToString(security.WebAuthnUserHandle(user.ID)),
+			Name:        user.ID,
+			DisplayName: user.Name,
+		},
+		PubKeyCredParams:   parameters,
+		Timeout:            c.ChallengeTTL.Milliseconds(),
+		ExcludeCredentials: passkeyDescriptors(passkeys),
+		AuthenticatorSelection: model.PasskeyAuthenticatorSelection{
+			ResidentKey:      "required",
+			UserVerification: "required",
+		},
+		Attestation: "none",
+	}, nil
+}
+
+// FinishRegistration verifies the new credential against the registration challenge and stores it
+func (c *PasskeyUseCase) FinishRegistration(ctx context.Context, request *model.FinishPasskeyRegistrationRequest) (*model.PasskeyResponse, error) {
+	if c.WebAuthn == nil {
+		c.Log.Warnf("Passkeys are disabled")
+		return nil, fiber.ErrNotFound
+	}
+
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request body : %+v", err)
+		return nil, fiber.ErrBadRequest
+	}
+
+	clientDataJSON, err := decodeBase64URL(request.Credential.Response.ClientDataJSON)
+	if err != nil {
+		c.Log.Warnf("Invalid client data : %+v", err)
+		return nil, fiber.ErrBadRequest
+	}
+
+	attestationObject, err := decodeBase64URL(request.Credential.Response.AttestationObject)
+	if err != nil {
+		c.Log.Warnf("Invalid attestation object : %+v", err)
+		return nil, fiber.ErrBadRequest
+	}
+
+	challenge, err := c.redeemChallenge(tx, clientDataJSON, model.WebAuthnRegistration)
+	if err != nil {
+		return nil, fiber.ErrBadRequest
+	}
+
+	if challenge.UserId != request.UserId {
+		c.Log.Warnf("Passkey registration challenge belongs to another user : %s", challenge.ID)
+		return nil, fiber.ErrBadRequest
+	}
+
+	credential, err := c.WebAuthn.VerifyRegistration(clientDataJSON, attestationObject, challenge.Challenge)
+	if err != nil {
+		c.Log.Warnf("Failed verify passkey registration : %+v", err)
+		return nil, fiber.ErrBadRequest
+	}
+
+	credentialId := base64.RawURLEncoding.EncodeToString(credential.ID)
+	if credentialId != strings.TrimRight(request.Credential.ID, "=") {
+		c.Log.Warnf("Passkey id does not match its attested credential data")
+		return nil, fiber.ErrBadRequest
+	}
+
+	total, err := c.PasskeyRepository.CountByCredentialId(tx, credentialId)
+	if err != nil {
+		c.Log.Warnf("Failed count passkeys : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if total > 0 {
+		c.Log.Warnf("Passkey is already registered")
+		return nil, fiber.ErrConflict
+	}
+
+	name := request.Name
+	if name == "" {
+		name = "Passkey"
+	}
+
+	passkey := &entity.Passkey{
+		ID:           uuid.NewString(),
+		UserId:       request.UserId,
+		Name:         name,
+		CredentialId: credentialId,
+		PublicKey:    credential.PublicKey,
+		SignCount:    int64(credential.SignCount),
+		AAGUID:       formatAAGUID(credential.AAGUID),
+	}
+	if err := c.PasskeyRepository.Create(tx, passkey); err != nil {
+		c.Log.Warnf("Failed create passkey : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	auditLog := newAuditLog(request.UserId, request.UserId, model.AuditPasskeyAdded, request.UserAgent, request.IPAddress)
+	if err := c.AuditLogRepository.Create(tx, auditLog); err != nil {
+		c.Log.Warnf("Failed create audit log : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return converter.PasskeyToResponse(passkey), nil
+}
+
+func (c *PasskeyUseCase) List(ctx context.Context, request *model.ListPasskeyRequest) ([]model.PasskeyResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request body : %+v", err)
+		return nil, fiber.ErrBadRequest
+	}
+
+	passkeys, err := c.PasskeyRepository.FindAllByUserId(tx, request.UserId)
+	if err != nil {
+		c.Log.Warnf("Failed find passkeys : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	responses := make([]model.PasskeyResponse, len(passkeys))
+	for i, passkey := range passkeys {
+		responses[i] = *converter.PasskeyToResponse(&passkey)
+	}
+
+	return responses, nil
+}
+
+func (c *PasskeyUseCase) Delete(ctx context.Context, request *model.DeletePasskeyRequest) error {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request body : %+v", err)
+		return fiber.ErrBadRequest
+	}
+
+	passkey := new(entity.Passkey)
+	if err := c.PasskeyRepository.FindByIdAndUserId(tx, passkey, request.ID, request.UserId); err != nil {
+		c.Log.Warnf("Failed find passkey : %+v", err)
+		return fiber.ErrNotFound
+	}
+
+	if err := c.PasskeyRepository.Delete(tx, passkey); err != nil {
+		c.Log.Warnf("Failed delete passkey : %+v", err)
+		return fiber.ErrInternalServerError
+	}
+
+	auditLog := newAuditLog(request.UserId, request.UserId, model.AuditPasskeyRemoved, request.UserAgent, request.IPAddress)
+	if err := c.AuditLogRepository.Create(tx, auditLog); err != nil {
+		c.Log.Warnf("Failed create audit log : %+v", err)
+		return fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return fiber.ErrInternalServerError
+	}
+
+	return nil
+}
+
+// BeginLogin returns the options to sign in with a passkey, limited to the passkeys of the account when one is named
+func (c *PasskeyUseCase) BeginLogin(ctx context.Context, request *model.BeginPasskeyLoginRequest) (*model.PasskeyRequestOptions, error) {
+	if c.WebAuthn == nil {
+		c.Log.Warnf("Passkeys are disabled")
+		return nil, fiber.ErrNotFound
+	}
+
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request body : %+v", err)
+		return nil, fiber.ErrBadRequest
+	}
+
+	var passkeys []entity.Passkey
+	if request.ID != "" {
+		var err error
+		passkeys, err = c.PasskeyRepository.FindAllByUserId(tx, request.ID)
+		if err != nil {
+			c.Log.Warnf("Failed find passkeys : %+v", err)
+			return nil, fiber.ErrInternalServerError
+		}
+	}
+
+	challenge, err := c.newChallenge(tx, model.WebAuthnLogin, request.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return &model.PasskeyRequestOptions{
+		Challenge:        challenge,
+		RPID:             c.WebAuthn.RPID,
+		Timeout:          c.ChallengeTTL.Milliseconds(),
+		AllowCredentials: passkeyDescriptors(passkeys),
+		UserVerification: "required",
+	}, nil
+}
+
+// Login verifies a passkey assertion and starts a session like a password login, a verified passkey is both
+// factors at once so no mfa challenge follows
+func (c *PasskeyUseCase) Login(ctx context.Context, request *model.LoginPasskeyRequest) (*model.UserResponse, error) {
+	if c.WebAuthn == nil {
+		c.Log.Warnf("Passkeys are disabled")
+		return nil, fiber.ErrNotFound
+	}
+
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request body : %+v", err)
+		return nil, fiber.ErrBadRequest
+	}
+
+	response := request.Credential.Response
+	clientDataJSON, err := decodeBase64URL(response.ClientDataJSON)
+	if err != nil {
+		c.Log.Warnf("Invalid client data : %+v", err)
+		return nil, fiber.ErrBadRequest
+	}
+
+	authenticatorData, err := decodeBase64URL(response.AuthenticatorData)
+	if err != nil {
+		c.Log.Warnf("Invalid authenticator data : %+v", err)
+		return nil, fiber.ErrBadRequest
+	}
+
+	signature, err := decodeBase64URL(response.Signature)
+	if err != nil {
+		c.Log.Warnf("Invalid signature : %+v", err)
+		return nil, fiber.ErrBadRequest
+	}
+
+	userHandle, err := decodeBase64URL(response.UserHandle)
+	if err != nil {
+		c.Log.Warnf("Invalid user handle : %+v", err)
+		return nil, fiber.ErrBadRequest
+	}
+
+	challenge, err := c.redeemChallenge(tx, clientDataJSON, model.WebAuthnLogin)
+	if err != nil {
+		return nil, fiber.ErrUnauthorized
+	}
+
+	passkey := new(entity.Passkey)
+	if err := c.PasskeyRepository.FindByCredentialIdForUpdate(tx, passkey, strings.TrimRight(request.Credential.ID, "=")); err != nil {
+		c.Log.Warnf("Failed find passkey by credential id : %+v", err)
+		return nil, fiber.ErrUnauthorized
+	}
+
+	if challenge.UserId != "" && challenge.UserId != passkey.UserId {
+		c.Log.Warnf("Passkey login was started for another user : %s", challenge.ID)
+		return nil, fiber.ErrUnauthorized
+	}
+
+	if len(userHandle) > 0 && !bytes.Equal(userHandle, security.WebAuthnUserHandle(passkey.UserId)) {
+		c.Log.Warnf("Passkey user handle does not match : %s", passkey.ID)
+		return nil, fiber.ErrUnauthorized
+	}
+
+	signCount, err := c.WebAuthn.VerifyAssertion(clientDataJSON, authenticatorData, signature, challenge.Challenge,
+		passkey.PublicKey, uint32(passkey.SignCount))
+	if err != nil {
+		c.Log.Warnf("Failed verify passkey assertion : %+v", err)
+		return nil, fiber.ErrUnauthorized
+	}
+
+	passkey.SignCount = int64(signCount)
+	passkey.LastUsedAt = time.Now().UnixMilli()
+	if err := c.PasskeyRepository.Update(tx, passkey); err != nil {
+		c.Log.Warnf("Failed save passkey : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	user := new(entity.User)
+	if err := c.UserRepository.FindById(tx, user, passkey.UserId); err != nil {
+		c.Log.Warnf("Failed find user by id : %+v", err)
+		return nil, fiber.ErrUnauthorized
+	}
+
+	if user.DisabledAt != 0 {
+		c.Log.Warnf("User is disabled : %s", user.ID)
+		return nil, ErrUserDisabled
+	}
+
+	if c.UserUseCase.RequireVerifiedEmail && user.EmailVerifiedAt == 0 {
+		c.Log.Warnf("Email is not verified : %s", user.ID)
+		return nil, ErrEmailNotVerified
+	}
+
+	session, err := c.UserUseCase.StartSession(tx, user, request.UserAgent, request.IPAddress)
+	if err != nil {
+		c.Log.Warnf("Failed start session : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if c.UserProducer != nil {
+		event := converter.UserToEvent(user)
+		c.Log.Info("Publishing user login event")
+		if err := c.UserProducer.Send(event); err != nil {
+			c.Log.Warnf("Failed publish user login event : %+v", err)
+			return nil, fiber.ErrInternalServerError
+		}
+	} else {
+		c.Log.Info("Kafka producer is disabled, skipping user login event")
+	}
+
+	return session, nil
+}
+
+// redeemedChallenge is a challenge redeemed by a ceremony response, with the challenge itself to verify the response against
+type redeemedChallenge struct {
+	*entity.WebAuthnChallenge
+	Challenge string
+}
+
+// newChallenge stores a fresh challenge for a ceremony and returns it base64url encoded, expired ones are purged along the way
+func (c *PasskeyUseCase) newChallenge(tx *gorm.DB, ceremony string, userId string) (string, error) {
+	now := time.Now()
+	if err := c.WebAuthnChallengeRepository.DeleteExpired(tx, now.UnixMilli()); err != nil {
+		c.Log.Warnf("Failed delete expired webauthn challenges : %+v", err)
+		return "", fiber.ErrInternalServerError
+	}
+
+	challenge, err := security.GenerateOpaqueToken()
+	if err != nil {
+		c.Log.Warnf("Failed generate webauthn challenge : %+v", err)
+		return "", fiber.ErrInternalServerError
+	}
+
+	webAuthnChallenge := &entity.WebAuthnChallenge{
+		ID:            uuid.NewString(),
+		ChallengeHash: security.HashToken(challenge),
+		Ceremony:      ceremony,
+		UserId:        userId,
+		ExpiresAt:     now.Add(c.ChallengeTTL).UnixMilli(),
+	}
+	if err := c.WebAuthnChallengeRepository.Create(tx, webAuthnChallenge); err != nil {
+		c.Log.Warnf("Failed create webauthn challenge : %+v", err)
+		return "", fiber.ErrInternalServerError
+	}
+
+	return challenge, nil
+}
+
+// redeemChallenge finds the pending challenge a ceremony response answers and marks it used
+func (c *PasskeyUseCase) redeemChallenge(tx *gorm.DB, clientDataJSON []byte, ceremony string) (*redeemedChallenge, error) {
+	challenge, err := security.WebAuthnChallenge(clientDataJSON)
+	if err != nil {
+		c.Log.Warnf("Failed read webauthn challenge : %+v", err)
+		return nil, err
+	}
+
+	webAuthnChallenge := new(entity.WebAuthnChallenge)
+	if err := c.WebAuthnChallengeRepository.FindByChallengeHashForUpdate(tx, webAuthnChallenge, security.HashToken(challenge)); err != nil {
+		c.Log.Warnf("Failed find webauthn challenge : %+v", err)
+		return nil, err
+	}
+
+	now := time.Now().UnixMilli()
+	if webAuthnChallenge.Ceremony != ceremony || webAuthnChallenge.UsedAt != 0 || webAuthnChallenge.ExpiresAt <= now {
+		c.Log.Warnf("Webauthn challenge is used, expired or of another ceremony : %s", webAuthnChallenge.ID)
+		return nil, security.ErrInvalidWebAuthn
+	}
+
+	webAuthnChallenge.UsedAt = now
+	if err := c.WebAuthnChallengeRepository.Update(tx, webAuthnChallenge); err != nil {
+		c.Log.Warnf("Failed save webauthn challenge : %+v", err)
+		return nil, err
+	}
+
+	return &redeemedChallenge{WebAuthnChallenge: webAuthnChallenge, Challenge: challenge}, nil
+}
+
+func passkeyDescriptors(passkeys []entity.Passkey) []model.PasskeyDescriptor {
+	descriptors := make([]model.PasskeyDescriptor, len(passkeys))
+	for i, passkey := range passkeys {
+		descriptors[i] = model.PasskeyDescriptor{Type: "public-key", ID: passkey.CredentialId}
+	}
+	return descriptors
+}
+
+// decodeBase64URL decodes the binary fields of WebAuthn credentials, some clients pad them
+func decodeBase64URL(value string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(value, "="))
+}
+
+func formatAAGUID(aaguid []byte) string {
+	id, err := uuid.FromBytes(aaguid)
+	if err != nil {
+		return ""
+	}
+	return id.String()
+}
@@ -0,0 +1,214 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"go-clean-template/internal/entity"
+	"go-clean-template/internal/gateway/messaging"
+	"go-clean-template/internal/gateway/notifier"
+	"go-clean-template/internal/model"
+	"go-clean-template/internal/model/converter"
+	"go-clean-template/internal/repository"
+	"go-clean-template/internal/security"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type PasswordResetUseCase struct {
+	DB                      *gorm.DB
+	Log                     *zap.SugaredLogger
+	Validate                *validator.Validate
+	UserRepository          *repository.UserRepository
+	SessionRepository       *repository.SessionRepository
+	PasswordResetRepository *repository.PasswordResetRepository
+	Notifier                notifier.Notifier
+	UserProducer            *messaging.UserProducer
+	TokenTTL                time.Duration
+	ResetURL                string
+	PasswordHasher          security.PasswordHasher
+	PasswordPolicy          *security.PasswordPolicy
+}
+
+func NewPasswordResetUseCase(db *gorm.DB, logger *zap.SugaredLogger, validate *validator.Validate,
+	userRepository *repository.UserRepository, sessionRepository *repository.SessionRepository,
+	passwordResetRepository *repository.PasswordResetRepository, notifier notifier.Notifier,
+	userProducer *messaging.UserProducer, tokenTTL time.Duration, resetURL string, passwordHasher security.PasswordHasher,
+	passwordPolicy *security.PasswordPolicy,
+) *PasswordResetUseCase {
+	return &PasswordResetUseCase{
+		DB:                      db,
+		Log:                     logger,
+		Validate:                validate,
+		UserRepository:          userRepository,
+		SessionRepository:       sessionRepository,
+		PasswordResetRepository: passwordResetRepository,
+		Notifier:                notifier,
+		UserProducer:            userProducer,
+		TokenTTL:                tokenTTL,
+		ResetURL:                resetURL,
+		PasswordHasher:          passwordHasher,
+		PasswordPolicy:          passwordPolicy,
+	}
+}
+
+// Forgot sends a reset link to the user, it succeeds for unknown users too so accounts can not be enumerated
+func (c *PasswordResetUseCase) Forgot(ctx context.Context, request *model.ForgotPasswordRequest) (bool, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request body : %+v", err)
+		return false, fiber.ErrBadRequest
+	}
+
+	user := new(entity.User)
+	if err := c.UserRepository.FindById(tx, user, request.ID); err != nil {
+		c.Log.Warnf("Failed find user by id : %+v", err)
+		return true, nil
+	}
+
+	if user.Email == "" {
+		c.Log.Warnf("User has no email to send the reset link to : %s", user.ID)
+		return true, nil
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return false, fiber.ErrInternalServerError
+	}
+
+	if err := c.Send(ctx, user); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Send delivers a fresh reset link to the user and invalidates the links sent before
+func (c *PasswordResetUseCase) Send(ctx context.Context, user *entity.User) error {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	now := time.Now()
+	if err := c.PasswordResetRepository.MarkUsedByUserId(tx, user.ID, now.UnixMilli()); err != nil {
+		c.Log.Warnf("Failed invalidate previous password resets : %+v", err)
+		return fiber.ErrInternalServerError
+	}
+
+	token, err := security.GenerateOpaqueToken()
+	if err != nil {
+		c.Log.Warnf("Failed generate password reset token : %+v", err)
+		return fiber.ErrInternalServerError
+	}
+
+	passwordReset := &entity.PasswordReset{
+		ID:        uuid.NewString(),
+		UserId:    user.ID,
+		TokenHash: security.HashToken(token),
+		ExpiresAt: now.Add(c.TokenTTL).UnixMilli(),
+	}
+
+	if err := c.PasswordResetRepository.Create(tx, passwordReset); err != nil {
+		c.Log.Warnf("Failed create password reset : %+v", err)
+		return fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return fiber.ErrInternalServerError
+	}
+
+	notification := &model.Notification{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Use this link to reset your password, it expires in %s: %s?token=%s",
+			c.TokenTTL, c.ResetURL, url.QueryEscape(token)),
+	}
+	if err := c.Notifier.Send(ctx, notification); err != nil {
+		c.Log.Warnf("Failed send password reset notification : %+v", err)
+		return fiber.ErrInternalServerError
+	}
+
+	return nil
+}
+
+// Reset redeems a reset token, sets the new password and logs the user out everywhere
+func (c *PasswordResetUseCase) Reset(ctx context.Context, request *model.ResetPasswordRequest) (bool, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request body : %+v", err)
+		return false, fiber.ErrBadRequest
+	}
+
+	passwordReset := new(entity.PasswordReset)
+	if err := c.PasswordResetRepository.FindByTokenHashForUpdate(tx, passwordReset, security.HashToken(request.Token)); err != nil {
+		c.Log.Warnf("Failed find password reset by token : %+v", err)
+		return false, fiber.ErrBadRequest
+	}
+
+	now := time.Now().UnixMilli()
+	if passwordReset.UsedAt != 0 || passwordReset.ExpiresAt <= now {
+		c.Log.Warnf("Password reset is used or expired : %s", passwordReset.ID)
+		return false, fiber.ErrBadRequest
+	}
+
+	user := new(entity.User)
+	if err := c.UserRepository.FindById(tx, user, passwordReset.UserId); err != nil {
+		c.Log.Warnf("Failed find user by id : %+v", err)
+		return false, fiber.ErrBadRequest
+	}
+
+	if err := checkPassword(c.PasswordPolicy, request.Password, user.ID, user.Name); err != nil {
+		c.Log.Warnf("Password rejected by password policy : %+v", err.Fields)
+		return false, err
+	}
+
+	password, err := c.PasswordHasher.Hash(request.Password)
+	if err != nil {
+		c.Log.Warnf("Failed to hash password : %+v", err)
+		return false, fiber.ErrInternalServerError
+	}
+	user.Password = password
+
+	if err := c.UserRepository.Update(tx, user); err != nil {
+		c.Log.Warnf("Failed save user : %+v", err)
+		return false, fiber.ErrInternalServerError
+	}
+
+	if err := c.PasswordResetRepository.MarkUsedByUserId(tx, user.ID, now); err != nil {
+		c.Log.Warnf("Failed mark password reset as used : %+v", err)
+		return false, fiber.ErrInternalServerError
+	}
+
+	if err := c.SessionRepository.RevokeByUserId(tx, user.ID, now); err != nil {
+		c.Log.Warnf("Failed revoke sessions : %+v", err)
+		return false, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return false, fiber.ErrInternalServerError
+	}
+
+	if c.UserProducer != nil {
+		event := converter.UserToEvent(user)
+		c.Log.Info("Publishing user password reset event")
+		if err := c.UserProducer.Send(event); err != nil {
+			c.Log.Warnf("Failed publish user password reset event : %+v", err)
+			return false, fiber.ErrInternalServerError
+		}
+	} else {
+		c.Log.Info("Kafka producer is disabled, skipping user password reset event")
+	}
+
+	return true, nil
+}
@@ -0,0 +1,196 @@
+package usecase
+
+import (
+	"context"
+
+	"go-clean-template/internal/entity"
+	"go-clean-template/internal/model"
+	"go-clean-template/internal/model/converter"
+	"go-clean-template/internal/repository"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type RoleUseCase struct {
+	DB                 *gorm.DB
+	Log                *zap.SugaredLogger
+	Validate           *validator.Validate
+	UserRepository     *repository.UserRepository
+	RoleRepository     *repository.RoleRepository
+	UserRoleRepository *repository.UserRoleRepository
+}
+
+func NewRoleUseCase(db *gorm.DB, logger *zap.SugaredLogger, validate *validator.Validate,
+	userRepository *repository.UserRepository, roleRepository *repository.RoleRepository,
+	userRoleRepository *repository.UserRoleRepository,
+) *RoleUseCase {
+	return &RoleUseCase{
+		DB:                 db,
+		Log:                logger,
+		Validate:           validate,
+		UserRepository:     userRepository,
+		RoleRepository:     roleRepository,
+		UserRoleRepository: userRoleRepository,
+	}
+}
+
+func (c *RoleUseCase) List(ctx context.Context) ([]model.RoleResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	roles := make([]entity.Role, 0)
+	if err := c.RoleRepository.FindAll(tx, &roles); err != nil {
+		c.Log.Warnf("Failed find roles : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	responses := make([]model.RoleResponse, len(roles))
+	for i, role := range roles {
+		responses[i] = *converter.RoleToResponse(&role)
+	}
+
+	return responses, nil
+}
+
+func (c *RoleUseCase) ListByUser(ctx context.Context, request *model.ListUserRoleRequest) ([]model.RoleResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request body : %+v", err)
+		return nil, fiber.ErrBadRequest
+	}
+
+	total, err := c.UserRepository.CountById(tx, request.UserId)
+	if err != nil {
+		c.Log.Warnf("Failed count user from database : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if total == 0 {
+		c.Log.Warnf("User not found : %s", request.UserId)
+		return nil, fiber.ErrNotFound
+	}
+
+	roles := make([]entity.Role, 0)
+	if err := c.RoleRepository.FindAllByUserId(tx, &roles, request.UserId); err != nil {
+		c.Log.Warnf("Failed find roles by user id : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	responses := make([]model.RoleResponse, len(roles))
+	for i, role := range roles {
+		responses[i] = *converter.RoleToResponse(&role)
+	}
+
+	return responses, nil
+}
+
+// Grant gives a role to a user, granting a role that is already held succeeds without changes
+func (c *RoleUseCase) Grant(ctx context.Context, request *model.GrantRoleRequest) (bool, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request body : %+v", err)
+		return false, fiber.ErrBadRequest
+	}
+
+	role, err := c.findGrantableRole(tx, request.Actor, request.RoleId)
+	if err != nil {
+		return false, err
+	}
+
+	user := new(entity.User)
+	if err := c.UserRepository.FindById(tx, user, request.UserId); err != nil {
+		c.Log.Warnf("Failed find user by id : %+v", err)
+		return false, fiber.ErrNotFound
+	}
+
+	total, err := c.UserRoleRepository.CountByUserIdAndRoleId(tx, user.ID, role.ID)
+	if err != nil {
+		c.Log.Warnf("Failed count user role : %+v", err)
+		return false, fiber.ErrInternalServerError
+	}
+
+	if total == 0 {
+		userRole := &entity.UserRole{UserId: user.ID, RoleId: role.ID}
+		if err := c.UserRoleRepository.Create(tx, userRole); err != nil {
+			c.Log.Warnf("Failed create user role : %+v", err)
+			return false, fiber.ErrInternalServerError
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return false, fiber.ErrInternalServerError
+	}
+
+	c.Log.Infof("Role %s granted to %s by %s", role.ID, user.ID, request.Actor.ID)
+	return true, nil
+}
+
+func (c *RoleUseCase) Revoke(ctx context.Context, request *model.RevokeRoleRequest) (bool, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request body : %+v", err)
+		return false, fiber.ErrBadRequest
+	}
+
+	role, err := c.findGrantableRole(tx, request.Actor, request.RoleId)
+	if err != nil {
+		return false, err
+	}
+
+	total, err := c.UserRoleRepository.DeleteByUserIdAndRoleId(tx, request.UserId, role.ID)
+	if err != nil {
+		c.Log.Warnf("Failed delete user role : %+v", err)
+		return false, fiber.ErrInternalServerError
+	}
+
+	if total == 0 {
+		c.Log.Warnf("User %s does not have role %s", request.UserId, role.ID)
+		return false, fiber.ErrNotFound
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return false, fiber.ErrInternalServerError
+	}
+
+	c.Log.Infof("Role %s revoked from %s by %s", role.ID, request.UserId, request.Actor.ID)
+	return true, nil
+}
+
+// findGrantableRole loads a role the actor may hand out or take away, which needs every permission the role carries
+func (c *RoleUseCase) findGrantableRole(tx *gorm.DB, actor *model.Auth, roleId string) (*entity.Role, error) {
+	role := new(entity.Role)
+	if err := c.RoleRepository.FindByIdWithPermissions(tx, role, roleId); err != nil {
+		c.Log.Warnf("Failed find role by id : %+v", err)
+		return nil, fiber.ErrNotFound
+	}
+
+	for _, permission := range role.Permissions {
+		if !actor.HasPermission(permission.ID) {
+			c.Log.Warnf("Actor %s lacks permission %s of role %s", actor.ID, permission.ID, role.ID)
+			return nil, fiber.ErrForbidden
+		}
+	}
+
+	return role, nil
+}
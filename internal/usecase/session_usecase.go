@@ -0,0 +1,135 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"go-clean-template/internal/model"
+	"go-clean-template/internal/model/converter"
+	"go-clean-template/internal/repository"
+	"go-clean-template/internal/security"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type SessionUseCase struct {
+	DB                *gorm.DB
+	Log               *zap.SugaredLogger
+	Validate          *validator.Validate
+	SessionRepository *repository.SessionRepository
+	SessionPolicy     *security.SessionPolicy
+}
+
+func NewSessionUseCase(db *gorm.DB, logger *zap.SugaredLogger, validate *validator.Validate,
+	sessionRepository *repository.SessionRepository, sessionPolicy *security.SessionPolicy,
+) *SessionUseCase {
+	return &SessionUseCase{
+		DB:                db,
+		Log:               logger,
+		Validate:          validate,
+		SessionRepository: sessionRepository,
+		SessionPolicy:     sessionPolicy,
+	}
+}
+
+func (c *SessionUseCase) List(ctx context.Context, request *model.ListSessionRequest) ([]model.SessionResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request body : %+v", err)
+		return nil, fiber.ErrBadRequest
+	}
+
+	now := time.Now()
+	sessions, err := c.SessionRepository.FindAllActiveByUserId(tx, request.UserId, now.UnixMilli(), c.SessionPolicy.IdleBefore(now))
+	if err != nil {
+		c.Log.Warnf("Failed find sessions : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	responses := make([]model.SessionResponse, len(sessions))
+	for i, session := range sessions {
+		responses[i] = *converter.SessionToResponse(&session, request.SessionId)
+	}
+
+	return responses, nil
+}
+
+func (c *SessionUseCase) Revoke(ctx context.Context, request *model.RevokeSessionRequest) error {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request body : %+v", err)
+		return fiber.ErrBadRequest
+	}
+
+	revoked, err := c.SessionRepository.RevokeByIdAndUserId(tx, request.ID, request.UserId, time.Now().UnixMilli())
+	if err != nil {
+		c.Log.Warnf("Failed revoke session : %+v", err)
+		return fiber.ErrInternalServerError
+	}
+
+	if revoked == 0 {
+		c.Log.Warnf("Session not found : %s", request.ID)
+		return fiber.ErrNotFound
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return fiber.ErrInternalServerError
+	}
+
+	return nil
+}
+
+func (c *SessionUseCase) RevokeAll(ctx context.Context, request *model.RevokeAllSessionRequest) error {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request body : %+v", err)
+		return fiber.ErrBadRequest
+	}
+
+	if err := c.SessionRepository.RevokeByUserId(tx, request.UserId, time.Now().UnixMilli()); err != nil {
+		c.Log.Warnf("Failed revoke sessions : %+v", err)
+		return fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return fiber.ErrInternalServerError
+	}
+
+	return nil
+}
+
+// DeleteInactive purges sessions that can no longer be used
+func (c *SessionUseCase) DeleteInactive(ctx context.Context) (int64, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	now := time.Now()
+	total, err := c.SessionRepository.DeleteInactive(tx, now.UnixMilli(), c.SessionPolicy.IdleBefore(now))
+	if err != nil {
+		c.Log.Warnf("Failed delete inactive sessions : %+v", err)
+		return 0, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return 0, err
+	}
+
+	return total, nil
+}
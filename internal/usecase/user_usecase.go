@@ -2,63 +2,215 @@ package usecase
 
 import (
 	"context"
+	"crypto/subtle"
+	"errors"
+	"time"
 
 	"go-clean-template/internal/entity"
 	"go-clean-template/internal/gateway/messaging"
 	"go-clean-template/internal/model"
 	"go-clean-template/internal/model/converter"
 	"go-clean-template/internal/repository"
+	"go-clean-template/internal/security"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
-	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
 
 type UserUseCase struct {
-	DB             *gorm.DB
-	Log            *zap.SugaredLogger
-	Validate       *validator.Validate
-	UserRepository *repository.UserRepository
-	UserProducer   *messaging.UserProducer
+	DB                *gorm.DB
+	Log               *zap.SugaredLogger
+	Validate          *validator.Validate
+	UserRepository    *repository.UserRepository
+	SessionRepository *repository.SessionRepository
+	TokenProvider     *security.TokenProvider
+	SessionPolicy     *security.SessionPolicy
+	UserProducer      *messaging.UserProducer
+	// EmailVerificationUseCase sends the verification link to newly registered users
+	EmailVerificationUseCase *EmailVerificationUseCase
+	// RequireVerifiedEmail blocks login until the user has verified their email
+	RequireVerifiedEmail bool
+	// MfaUseCase checks the second factor of users who enabled it
+	MfaUseCase             *MfaUseCase
+	MfaChallengeRepository *repository.MfaChallengeRepository
+	MfaChallengeTTL        time.Duration
+	MfaChallengeAttempts   int
+	// LoginProtectionUseCase locks accounts and clients out after too many failed logins
+	LoginProtectionUseCase *LoginProtectionUseCase
+	RoleRepository         *repository.RoleRepository
+	PasswordHasher         security.PasswordHasher
+	PasswordPolicy         *security.PasswordPolicy
+	AuditLogRepository     *repository.AuditLogRepository
+	TokenHasher            *security.TokenHasher
+	MagicLinkRepository    *repository.MagicLinkRepository
 }
 
 func NewUserUseCase(db *gorm.DB, logger *zap.SugaredLogger, validate *validator.Validate,
-	userRepository *repository.UserRepository, userProducer *messaging.UserProducer,
+	userRepository *repository.UserRepository, sessionRepository *repository.SessionRepository,
+	tokenProvider *security.TokenProvider, sessionPolicy *security.SessionPolicy, userProducer *messaging.UserProducer,
+	emailVerificationUseCase *EmailVerificationUseCase, requireVerifiedEmail bool,
+	mfaUseCase *MfaUseCase, mfaChallengeRepository *repository.MfaChallengeRepository,
+	mfaChallengeTTL time.Duration, mfaChallengeAttempts int, loginProtectionUseCase *LoginProtectionUseCase,
+	roleRepository *repository.RoleRepository, passwordHasher security.PasswordHasher, passwordPolicy *security.PasswordPolicy,
+	auditLogRepository *repository.AuditLogRepository, tokenHasher *security.TokenHasher,
+	magicLinkRepository *repository.MagicLinkRepository,
 ) *UserUseCase {
 	return &UserUseCase{
-		DB:             db,
-		Log:            logger,
-		Validate:       validate,
-		UserRepository: userRepository,
-		UserProducer:   userProducer,
+		DB:                db,
+		Log:               logger,
+		Validate:          validate,
+		UserRepository:    userRepository,
+		SessionRepository: sessionRepository,
+		TokenProvider:     tokenProvider,
+		SessionPolicy:     sessionPolicy,
+		UserProducer:      userProducer,
+
+		EmailVerificationUseCase: emailVerificationUseCase,
+		RequireVerifiedEmail:     requireVerifiedEmail,
+		MfaUseCase:               mfaUseCase,
+		MfaChallengeRepository:   mfaChallengeRepository,
+		MfaChallengeTTL:          mfaChallengeTTL,
+		MfaChallengeAttempts:     mfaChallengeAttempts,
+		LoginProtectionUseCase:   loginProtectionUseCase,
+		RoleRepository:           roleRepository,
+		PasswordHasher:           passwordHasher,
+		PasswordPolicy:           passwordPolicy,
+		AuditLogRepository:       auditLogRepository,
+		TokenHasher:              tokenHasher,
+		MagicLinkRepository:      magicLinkRepository,
 	}
 }
 
 func (c *UserUseCase) Verify(ctx context.Context, request *model.VerifyUserRequest) (*model.Auth, error) {
-	tx := c.DB.WithContext(ctx).Begin()
-	defer tx.Rollback()
-
 	err := c.Validate.Struct(request)
 	if err != nil {
 		c.Log.Warnf("Invalid request body : %+v", err)
 		return nil, fiber.ErrBadRequest
 	}
 
-	user := new(entity.User)
-	if err := c.UserRepository.FindByToken(tx, user, request.Token); err != nil {
-		c.Log.Warnf("Failed find user by token : %+v", err)
+	// signed access tokens carry the grants of their user, but the session and the account are looked up
+	// on every request so a logout or a disabled account takes effect before the token expires
+	if security.IsJWT(request.Token) {
+		claims, err := c.TokenProvider.ParseAccessToken(request.Token)
+		if errors.Is(err, security.ErrExpiredToken) {
+			c.Log.Warnf("Access token expired")
+			return nil, ErrAccessTokenExpired
+		}
+		if err != nil {
+			c.Log.Warnf("Failed verify access token : %+v", err)
+			return nil, fiber.ErrUnauthorized
+		}
+
+		auth := &model.Auth{
+			ID:          claims.Subject,
+			SessionID:   claims.SessionID,
+			TokenID:     claims.ID,
+			IssuedAt:    claims.IssuedAt.UnixMilli(),
+			ExpiresAt:   claims.ExpiresAt.UnixMilli(),
+			Roles:       claims.Roles,
+			Permissions: claims.Permissions,
+		}
+		if claims.Actor != nil {
+			auth.ActorID = claims.Actor.Subject
+		}
+
+		if err := c.verifyAccessToken(ctx, claims); err != nil {
+			return nil, err
+		}
+
+		return auth, nil
+	}
+
+	if !c.TokenProvider.AcceptsLegacyTokens(time.Now()) {
+		c.Log.Warnf("Legacy token rejected, migration window is over")
+		return nil, fiber.ErrUnauthorized
+	}
+
+	if !security.IsLegacyToken(request.Token) {
+		c.Log.Warnf("Refresh token can not be used as access token")
+		return nil, fiber.ErrUnauthorized
+	}
+
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	session := new(entity.Session)
+	if err := c.findSessionByToken(tx, session, request.Token); err != nil {
+		c.Log.Warnf("Failed find session by token : %+v", err)
 		return nil, fiber.ErrNotFound
 	}
 
+	now := time.Now()
+	if !c.SessionPolicy.Active(session, now) {
+		c.Log.Warnf("Session is revoked or expired : %s", session.ID)
+		return nil, ErrSessionExpired
+	}
+
+	user := new(entity.User)
+	if err := c.UserRepository.FindById(tx, user, session.UserId); err != nil {
+		c.Log.Warnf("Failed find user by id : %+v", err)
+		return nil, fiber.ErrUnauthorized
+	}
+
+	if user.DisabledAt != 0 {
+		c.Log.Warnf("User is disabled : %s", user.ID)
+		return nil, ErrUserDisabled
+	}
+
+	if err := c.SessionRepository.UpdateLastSeenAt(tx, session.ID, now.UnixMilli()); err != nil {
+		c.Log.Warnf("Failed save session : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	roles := make([]entity.Role, 0)
+	if err := c.RoleRepository.FindAllByUserId(tx, &roles, session.UserId); err != nil {
+		c.Log.Warnf("Failed find roles by user id : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		c.Log.Warnf("Failed commit transaction : %+v", err)
 		return nil, fiber.ErrInternalServerError
 	}
 
-	return &model.Auth{ID: user.ID}, nil
+	roleIds, permissions := converter.RolesToGrants(roles)
+	return &model.Auth{ID: session.UserId, SessionID: session.ID, Roles: roleIds, Permissions: permissions}, nil
+}
+
+// verifyAccessToken refuses a signed token whose session ended or whose user was disabled since it was signed,
+// impersonation tokens belong to no session and only depend on the user
+func (c *UserUseCase) verifyAccessToken(ctx context.Context, claims *security.AccessClaims) error {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if claims.SessionID != "" {
+		session := new(entity.Session)
+		if err := c.SessionRepository.FindByIdAndUserId(tx, session, claims.SessionID, claims.Subject); err != nil {
+			c.Log.Warnf("Failed find session by id : %+v", err)
+			return ErrSessionExpired
+		}
+
+		if !c.SessionPolicy.Active(session, time.Now()) {
+			c.Log.Warnf("Session is revoked or expired : %s", session.ID)
+			return ErrSessionExpired
+		}
+	}
+
+	user := new(entity.User)
+	if err := c.UserRepository.FindById(tx, user, claims.Subject); err != nil {
+		c.Log.Warnf("Failed find user by id : %+v", err)
+		return fiber.ErrUnauthorized
+	}
+
+	if user.DisabledAt != 0 {
+		c.Log.Warnf("User is disabled : %s", user.ID)
+		return ErrUserDisabled
+	}
+
+	return nil
 }
 
 func (c *UserUseCase) Create(ctx context.Context, request *model.RegisterUserRequest) (*model.UserResponse, error) {
@@ -82,16 +234,33 @@ func (c *UserUseCase) Create(ctx context.Context, request *model.RegisterUserReq
 		return nil, fiber.ErrConflict
 	}
 
-	password, err := bcrypt.GenerateFromPassword([]byte(request.Password), bcrypt.DefaultCost)
+	total, err = c.UserRepository.CountByEmail(tx, request.Email)
+	if err != nil {
+		c.Log.Warnf("Failed count user by email from database : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if total > 0 {
+		c.Log.Warnf("Email already registered : %s", request.Email)
+		return nil, fiber.ErrConflict
+	}
+
+	if err := checkPassword(c.PasswordPolicy, request.Password, request.ID, request.Name); err != nil {
+		c.Log.Warnf("Password rejected by password policy : %+v", err.Fields)
+		return nil, err
+	}
+
+	password, err := c.PasswordHasher.Hash(request.Password)
 	if err != nil {
-		c.Log.Warnf("Failed to generate bcrype hash : %+v", err)
+		c.Log.Warnf("Failed to hash password : %+v", err)
 		return nil, fiber.ErrInternalServerError
 	}
 
 	user := &entity.User{
 		ID:       request.ID,
-		Password: string(password),
+		Password: password,
 		Name:     request.Name,
+		Email:    request.Email,
 	}
 
 	if err := c.UserRepository.Create(tx, user); err != nil {
@@ -104,6 +273,11 @@ func (c *UserUseCase) Create(ctx context.Context, request *model.RegisterUserReq
 		return nil, fiber.ErrInternalServerError
 	}
 
+	// the account exists either way, a failed delivery can be retried through the resend endpoint
+	if err := c.EmailVerificationUseCase.Send(ctx, user); err != nil {
+		c.Log.Warnf("Failed send email verification : %+v", err)
+	}
+
 	if c.UserProducer != nil {
 		event := converter.UserToEvent(user)
 		c.Log.Info("Publishing user created event")
@@ -127,20 +301,305 @@ func (c *UserUseCase) Login(ctx context.Context, request *model.LoginUserRequest
 		return nil, fiber.ErrBadRequest
 	}
 
+	if err := c.LoginProtectionUseCase.Check(tx, request.ID, request.IPAddress); err != nil {
+		return nil, err
+	}
+
 	user := new(entity.User)
 	if err := c.UserRepository.FindById(tx, user, request.ID); err != nil {
 		c.Log.Warnf("Failed find user by id : %+v", err)
+		return nil, c.loginFailed(tx, request, nil)
+	}
+
+	matched, err := c.PasswordHasher.Verify(user.Password, request.Password)
+	if err != nil || !matched {
+		c.Log.Warnf("Failed to verify user password : %+v", err)
+		return nil, c.loginFailed(tx, request, user)
+	}
+
+	// hashes from before argon2id or with outdated parameters are replaced while the password is at hand
+	if c.PasswordHasher.NeedsRehash(user.Password) {
+		if err := c.rehashPassword(tx, user, request.Password); err != nil {
+			c.Log.Warnf("Failed to rehash user password : %+v", err)
+		}
+	}
+
+	// checked after the password so the state of an account is only revealed to its owner
+	if user.DisabledAt != 0 {
+		c.Log.Warnf("User is disabled : %s", user.ID)
+		return nil, ErrUserDisabled
+	}
+
+	if c.RequireVerifiedEmail && user.EmailVerifiedAt == 0 {
+		c.Log.Warnf("Email is not verified : %s", user.ID)
+		return nil, ErrEmailNotVerified
+	}
+
+	mfaEnabled, err := c.MfaUseCase.Enabled(tx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// the session is only created once the second factor is verified in LoginMfa
+	if mfaEnabled {
+		response, err := c.createMfaChallenge(tx, user, request.UserAgent, request.IPAddress)
+		if err != nil {
+			c.Log.Warnf("Failed create mfa challenge : %+v", err)
+			return nil, fiber.ErrInternalServerError
+		}
+
+		if err := tx.Commit().Error; err != nil {
+			c.Log.Warnf("Failed commit transaction : %+v", err)
+			return nil, fiber.ErrInternalServerError
+		}
+
+		return response, nil
+	}
+
+	// with a second factor the failures are only forgotten once LoginMfa succeeds, or a known password
+	// would reset the lockout that limits guessing the code
+	if err := c.LoginProtectionUseCase.Succeed(tx, user.ID); err != nil {
+		c.Log.Warnf("Failed reset login attempts : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	response, err := c.StartSession(tx, user, request.UserAgent, request.IPAddress)
+	if err != nil {
+		c.Log.Warnf("Failed start session : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if c.UserProducer != nil {
+		event := converter.UserToEvent(user)
+		c.Log.Info("Publishing user login event")
+		if err := c.UserProducer.Send(event); err != nil {
+			c.Log.Warnf("Failed publish user login event : %+v", err)
+			return nil, fiber.ErrInternalServerError
+		}
+	} else {
+		c.Log.Info("Kafka producer is disabled, skipping user login event")
+	}
+
+	return response, nil
+}
+
+// LoginMagicLink redeems a login link on the device that asked for it, the link stands in for the password
+// so users who enabled two-factor authentication still get an mfa challenge
+func (c *UserUseCase) LoginMagicLink(ctx context.Context, request *model.LoginMagicLinkRequest) (*model.UserResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request body : %+v", err)
+		return nil, fiber.ErrBadRequest
+	}
+
+	magicLink := new(entity.MagicLink)
+	if err := c.MagicLinkRepository.FindByTokenHashForUpdate(tx, magicLink, c.TokenHasher.Hash(request.Token)); err != nil {
+		c.Log.Warnf("Failed find magic link by token : %+v", err)
 		return nil, fiber.ErrUnauthorized
 	}
 
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(request.Password)); err != nil {
-		c.Log.Warnf("Failed to compare user password with bcrype hash : %+v", err)
+	now := time.Now().UnixMilli()
+	if magicLink.UsedAt != 0 || magicLink.ExpiresAt <= now {
+		c.Log.Warnf("Magic link is used or expired : %s", magicLink.ID)
 		return nil, fiber.ErrUnauthorized
 	}
 
-	user.Token = uuid.New().String()
-	if err := c.UserRepository.Update(tx, user); err != nil {
-		c.Log.Warnf("Failed save user : %+v", err)
+	// a link opened on another device stays valid for the device it was requested from
+	deviceHash := c.TokenHasher.Hash(request.DeviceToken)
+	if subtle.ConstantTimeCompare([]byte(deviceHash), []byte(magicLink.DeviceHash)) != 1 {
+		c.Log.Warnf("Magic link redeemed from another device : %s", magicLink.ID)
+		return nil, fiber.ErrUnauthorized
+	}
+
+	user := new(entity.User)
+	if err := c.UserRepository.FindById(tx, user, magicLink.UserId); err != nil {
+		c.Log.Warnf("Failed find user by id : %+v", err)
+		return nil, fiber.ErrUnauthorized
+	}
+
+	if user.Email != magicLink.Email {
+		c.Log.Warnf("Email changed since magic link was sent : %s", user.ID)
+		return nil, fiber.ErrUnauthorized
+	}
+
+	if err := c.MagicLinkRepository.MarkUsedByUserId(tx, user.ID, now); err != nil {
+		c.Log.Warnf("Failed mark magic link as used : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if user.DisabledAt != 0 {
+		c.Log.Warnf("User is disabled : %s", user.ID)
+		return nil, ErrUserDisabled
+	}
+
+	// the link was delivered to the email, which proves it belongs to the user
+	if user.EmailVerifiedAt == 0 {
+		user.EmailVerifiedAt = now
+		if err := c.UserRepository.Update(tx, user); err != nil {
+			c.Log.Warnf("Failed save user : %+v", err)
+			return nil, fiber.ErrInternalServerError
+		}
+	}
+
+	mfaEnabled, err := c.MfaUseCase.Enabled(tx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var response *model.UserResponse
+	if mfaEnabled {
+		response, err = c.createMfaChallenge(tx, user, request.UserAgent, request.IPAddress)
+		if err != nil {
+			c.Log.Warnf("Failed create mfa challenge : %+v", err)
+			return nil, fiber.ErrInternalServerError
+		}
+	} else {
+		response, err = c.StartSession(tx, user, request.UserAgent, request.IPAddress)
+		if err != nil {
+			c.Log.Warnf("Failed start session : %+v", err)
+			return nil, fiber.ErrInternalServerError
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if mfaEnabled {
+		return response, nil
+	}
+
+	if c.UserProducer != nil {
+		event := converter.UserToEvent(user)
+		c.Log.Info("Publishing user login event")
+		if err := c.UserProducer.Send(event); err != nil {
+			c.Log.Warnf("Failed publish user login event : %+v", err)
+			return nil, fiber.ErrInternalServerError
+		}
+	} else {
+		c.Log.Info("Kafka producer is disabled, skipping user login event")
+	}
+
+	return response, nil
+}
+
+// loginFailed records the failed attempt, which has to outlive the login transaction, and publishes
+// a user event when it locked the account
+func (c *UserUseCase) loginFailed(tx *gorm.DB, request *model.LoginUserRequest, user *entity.User) error {
+	lockedUntil, err := c.LoginProtectionUseCase.Fail(tx, request.ID, request.IPAddress)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return fiber.ErrInternalServerError
+	}
+
+	if lockedUntil == 0 || user == nil {
+		return fiber.ErrUnauthorized
+	}
+
+	c.Log.Warnf("Account locked after too many failed logins : %s", user.ID)
+	if c.UserProducer != nil {
+		event := converter.UserToEvent(user)
+		event.LockedUntil = lockedUntil
+		c.Log.Info("Publishing user locked event")
+		if err := c.UserProducer.Send(event); err != nil {
+			c.Log.Warnf("Failed publish user locked event : %+v", err)
+		}
+	} else {
+		c.Log.Info("Kafka producer is disabled, skipping user locked event")
+	}
+
+	return fiber.ErrUnauthorized
+}
+
+// LoginMfa completes a login with a TOTP or recovery code, each challenge allows a limited number of attempts
+func (c *UserUseCase) LoginMfa(ctx context.Context, request *model.LoginMfaUserRequest) (*model.UserResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request body : %+v", err)
+		return nil, fiber.ErrBadRequest
+	}
+
+	challenge := new(entity.MfaChallenge)
+	if err := c.MfaChallengeRepository.FindByTokenHashForUpdate(tx, challenge, security.HashToken(request.MfaToken)); err != nil {
+		c.Log.Warnf("Failed find mfa challenge by token : %+v", err)
+		return nil, ErrMfaChallengeExpired
+	}
+
+	now := time.Now()
+	if challenge.UsedAt != 0 || challenge.ExpiresAt <= now.UnixMilli() || challenge.Attempts >= c.MfaChallengeAttempts {
+		c.Log.Warnf("Mfa challenge is used or expired : %s", challenge.ID)
+		return nil, ErrMfaChallengeExpired
+	}
+
+	if err := c.LoginProtectionUseCase.Check(tx, challenge.UserId, request.IPAddress); err != nil {
+		return nil, err
+	}
+
+	ok, err := c.MfaUseCase.VerifyCode(tx, challenge.UserId, request.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		// the failed attempt is kept, the transaction only holds the attempt counters at this point
+		challenge.Attempts++
+		if err := c.MfaChallengeRepository.Update(tx, challenge); err != nil {
+			c.Log.Warnf("Failed save mfa challenge : %+v", err)
+			return nil, fiber.ErrInternalServerError
+		}
+
+		if _, err := c.LoginProtectionUseCase.Fail(tx, challenge.UserId, request.IPAddress); err != nil {
+			return nil, err
+		}
+
+		if err := tx.Commit().Error; err != nil {
+			c.Log.Warnf("Failed commit transaction : %+v", err)
+			return nil, fiber.ErrInternalServerError
+		}
+
+		c.Log.Warnf("Invalid two-factor code : %s", challenge.UserId)
+		return nil, ErrInvalidMfaCode
+	}
+
+	challenge.UsedAt = now.UnixMilli()
+	if err := c.MfaChallengeRepository.Update(tx, challenge); err != nil {
+		c.Log.Warnf("Failed save mfa challenge : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := c.LoginProtectionUseCase.Succeed(tx, challenge.UserId); err != nil {
+		c.Log.Warnf("Failed reset login attempts : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	user := new(entity.User)
+	if err := c.UserRepository.FindById(tx, user, challenge.UserId); err != nil {
+		c.Log.Warnf("Failed find user by id : %+v", err)
+		return nil, fiber.ErrUnauthorized
+	}
+
+	if user.DisabledAt != 0 {
+		c.Log.Warnf("User is disabled : %s", user.ID)
+		return nil, ErrUserDisabled
+	}
+
+	response, err := c.StartSession(tx, user, request.UserAgent, request.IPAddress)
+	if err != nil {
+		c.Log.Warnf("Failed start session : %+v", err)
 		return nil, fiber.ErrInternalServerError
 	}
 
@@ -160,7 +619,259 @@ func (c *UserUseCase) Login(ctx context.Context, request *model.LoginUserRequest
 		c.Log.Info("Kafka producer is disabled, skipping user login event")
 	}
 
-	return converter.UserToTokenResponse(user), nil
+	return response, nil
+}
+
+// Refresh rotates the refresh token of a session, presenting an already rotated token revokes the session
+func (c *UserUseCase) Refresh(ctx context.Context, request *model.RefreshUserRequest) (*model.UserResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request body : %+v", err)
+		return nil, fiber.ErrBadRequest
+	}
+
+	now := time.Now()
+
+	session := new(entity.Session)
+	if err := c.findSessionByToken(tx, session, request.RefreshToken); err != nil {
+		c.Log.Warnf("Failed find session by refresh token : %+v", err)
+
+		// only the token the session was rotated away from counts as reuse, anything else could be forged
+		// by whoever knows a session id and must not be able to log its owner out
+		if err := c.findSessionByPreviousToken(tx, session, request.RefreshToken); err != nil {
+			return nil, fiber.ErrUnauthorized
+		}
+
+		revoked, err := c.SessionRepository.RevokeById(tx, session.ID, now.UnixMilli())
+		if err != nil {
+			c.Log.Warnf("Failed revoke session : %+v", err)
+			return nil, fiber.ErrInternalServerError
+		}
+
+		if revoked > 0 {
+			c.Log.Warnf("Refresh token reuse detected, revoked session : %s", session.ID)
+			if err := tx.Commit().Error; err != nil {
+				c.Log.Warnf("Failed commit transaction : %+v", err)
+				return nil, fiber.ErrInternalServerError
+			}
+
+			return nil, ErrSessionExpired
+		}
+
+		return nil, fiber.ErrUnauthorized
+	}
+
+	if !c.SessionPolicy.Active(session, now) {
+		c.Log.Warnf("Session is revoked or expired : %s", session.ID)
+		return nil, ErrSessionExpired
+	}
+
+	user := new(entity.User)
+	if err := c.UserRepository.FindById(tx, user, session.UserId); err != nil {
+		c.Log.Warnf("Failed find user by id : %+v", err)
+		return nil, fiber.ErrUnauthorized
+	}
+
+	if user.DisabledAt != 0 {
+		c.Log.Warnf("User is disabled : %s", user.ID)
+		return nil, ErrUserDisabled
+	}
+
+	session.UserAgent = request.UserAgent
+	session.IPAddress = request.IPAddress
+	session.LastSeenAt = now.UnixMilli()
+
+	response, err := c.issueToken(tx, user, session)
+	if err != nil {
+		c.Log.Warnf("Failed issue token : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := c.SessionRepository.Update(tx, session); err != nil {
+		c.Log.Warnf("Failed save session : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return response, nil
+}
+
+// Renew slides the session of an access token that passed half of its lifetime and signs a fresh access token
+func (c *UserUseCase) Renew(ctx context.Context, request *model.RenewUserRequest) (*model.UserResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request body : %+v", err)
+		return nil, fiber.ErrBadRequest
+	}
+
+	session := new(entity.Session)
+	if err := c.SessionRepository.FindByIdAndUserId(tx, session, request.SessionId, request.ID); err != nil {
+		c.Log.Warnf("Failed find session by id : %+v", err)
+		return nil, ErrSessionExpired
+	}
+
+	now := time.Now()
+	if !c.SessionPolicy.Active(session, now) {
+		c.Log.Warnf("Session is revoked or expired : %s", session.ID)
+		return nil, ErrSessionExpired
+	}
+
+	user := new(entity.User)
+	if err := c.UserRepository.FindById(tx, user, session.UserId); err != nil {
+		c.Log.Warnf("Failed find user by id : %+v", err)
+		return nil, fiber.ErrUnauthorized
+	}
+
+	if user.DisabledAt != 0 {
+		c.Log.Warnf("User is disabled : %s", user.ID)
+		return nil, ErrUserDisabled
+	}
+
+	if err := c.SessionRepository.UpdateLastSeenAt(tx, session.ID, now.UnixMilli()); err != nil {
+		c.Log.Warnf("Failed save session : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	accessToken, claims, err := c.signAccessToken(tx, session.UserId, session.ID)
+	if err != nil {
+		c.Log.Warnf("Failed issue token : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return converter.UserToTokenResponse(accessToken, claims.ExpiresAt.UnixMilli(), ""), nil
+}
+
+func (c *UserUseCase) rehashPassword(tx *gorm.DB, user *entity.User, password string) error {
+	hash, err := c.PasswordHasher.Hash(password)
+	if err != nil {
+		return err
+	}
+
+	return c.UserRepository.UpdatePassword(tx, user.ID, hash)
+}
+
+// StartSession creates a session for a user who completed a login and signs its tokens,
+// it runs in the caller's transaction so other login methods issue the same tokens as Login
+func (c *UserUseCase) StartSession(tx *gorm.DB, user *entity.User, userAgent string, ipAddress string) (*model.UserResponse, error) {
+	session := c.newSession(user, userAgent, ipAddress)
+	response, err := c.issueToken(tx, user, session)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.SessionRepository.Create(tx, session); err != nil {
+		return nil, err
+	}
+
+	if err := c.AuditLogRepository.Create(tx, newAuditLog(user.ID, user.ID, model.AuditLogin, userAgent, ipAddress)); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (c *UserUseCase) newSession(user *entity.User, userAgent string, ipAddress string) *entity.Session {
+	now := time.Now()
+	return &entity.Session{
+		ID:         uuid.NewString(),
+		UserId:     user.ID,
+		UserAgent:  userAgent,
+		IPAddress:  ipAddress,
+		LastSeenAt: now.UnixMilli(),
+		ExpiresAt:  c.SessionPolicy.ExpiresAt(now),
+	}
+}
+
+func (c *UserUseCase) createMfaChallenge(tx *gorm.DB, user *entity.User, userAgent string, ipAddress string) (*model.UserResponse, error) {
+	token, err := security.GenerateOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+
+	challenge := &entity.MfaChallenge{
+		ID:        uuid.NewString(),
+		UserId:    user.ID,
+		TokenHash: security.HashToken(token),
+		UserAgent: userAgent,
+		IPAddress: ipAddress,
+		ExpiresAt: time.Now().Add(c.MfaChallengeTTL).UnixMilli(),
+	}
+	if err := c.MfaChallengeRepository.Create(tx, challenge); err != nil {
+		return nil, err
+	}
+
+	return &model.UserResponse{MfaRequired: true, MfaToken: token}, nil
+}
+
+// findSessionByToken locks the session a token belongs to, sessions stored before the pepper was configured
+// are found by the plain hash of their token and upgraded in place, so nobody is logged out
+func (c *UserUseCase) findSessionByToken(tx *gorm.DB, session *entity.Session, token string) error {
+	err := c.SessionRepository.FindByTokenHashForUpdate(tx, session, c.TokenHasher.Hash(token))
+	if !errors.Is(err, gorm.ErrRecordNotFound) || !c.TokenHasher.Peppered() {
+		return err
+	}
+
+	if err := c.SessionRepository.FindByTokenHashForUpdate(tx, session, security.HashToken(token)); err != nil {
+		return err
+	}
+
+	session.TokenHash = c.TokenHasher.Hash(token)
+	return c.SessionRepository.UpdateTokenHash(tx, session.ID, session.TokenHash)
+}
+
+// findSessionByPreviousToken finds the session a token was rotated away from, under the peppered
+// as well as the plain hash since the rotation may predate the pepper
+func (c *UserUseCase) findSessionByPreviousToken(tx *gorm.DB, session *entity.Session, token string) error {
+	err := c.SessionRepository.FindByPreviousTokenHash(tx, session, c.TokenHasher.Hash(token))
+	if !errors.Is(err, gorm.ErrRecordNotFound) || !c.TokenHasher.Peppered() {
+		return err
+	}
+
+	return c.SessionRepository.FindByPreviousTokenHash(tx, session, security.HashToken(token))
+}
+
+// issueToken signs an access token for the session and gives the session a new refresh token,
+// the caller is responsible for saving the session
+func (c *UserUseCase) issueToken(tx *gorm.DB, user *entity.User, session *entity.Session) (*model.UserResponse, error) {
+	accessToken, claims, err := c.signAccessToken(tx, user.ID, session.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := security.GenerateRefreshToken(session.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	session.PreviousTokenHash = session.TokenHash
+	session.TokenHash = c.TokenHasher.Hash(refreshToken)
+
+	return converter.UserToTokenResponse(accessToken, claims.ExpiresAt.UnixMilli(), refreshToken), nil
+}
+
+// signAccessToken embeds the user's current roles and permissions in the access token,
+// role changes reach the token at the next renewal or refresh
+func (c *UserUseCase) signAccessToken(tx *gorm.DB, userId string, sessionId string) (string, *security.AccessClaims, error) {
+	roles := make([]entity.Role, 0)
+	if err := c.RoleRepository.FindAllByUserId(tx, &roles, userId); err != nil {
+		return "", nil, err
+	}
+
+	roleIds, permissions := converter.RolesToGrants(roles)
+	return c.TokenProvider.GenerateAccessToken(userId, sessionId, roleIds, permissions)
 }
 
 func (c *UserUseCase) Current(ctx context.Context, request *model.GetUserRequest) (*model.UserResponse, error) {
@@ -201,11 +912,18 @@ func (c *UserUseCase) Logout(ctx context.Context, request *model.LogoutUserReque
 		return false, fiber.ErrNotFound
 	}
 
-	user.Token = ""
-
-	if err := c.UserRepository.Update(tx, user); err != nil {
-		c.Log.Warnf("Failed save user : %+v", err)
-		return false, fiber.ErrInternalServerError
+	now := time.Now().UnixMilli()
+	if request.SessionId != "" {
+		if _, err := c.SessionRepository.RevokeByIdAndUserId(tx, request.SessionId, user.ID, now); err != nil {
+			c.Log.Warnf("Failed revoke session : %+v", err)
+			return false, fiber.ErrInternalServerError
+		}
+	} else {
+		// access tokens issued before sessions existed can only log out everywhere
+		if err := c.SessionRepository.RevokeByUserId(tx, user.ID, now); err != nil {
+			c.Log.Warnf("Failed revoke sessions : %+v", err)
+			return false, fiber.ErrInternalServerError
+		}
 	}
 
 	if err := tx.Commit().Error; err != nil {
@@ -236,6 +954,12 @@ func (c *UserUseCase) Update(ctx context.Context, request *model.UpdateUserReque
 		return nil, fiber.ErrBadRequest
 	}
 
+	// answering a password here with success would let clients believe it was changed
+	if request.Password != nil {
+		c.Log.Warnf("Password sent to profile update : %s", request.ID)
+		return nil, ErrPasswordNotUpdatable
+	}
+
 	user := new(entity.User)
 	if err := c.UserRepository.FindById(tx, user, request.ID); err != nil {
 		c.Log.Warnf("Failed find user by id : %+v", err)
@@ -246,15 +970,6 @@ func (c *UserUseCase) Update(ctx context.Context, request *model.UpdateUserReque
 		user.Name = request.Name
 	}
 
-	if request.Password != "" {
-		password, err := bcrypt.GenerateFromPassword([]byte(request.Password), bcrypt.DefaultCost)
-		if err != nil {
-			c.Log.Warnf("Failed to generate bcrype hash : %+v", err)
-			return nil, fiber.ErrInternalServerError
-		}
-		user.Password = string(password)
-	}
-
 	if err := c.UserRepository.Update(tx, user); err != nil {
 		c.Log.Warnf("Failed save user : %+v", err)
 		return nil, fiber.ErrInternalServerError
@@ -278,3 +993,91 @@ func (c *UserUseCase) Update(ctx context.Context, request *model.UpdateUserReque
 
 	return converter.UserToResponse(user), nil
 }
+
+// ChangePassword sets a new password once the current one is confirmed and logs the user out of every
+// other session, the session making the change stays logged in
+func (c *UserUseCase) ChangePassword(ctx context.Context, request *model.ChangePasswordRequest) (bool, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request body : %+v", err)
+		return false, fiber.ErrBadRequest
+	}
+
+	user := new(entity.User)
+	if err := c.UserRepository.FindByIdForUpdate(tx, user, request.ID); err != nil {
+		c.Log.Warnf("Failed find user by id : %+v", err)
+		return false, fiber.ErrNotFound
+	}
+
+	if err := c.LoginProtectionUseCase.ConfirmPassword(tx, c.PasswordHasher, user, request.IPAddress, request.CurrentPassword); err != nil {
+		return false, err
+	}
+
+	if err := checkPassword(c.PasswordPolicy, request.Password, user.ID, user.Name); err != nil {
+		c.Log.Warnf("Password rejected by password policy : %+v", err.Fields)
+		return false, err
+	}
+
+	password, err := c.PasswordHasher.Hash(request.Password)
+	if err != nil {
+		c.Log.Warnf("Failed to hash password : %+v", err)
+		return false, fiber.ErrInternalServerError
+	}
+	user.Password = password
+
+	if err := c.UserRepository.Update(tx, user); err != nil {
+		c.Log.Warnf("Failed save user : %+v", err)
+		return false, fiber.ErrInternalServerError
+	}
+
+	// access tokens issued before sessions existed can not tell their session apart, so they log out everywhere
+	now := time.Now().UnixMilli()
+	if request.SessionId != "" {
+		if err := c.SessionRepository.RevokeOthersByUserId(tx, user.ID, request.SessionId, now); err != nil {
+			c.Log.Warnf("Failed revoke other sessions : %+v", err)
+			return false, fiber.ErrInternalServerError
+		}
+	} else {
+		if err := c.SessionRepository.RevokeByUserId(tx, user.ID, now); err != nil {
+			c.Log.Warnf("Failed revoke sessions : %+v", err)
+			return false, fiber.ErrInternalServerError
+		}
+	}
+
+	auditLog := newAuditLog(user.ID, user.ID, model.AuditPasswordChanged, request.UserAgent, request.IPAddress)
+	if err := c.AuditLogRepository.Create(tx, auditLog); err != nil {
+		c.Log.Warnf("Failed create audit log : %+v", err)
+		return false, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return false, fiber.ErrInternalServerError
+	}
+
+	if c.UserProducer != nil {
+		event := converter.UserToEvent(user)
+		event.PasswordChangedAt = now
+		c.Log.Info("Publishing user password changed event")
+		if err := c.UserProducer.Send(event); err != nil {
+			c.Log.Warnf("Failed publish user password changed event : %+v", err)
+			return false, fiber.ErrInternalServerError
+		}
+	} else {
+		c.Log.Info("Kafka producer is disabled, skipping user password changed event")
+	}
+
+	return true, nil
+}
+
+// checkPassword applies the password policy to a new password, reporting every reason it is rejected for
+func checkPassword(policy *security.PasswordPolicy, password string, userId string, name string) *FieldError {
+	reasons := policy.Check(password, userId, name)
+	if len(reasons) == 0 {
+		return nil
+	}
+
+	return &FieldError{Err: ErrWeakPassword, Fields: map[string][]string{"password": reasons}}
+}
@@ -0,0 +1,186 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-clean-template/internal/entity"
+	"go-clean-template/internal/model"
+	"go-clean-template/internal/repository"
+	"go-clean-template/internal/usecase"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeleteAccountWrongPassword(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+
+	response, _ := deleteAccount(t, GetToken(t), "wrong")
+	assert.Equal(t, http.StatusForbidden, response.StatusCode)
+
+	user := GetFirstUser(t)
+	assert.Zero(t, user.DeleteAfter)
+}
+
+func TestDeleteAccountLockout(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+	token := GetToken(t)
+
+	threshold := viperConfig.GetInt("login_protection.account.threshold")
+	for i := 0; i < threshold; i++ {
+		response, _ := deleteAccount(t, token, "wrong")
+		assert.Equal(t, http.StatusForbidden, response.StatusCode)
+	}
+
+	// wrong passwords lock the account like failed logins, so a stolen access token can not guess it
+	response, _ := deleteAccount(t, token, "rahasia")
+	assert.Equal(t, http.StatusLocked, response.StatusCode)
+
+	user := GetFirstUser(t)
+	assert.Zero(t, user.DeleteAfter)
+}
+
+func TestDeleteAccount(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+	login := LoginUser(t, "achieva", "rahasia")
+
+	response, responseBody := deleteAccount(t, login.Token, "rahasia")
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Greater(t, responseBody.Data.DeleteAfter, time.Now().UnixMilli())
+
+	// the user is logged out everywhere
+	response, _ = refresh(t, login.RefreshToken)
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+
+	// but keeps the account until the grace period is over
+	assert.Equal(t, responseBody.Data.DeleteAfter, GetFirstUser(t).DeleteAfter)
+	purged, err := accountDeletionUseCase().Purge(context.Background())
+	assert.Nil(t, err)
+	assert.Zero(t, purged)
+}
+
+func TestDeleteAccountRevokesApiKeys(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+
+	_, apiKey := createApiKey(t, GetToken(t), model.CreateApiKeyRequest{
+		Name:   "reader",
+		Scopes: []string{model.ScopeContactsRead},
+	})
+
+	response, _ := deleteAccount(t, GetToken(t), "rahasia")
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	response = requestWithToken(t, http.MethodGet, "/api/contacts", apiKey.Data.Key)
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+}
+
+func TestDeleteAccountWithoutPassword(t *testing.T) {
+	ClearAll()
+
+	// users provisioned by the issuer have no password, a fresh login confirms the deletion instead
+	code, state := oidcIssuer.Authorize(oidcAuthorize(t), ssoIdentity)
+	response, login := oidcCallback(t, code, state)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	err := db.Model(new(entity.Session)).Where("revoked_at = 0").Update("created_at", time.Now().Add(-time.Hour).UnixMilli()).Error
+	assert.Nil(t, err)
+
+	response, _ = deleteAccount(t, login.Data.Token, "")
+	assert.Equal(t, http.StatusForbidden, response.StatusCode)
+	assert.Equal(t, "Log in again to confirm this action", errorMessage(t, response))
+
+	code, state = oidcIssuer.Authorize(oidcAuthorize(t), ssoIdentity)
+	response, login = oidcCallback(t, code, state)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	response, responseBody := deleteAccount(t, login.Data.Token, "")
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.NotZero(t, responseBody.Data.DeleteAfter)
+}
+
+func TestCancelAccountDeletion(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+	deleteAccount(t, GetToken(t), "rahasia")
+
+	request := httptest.NewRequest(http.MethodPost, "/api/users/_current/_cancel-deletion", nil)
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", GetToken(t))
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Zero(t, GetFirstUser(t).DeleteAfter)
+}
+
+func TestPurgeDeletedAccounts(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+	user := GetFirstUser(t)
+	CreateContacts(user, 2)
+	CreateAddresses(t, GetFirstContact(t, user), 2)
+	CreateUser(t, "gemilang", "rahasia")
+	CreateContacts(&entity.User{ID: "gemilang"}, 1)
+	deleteAccount(t, GetToken(t), "rahasia")
+
+	err := db.Model(user).Update("delete_after", time.Now().Add(-time.Minute).UnixMilli()).Error
+	assert.Nil(t, err)
+
+	purged, err := accountDeletionUseCase().Purge(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), purged)
+
+	var total int64
+	assert.Nil(t, db.Model(new(entity.User)).Where("id = ?", "achieva").Count(&total).Error)
+	assert.Zero(t, total)
+	assert.Nil(t, db.Model(new(entity.Contact)).Where("user_id = ?", "achieva").Count(&total).Error)
+	assert.Zero(t, total)
+	assert.Nil(t, db.Model(new(entity.Address)).Count(&total).Error)
+	assert.Zero(t, total)
+	assert.Nil(t, db.Model(new(entity.Session)).Where("user_id = ?", "achieva").Count(&total).Error)
+	assert.Zero(t, total)
+
+	// other accounts are left alone
+	assert.Nil(t, db.Model(new(entity.Contact)).Where("user_id = ?", "gemilang").Count(&total).Error)
+	assert.Equal(t, int64(1), total)
+}
+
+func deleteAccount(t *testing.T, token string, password string) (*http.Response, *model.WebResponse[model.UserResponse]) {
+	bodyJson, err := json.Marshal(model.DeleteUserRequest{Password: password})
+	assert.Nil(t, err)
+
+	request := httptest.NewRequest(http.MethodDelete, "/api/users/_current", strings.NewReader(string(bodyJson)))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", token)
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+
+	bytes, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	responseBody := new(model.WebResponse[model.UserResponse])
+	err = json.Unmarshal(bytes, responseBody)
+	assert.Nil(t, err)
+
+	return response, responseBody
+}
+
+// accountDeletionUseCase builds the use case the worker runs the purge with
+func accountDeletionUseCase() *usecase.AccountDeletionUseCase {
+	return usecase.NewAccountDeletionUseCase(db, log, validate, repository.NewUserRepository(log),
+		repository.NewSessionRepository(log), repository.NewContactRepository(log), repository.NewAddressRepository(log),
+		passwordHasher, nil, repository.NewAuditLogRepository(log), repository.NewApiKeyRepository(log), nil, nil,
+		time.Hour, 5*time.Minute)
+}
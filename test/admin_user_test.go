@@ -0,0 +1,214 @@
+package test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-clean-template/internal/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchUsersForbidden(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+
+	response, _ := searchUsers(t, GetToken(t), "")
+	assert.Equal(t, http.StatusForbidden, response.StatusCode)
+}
+
+func TestSearchUsers(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+	GrantRole(t, "achieva", model.RoleAdmin)
+	for _, id := range []string{"gemilang", "gema", "budi"} {
+		CreateUser(t, id, "rahasia")
+	}
+
+	response, responseBody := searchUsers(t, GetToken(t), "?size=2")
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Len(t, responseBody.Data, 2)
+	assert.Equal(t, int64(4), responseBody.Paging.TotalItem)
+	assert.Equal(t, int64(2), responseBody.Paging.TotalPage)
+
+	response, responseBody = searchUsers(t, GetToken(t), "?id=ge")
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Len(t, responseBody.Data, 2)
+	assert.Equal(t, "gema", responseBody.Data[0].ID)
+	assert.Equal(t, "gemilang", responseBody.Data[1].ID)
+}
+
+func TestGetUserAdmin(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+	GrantRole(t, "achieva", model.RoleAdmin)
+	CreateUser(t, "gemilang", "rahasia")
+
+	response, responseBody := adminUserAction(t, GetToken(t), http.MethodGet, "/api/admin/users/gemilang")
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, "gemilang", responseBody.Data.ID)
+	assert.Equal(t, "gemilang@example.com", responseBody.Data.Email)
+
+	response, _ = adminUserAction(t, GetToken(t), http.MethodGet, "/api/admin/users/unknown")
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+}
+
+func TestDisableUser(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+	GrantRole(t, "achieva", model.RoleAdmin)
+	CreateUser(t, "gemilang", "rahasia")
+	session := LoginUser(t, "gemilang", "rahasia")
+
+	response, responseBody := adminUserAction(t, GetToken(t), http.MethodPost, "/api/admin/users/gemilang/_disable")
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.NotZero(t, responseBody.Data.DisabledAt)
+
+	response = login(t, "gemilang", "rahasia")
+	assert.Equal(t, http.StatusForbidden, response.StatusCode)
+	assert.Equal(t, "Account disabled", errorMessage(t, response))
+
+	// the sessions were revoked along the way
+	response, _ = refresh(t, session.RefreshToken)
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+
+	response, searchBody := searchUsers(t, GetToken(t), "?disabled=true")
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Len(t, searchBody.Data, 1)
+	assert.Equal(t, "gemilang", searchBody.Data[0].ID)
+
+	response, responseBody = adminUserAction(t, GetToken(t), http.MethodPost, "/api/admin/users/gemilang/_enable")
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Zero(t, responseBody.Data.DisabledAt)
+
+	response = login(t, "gemilang", "rahasia")
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+}
+
+func TestDisableUserRefusesAccessToken(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+	GrantRole(t, "achieva", model.RoleAdmin)
+	CreateUser(t, "gemilang", "rahasia")
+	session := LoginUser(t, "gemilang", "rahasia")
+	assert.Equal(t, "gemilang", currentUser(t, session.Token).ID)
+
+	response, _ := adminUserAction(t, GetToken(t), http.MethodPost, "/api/admin/users/gemilang/_disable")
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	// the access token has not expired yet but is refused right away
+	request := httptest.NewRequest(http.MethodGet, "/api/users/_current", nil)
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", session.Token)
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusForbidden, response.StatusCode)
+	assert.Equal(t, "Account disabled", errorMessage(t, response))
+}
+
+func TestDisableUserWrongPassword(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+	GrantRole(t, "achieva", model.RoleAdmin)
+	CreateUser(t, "gemilang", "rahasia")
+
+	response, _ := adminUserAction(t, GetToken(t), http.MethodPost, "/api/admin/users/gemilang/_disable")
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	// a wrong password does not reveal that the account is disabled
+	response = login(t, "gemilang", "salah")
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+}
+
+func TestDisableSelf(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+	GrantRole(t, "achieva", model.RoleAdmin)
+
+	response, _ := adminUserAction(t, GetToken(t), http.MethodPost, "/api/admin/users/achieva/_disable")
+	assert.Equal(t, http.StatusConflict, response.StatusCode)
+}
+
+func TestForceLogoutUser(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+	GrantRole(t, "achieva", model.RoleAdmin)
+	CreateUser(t, "gemilang", "rahasia")
+	session := LoginUser(t, "gemilang", "rahasia")
+
+	request := httptest.NewRequest(http.MethodPost, "/api/admin/users/gemilang/_logout", nil)
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", GetToken(t))
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	response, _ = refresh(t, session.RefreshToken)
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+
+	// the user can still log in again
+	response = login(t, "gemilang", "rahasia")
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+}
+
+func TestAdminResetPassword(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+	GrantRole(t, "achieva", model.RoleAdmin)
+	CreateUser(t, "gemilang", "rahasia")
+
+	request := httptest.NewRequest(http.MethodPost, "/api/admin/users/gemilang/_reset-password", nil)
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", GetToken(t))
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	response = resetPassword(t, GetNotifiedToken(t, "gemilang@example.com"), "rahasia-baru")
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	response = login(t, "gemilang", "rahasia-baru")
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+}
+
+func searchUsers(t *testing.T, token string, query string) (*http.Response, *model.PageResponse[model.UserResponse]) {
+	request := httptest.NewRequest(http.MethodGet, "/api/admin/users"+query, nil)
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", token)
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+
+	bytes, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	responseBody := new(model.PageResponse[model.UserResponse])
+	err = json.Unmarshal(bytes, responseBody)
+	assert.Nil(t, err)
+
+	return response, responseBody
+}
+
+func adminUserAction(t *testing.T, token string, method string, path string) (*http.Response, *model.WebResponse[model.UserResponse]) {
+	request := httptest.NewRequest(method, path, nil)
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", token)
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+
+	bytes, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	responseBody := new(model.WebResponse[model.UserResponse])
+	err = json.Unmarshal(bytes, responseBody)
+	assert.Nil(t, err)
+
+	return response, responseBody
+}
@@ -0,0 +1,239 @@
+package test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-clean-template/internal/entity"
+	"go-clean-template/internal/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateApiKey(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+
+	response, responseBody := createApiKey(t, GetToken(t), model.CreateApiKeyRequest{
+		Name:   "crm sync",
+		Scopes: []string{model.ScopeContactsRead},
+	})
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, "crm sync", responseBody.Data.Name)
+	assert.Equal(t, []string{model.ScopeContactsRead}, responseBody.Data.Scopes)
+	assert.True(t, strings.HasPrefix(responseBody.Data.Key, responseBody.Data.Prefix))
+
+	apiKey := new(entity.ApiKey)
+	err := db.Where("id = ?", responseBody.Data.ID).Take(apiKey).Error
+	assert.Nil(t, err)
+	assert.NotEqual(t, responseBody.Data.Key, apiKey.KeyHash)
+
+	list := listApiKeys(t, GetToken(t))
+	assert.Len(t, list, 1)
+	assert.Empty(t, list[0].Key)
+}
+
+func TestCreateApiKeyInvalidScope(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+
+	response, _ := createApiKey(t, GetToken(t), model.CreateApiKeyRequest{
+		Name:   "admin",
+		Scopes: []string{model.PermissionUsersWrite},
+	})
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+
+	response, _ = createApiKey(t, GetToken(t), model.CreateApiKeyRequest{
+		Name:      "expired",
+		Scopes:    []string{model.ScopeContactsRead},
+		ExpiresAt: time.Now().Add(-time.Minute).UnixMilli(),
+	})
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+}
+
+func TestApiKeyScopes(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+	CreateContacts(GetFirstUser(t), 3)
+
+	_, responseBody := createApiKey(t, GetToken(t), model.CreateApiKeyRequest{
+		Name:   "reader",
+		Scopes: []string{model.ScopeContactsRead},
+	})
+	key := responseBody.Data.Key
+
+	response := requestWithToken(t, http.MethodGet, "/api/contacts", key)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	response = requestWithToken(t, http.MethodDelete, "/api/contacts/"+GetFirstContact(t, GetFirstUser(t)).ID, key)
+	assert.Equal(t, http.StatusForbidden, response.StatusCode)
+
+	// the account itself stays out of reach of API keys
+	response = requestWithToken(t, http.MethodGet, "/api/users/_current", key)
+	assert.Equal(t, http.StatusForbidden, response.StatusCode)
+
+	response = requestWithToken(t, http.MethodGet, "/api/users/_current/api-keys", key)
+	assert.Equal(t, http.StatusForbidden, response.StatusCode)
+
+	apiKey := new(entity.ApiKey)
+	err := db.Where("id = ?", responseBody.Data.ID).Take(apiKey).Error
+	assert.Nil(t, err)
+	assert.NotZero(t, apiKey.LastUsedAt)
+}
+
+func TestApiKeyAdminRoutes(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+	GrantRole(t, "achieva", model.RoleAdmin)
+
+	_, responseBody := createApiKey(t, GetToken(t), model.CreateApiKeyRequest{
+		Name:   "writer",
+		Scopes: []string{model.ScopeContactsRead, model.ScopeContactsWrite},
+	})
+
+	// API keys never carry the roles of their owner
+	response := requestWithToken(t, http.MethodGet, "/api/admin/users", responseBody.Data.Key)
+	assert.Equal(t, http.StatusForbidden, response.StatusCode)
+}
+
+func TestApiKeyExpired(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+
+	_, responseBody := createApiKey(t, GetToken(t), model.CreateApiKeyRequest{
+		Name:      "short lived",
+		Scopes:    []string{model.ScopeContactsRead},
+		ExpiresAt: time.Now().Add(time.Hour).UnixMilli(),
+	})
+
+	err := db.Model(new(entity.ApiKey)).Where("id = ?", responseBody.Data.ID).
+		Update("expires_at", time.Now().Add(-time.Minute).UnixMilli()).Error
+	assert.Nil(t, err)
+
+	response := requestWithToken(t, http.MethodGet, "/api/contacts", responseBody.Data.Key)
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+	assert.Equal(t, "API key expired", errorMessage(t, response))
+}
+
+func TestCreateApiKeyLimit(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+	token := GetToken(t)
+
+	limit := viperConfig.GetInt("api_key.max_per_user")
+	for i := 0; i < limit; i++ {
+		response, _ := createApiKey(t, token, model.CreateApiKeyRequest{Name: "reader", Scopes: []string{model.ScopeContactsRead}})
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+	}
+
+	response, _ := createApiKey(t, token, model.CreateApiKeyRequest{Name: "reader", Scopes: []string{model.ScopeContactsRead}})
+	assert.Equal(t, http.StatusConflict, response.StatusCode)
+
+	// expired keys do not count
+	err := db.Model(new(entity.ApiKey)).Where("user_id = ?", "achieva").Update("expires_at", 1).Error
+	assert.Nil(t, err)
+
+	response, _ = createApiKey(t, token, model.CreateApiKeyRequest{Name: "reader", Scopes: []string{model.ScopeContactsRead}})
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+}
+
+func TestApiKeyOfDisabledUser(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+
+	_, responseBody := createApiKey(t, GetToken(t), model.CreateApiKeyRequest{
+		Name:   "reader",
+		Scopes: []string{model.ScopeContactsRead},
+	})
+
+	err := db.Model(new(entity.User)).Where("id = ?", "achieva").Update("disabled_at", time.Now().UnixMilli()).Error
+	assert.Nil(t, err)
+
+	response := requestWithToken(t, http.MethodGet, "/api/contacts", responseBody.Data.Key)
+	assert.Equal(t, http.StatusForbidden, response.StatusCode)
+
+	// an account scheduled for deletion is refused as well
+	err = db.Model(new(entity.User)).Where("id = ?", "achieva").Updates(map[string]any{
+		"disabled_at":  0,
+		"delete_after": time.Now().Add(time.Hour).UnixMilli(),
+	}).Error
+	assert.Nil(t, err)
+
+	response = requestWithToken(t, http.MethodGet, "/api/contacts", responseBody.Data.Key)
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+}
+
+func TestDeleteApiKey(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+
+	_, responseBody := createApiKey(t, GetToken(t), model.CreateApiKeyRequest{
+		Name:   "reader",
+		Scopes: []string{model.ScopeContactsRead},
+	})
+
+	response := requestWithToken(t, http.MethodDelete, "/api/users/_current/api-keys/"+responseBody.Data.ID, GetToken(t))
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	response = requestWithToken(t, http.MethodGet, "/api/contacts", responseBody.Data.Key)
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+
+	response = requestWithToken(t, http.MethodDelete, "/api/users/_current/api-keys/"+responseBody.Data.ID, GetToken(t))
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+}
+
+func createApiKey(t *testing.T, token string, requestBody model.CreateApiKeyRequest) (*http.Response, *model.WebResponse[model.ApiKeyResponse]) {
+	bodyJson, err := json.Marshal(requestBody)
+	assert.Nil(t, err)
+
+	request := httptest.NewRequest(http.MethodPost, "/api/users/_current/api-keys", strings.NewReader(string(bodyJson)))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", token)
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+
+	bytes, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	responseBody := new(model.WebResponse[model.ApiKeyResponse])
+	err = json.Unmarshal(bytes, responseBody)
+	assert.Nil(t, err)
+
+	return response, responseBody
+}
+
+func listApiKeys(t *testing.T, token string) []model.ApiKeyResponse {
+	request := httptest.NewRequest(http.MethodGet, "/api/users/_current/api-keys", nil)
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", token)
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	bytes, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	responseBody := new(model.WebResponse[[]model.ApiKeyResponse])
+	err = json.Unmarshal(bytes, responseBody)
+	assert.Nil(t, err)
+
+	return responseBody.Data
+}
+
+func requestWithToken(t *testing.T, method string, path string, token string) *http.Response {
+	request := httptest.NewRequest(method, path, nil)
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", token)
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+	return response
+}
@@ -0,0 +1,169 @@
+package test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+
+	"go-clean-template/internal/model"
+)
+
+var authenticator = &TestAuthenticator{RPID: "localhost", Origin: "http://localhost:8080"}
+
+// TestPasskey is a credential held by the TestAuthenticator, SignCount may be rewound to play a cloned authenticator
+type TestPasskey struct {
+	ID         []byte
+	UserHandle []byte
+	SignCount  uint32
+	key        *ecdsa.PrivateKey
+}
+
+// TestAuthenticator is a software authenticator answering WebAuthn ceremonies the way a browser and a
+// platform authenticator would, the user is always present and verified
+type TestAuthenticator struct {
+	RPID   string
+	Origin string
+}
+
+// Create plays navigator.credentials.create and returns the credential with the passkey it holds
+func (a *TestAuthenticator) Create(options *model.PasskeyCreationOptions) (*model.PasskeyAttestation, *TestPasskey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+
+	id := make([]byte, 32)
+	if _, err := rand.Read(id); err != nil {
+		panic(err)
+	}
+
+	userHandle, err := base64.RawURLEncoding.DecodeString(options.User.ID)
+	if err != nil {
+		panic(err)
+	}
+
+	passkey := &TestPasskey{ID: id, UserHandle: userHandle, key: key}
+
+	publicKey, err := key.PublicKey.ECDH()
+	if err != nil {
+		panic(err)
+	}
+	point := publicKey.Bytes()
+	coseKey := cborMap{
+		{int64(1), int64(2)},
+		{int64(3), int64(-7)},
+		{int64(-1), int64(1)},
+		{int64(-2), point[1:33]},
+		{int64(-3), point[33:]},
+	}
+
+	attestedData := make([]byte, 16, 16+2+len(id))
+	attestedData = binary.BigEndian.AppendUint16(attestedData, uint16(len(id)))
+	attestedData = append(attestedData, id...)
+	attestedData = append(attestedData, encodeCBOR(coseKey)...)
+
+	authenticatorData := a.authenticatorData(options.RP.ID, 0x45, passkey.SignCount)
+	authenticatorData = append(authenticatorData, attestedData...)
+
+	attestationObject := cborMap{
+		{"fmt", "none"},
+		{"attStmt", cborMap{}},
+		{"authData", authenticatorData},
+	}
+
+	return &model.PasskeyAttestation{
+		ID:   base64.RawURLEncoding.EncodeToString(id),
+		Type: "public-key",
+		Response: model.PasskeyAttestationResponse{
+			ClientDataJSON:    base64.RawURLEncoding.EncodeToString(a.clientData("webauthn.create", options.Challenge)),
+			AttestationObject: base64.RawURLEncoding.EncodeToString(encodeCBOR(attestationObject)),
+		},
+	}, passkey
+}
+
+// Get plays navigator.credentials.get with the passkey the user picked
+func (a *TestAuthenticator) Get(options *model.PasskeyRequestOptions, passkey *TestPasskey) *model.PasskeyAssertion {
+	passkey.SignCount++
+	authenticatorData := a.authenticatorData(options.RPID, 0x05, passkey.SignCount)
+	clientData := a.clientData("webauthn.get", options.Challenge)
+
+	clientDataHash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(authenticatorData, clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, passkey.key, digest[:])
+	if err != nil {
+		panic(err)
+	}
+
+	return &model.PasskeyAssertion{
+		ID:   base64.RawURLEncoding.EncodeToString(passkey.ID),
+		Type: "public-key",
+		Response: model.PasskeyAssertionResponse{
+			ClientDataJSON:    base64.RawURLEncoding.EncodeToString(clientData),
+			AuthenticatorData: base64.RawURLEncoding.EncodeToString(authenticatorData),
+			Signature:         base64.RawURLEncoding.EncodeToString(signature),
+			UserHandle:        base64.RawURLEncoding.EncodeToString(passkey.UserHandle),
+		},
+	}
+}
+
+func (a *TestAuthenticator) authenticatorData(rpId string, flags byte, signCount uint32) []byte {
+	rpIdHash := sha256.Sum256([]byte(rpId))
+	data := append(rpIdHash[:], flags)
+	return binary.BigEndian.AppendUint32(data, signCount)
+}
+
+func (a *TestAuthenticator) clientData(ceremony string, challenge string) []byte {
+	clientData, err := json.Marshal(map[string]any{
+		"type":        ceremony,
+		"challenge":   challenge,
+		"origin":      a.Origin,
+		"crossOrigin": false,
+	})
+	if err != nil {
+		panic(err)
+	}
+	return clientData
+}
+
+// cborMap keeps the order of its entries, authenticators emit COSE keys in canonical order
+type cborMap [][2]any
+
+// encodeCBOR encodes the few CBOR types authenticators emit
+func encodeCBOR(value any) []byte {
+	switch value := value.(type) {
+	case int64:
+		if value < 0 {
+			return cborHead(1, uint64(-1-value))
+		}
+		return cborHead(0, uint64(value))
+	case []byte:
+		return append(cborHead(2, uint64(len(value))), value...)
+	case string:
+		return append(cborHead(3, uint64(len(value))), value...)
+	case cborMap:
+		data := cborHead(5, uint64(len(value)))
+		for _, entry := range value {
+			data = append(data, encodeCBOR(entry[0])...)
+			data = append(data, encodeCBOR(entry[1])...)
+		}
+		return data
+	}
+	panic("unsupported cbor value")
+}
+
+func cborHead(major byte, argument uint64) []byte {
+	switch {
+	case argument < 24:
+		return []byte{major<<5 | byte(argument)}
+	case argument <= 0xff:
+		return []byte{major<<5 | 24, byte(argument)}
+	case argument <= 0xffff:
+		return binary.BigEndian.AppendUint16([]byte{major<<5 | 25}, uint16(argument))
+	default:
+		return binary.BigEndian.AppendUint32([]byte{major<<5 | 26}, uint32(argument))
+	}
+}
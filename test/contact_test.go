@@ -5,6 +5,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"slices"
 	"strings"
 	"testing"
 
@@ -34,7 +35,7 @@ func TestCreateContact(t *testing.T) {
 	request := httptest.NewRequest(http.MethodPost, "/api/contacts", strings.NewReader(string(bodyJson)))
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", user.Token)
+	request.Header.Set("Authorization", GetToken(t))
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -75,7 +76,7 @@ func TestCreateContactFailed(t *testing.T) {
 	request := httptest.NewRequest(http.MethodPost, "/api/contacts", strings.NewReader(string(bodyJson)))
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", user.Token)
+	request.Header.Set("Authorization", GetToken(t))
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -104,7 +105,7 @@ func TestGetConnect(t *testing.T) {
 
 	request := httptest.NewRequest(http.MethodGet, "/api/contacts/"+contact.ID, nil)
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", user.Token)
+	request.Header.Set("Authorization", GetToken(t))
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -135,7 +136,7 @@ func TestGetContactFailed(t *testing.T) {
 
 	request := httptest.NewRequest(http.MethodGet, "/api/contacts/"+uuid.NewString(), nil)
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", user.Token)
+	request.Header.Set("Authorization", GetToken(t))
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -173,7 +174,7 @@ func TestUpdateContact(t *testing.T) {
 	request := httptest.NewRequest(http.MethodPut, "/api/contacts/"+contact.ID, strings.NewReader(string(bodyJson)))
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", user.Token)
+	request.Header.Set("Authorization", GetToken(t))
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -218,7 +219,7 @@ func TestUpdateContactFailed(t *testing.T) {
 	request := httptest.NewRequest(http.MethodPut, "/api/contacts/"+contact.ID, strings.NewReader(string(bodyJson)))
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", user.Token)
+	request.Header.Set("Authorization", GetToken(t))
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -252,7 +253,7 @@ func TestUpdateContactNotFound(t *testing.T) {
 	request := httptest.NewRequest(http.MethodPut, "/api/contacts/"+uuid.NewString(), strings.NewReader(string(bodyJson)))
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", user.Token)
+	request.Header.Set("Authorization", GetToken(t))
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -280,7 +281,7 @@ func TestDeleteContact(t *testing.T) {
 
 	request := httptest.NewRequest(http.MethodDelete, "/api/contacts/"+contact.ID, nil)
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", user.Token)
+	request.Header.Set("Authorization", GetToken(t))
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -305,7 +306,7 @@ func TestDeleteContactFailed(t *testing.T) {
 
 	request := httptest.NewRequest(http.MethodDelete, "/api/contacts/"+uuid.NewString(), nil)
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", user.Token)
+	request.Header.Set("Authorization", GetToken(t))
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -331,7 +332,7 @@ func TestSearchContact(t *testing.T) {
 
 	request := httptest.NewRequest(http.MethodGet, "/api/contacts", nil)
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", user.Token)
+	request.Header.Set("Authorization", GetToken(t))
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -362,7 +363,7 @@ func TestSearchContactWithPagination(t *testing.T) {
 
 	request := httptest.NewRequest(http.MethodGet, "/api/contacts?page=2&size=5", nil)
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", user.Token)
+	request.Header.Set("Authorization", GetToken(t))
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -393,7 +394,7 @@ func TestSearchContactWithFilter(t *testing.T) {
 
 	request := httptest.NewRequest(http.MethodGet, "/api/contacts?name=contact&phone=08000000&email=example.com", nil)
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", user.Token)
+	request.Header.Set("Authorization", GetToken(t))
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -412,3 +413,329 @@ func TestSearchContactWithFilter(t *testing.T) {
 	assert.Equal(t, 1, responseBody.Paging.Page)
 	assert.Equal(t, 10, responseBody.Paging.Size)
 }
+
+func TestSearchContactQuery(t *testing.T) {
+	TestLogin(t)
+	user := GetFirstUser(t)
+	CreateContacts(user, 5)
+	CreateNamedContact(t, user, "Jonathan", "Smith")
+	CreateNamedContact(t, user, "Maria", "Jonathan")
+	CreateNamedContact(t, user, "Budi", "Santoso")
+
+	response, responseBody := searchContacts(t, "q=jonathan")
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, int64(2), responseBody.Paging.TotalItem)
+
+	// words match as prefixes, and every word has to match
+	_, responseBody = searchContacts(t, "q=jona%20smi")
+	assert.Equal(t, int64(1), responseBody.Paging.TotalItem)
+	assert.Equal(t, "Smith", responseBody.Data[0].LastName)
+
+	_, responseBody = searchContacts(t, "q=contact3%40example.com")
+	assert.Equal(t, int64(1), responseBody.Paging.TotalItem)
+	assert.Equal(t, "3", responseBody.Data[0].LastName)
+
+	_, responseBody = searchContacts(t, "q=080000004")
+	assert.Equal(t, int64(1), responseBody.Paging.TotalItem)
+	assert.Equal(t, "4", responseBody.Data[0].LastName)
+}
+
+func TestSearchContactQueryTypo(t *testing.T) {
+	TestLogin(t)
+	user := GetFirstUser(t)
+	CreateContacts(user, 5)
+	CreateNamedContact(t, user, "Jonathan", "Smith")
+
+	// close in spelling
+	_, responseBody := searchContacts(t, "q=jonathon")
+	assert.Equal(t, int64(1), responseBody.Paging.TotalItem)
+	assert.Equal(t, "Jonathan", responseBody.Data[0].FirstName)
+
+	// alike in sound
+	_, responseBody = searchContacts(t, "q=smyth")
+	assert.Equal(t, int64(1), responseBody.Paging.TotalItem)
+	assert.Equal(t, "Smith", responseBody.Data[0].LastName)
+}
+
+func TestSearchContactQueryRanking(t *testing.T) {
+	TestLogin(t)
+	user := GetFirstUser(t)
+	CreateNamedContact(t, user, "Jon", "Baker")
+	CreateNamedContact(t, user, "John", "Smith")
+	CreateNamedContact(t, user, "Johnny", "Walker")
+
+	_, responseBody := searchContacts(t, "q=john%20smith")
+	assert.Equal(t, "Smith", responseBody.Data[0].LastName)
+
+	_, responseBody = searchContacts(t, "q=john")
+	assert.GreaterOrEqual(t, len(responseBody.Data), 2)
+	assert.Equal(t, "John", responseBody.Data[0].FirstName)
+}
+
+func TestSearchContactQueryOtherUser(t *testing.T) {
+	TestLogin(t)
+	other := CreateUser(t, "other", "rahasia")
+	CreateNamedContact(t, other, "Jonathan", "Smith")
+
+	response, responseBody := searchContacts(t, "q=jonathan")
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, int64(0), responseBody.Paging.TotalItem)
+}
+
+func TestSearchContactQueryTooLong(t *testing.T) {
+	TestLogin(t)
+
+	response, _ := searchContacts(t, "q="+strings.Repeat("a", 101))
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+}
+
+func TestSearchContactSort(t *testing.T) {
+	TestLogin(t)
+	user := GetFirstUser(t)
+	budi := CreateNamedContact(t, user, "Budi", "Santoso")
+	ani := CreateNamedContact(t, user, "Ani", "Santoso")
+	citra := CreateNamedContact(t, user, "Citra", "Wijaya")
+
+	for i, contact := range []*entity.Contact{ani, citra, budi} {
+		err := db.Model(contact).UpdateColumn("updated_at", int64(1000+i)).Error
+		assert.Nil(t, err)
+	}
+
+	_, responseBody := searchContacts(t, "sort=last_name,first_name")
+	assert.Equal(t, []string{"Ani", "Budi", "Citra"}, contactFirstNames(responseBody.Data))
+
+	_, responseBody = searchContacts(t, "sort=-last_name,first_name")
+	assert.Equal(t, []string{"Citra", "Ani", "Budi"}, contactFirstNames(responseBody.Data))
+
+	_, responseBody = searchContacts(t, "sort=-updated_at")
+	assert.Equal(t, []string{"Budi", "Citra", "Ani"}, contactFirstNames(responseBody.Data))
+
+	// sorting wins over relevance
+	_, responseBody = searchContacts(t, "q=santoso&sort=-first_name")
+	assert.Equal(t, []string{"Budi", "Ani"}, contactFirstNames(responseBody.Data))
+}
+
+func TestSearchContactSortUnknown(t *testing.T) {
+	TestLogin(t)
+
+	for _, sort := range []string{"password", "-user_id", "first_name,last_name%20desc"} {
+		response, _ := searchContacts(t, "sort="+sort)
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode, sort)
+	}
+}
+
+func TestSearchContactSortTwice(t *testing.T) {
+	TestLogin(t)
+
+	for _, sort := range []string{"email,email", "email,-email", "-first_name,last_name,first_name"} {
+		response, _ := searchContacts(t, "sort="+sort)
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode, sort)
+	}
+}
+
+func TestSearchContactFields(t *testing.T) {
+	TestLogin(t)
+	user := GetFirstUser(t)
+	contact := CreateNamedContact(t, user, "Budi", "Santoso")
+
+	request := httptest.NewRequest(http.MethodGet, "/api/contacts?fields=first_name,email", nil)
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", GetToken(t))
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	bytes, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	responseBody := new(model.PageResponse[map[string]any])
+	err = json.Unmarshal(bytes, responseBody)
+	assert.Nil(t, err)
+
+	assert.Equal(t, int64(1), responseBody.Paging.TotalItem)
+	assert.Equal(t, map[string]any{
+		"id":         contact.ID,
+		"first_name": "Budi",
+		"email":      "budi.santoso@example.com",
+	}, responseBody.Data[0])
+}
+
+func TestSearchContactFieldsUnknown(t *testing.T) {
+	TestLogin(t)
+
+	for _, fields := range []string{"user_id", "first_name,addresses", "email,email"} {
+		response, _ := searchContacts(t, "fields="+fields)
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode, fields)
+	}
+}
+
+func TestSearchContactCursor(t *testing.T) {
+	TestLogin(t)
+	user := GetFirstUser(t)
+	CreateContacts(user, 25)
+
+	var names []string
+	query := "size=10&sort=last_name"
+	for page := 0; page < 3; page++ {
+		response, responseBody := searchContacts(t, query)
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		names = append(names, contactLastNames(responseBody.Data)...)
+
+		if page == 2 {
+			assert.Empty(t, responseBody.Paging.Next)
+			break
+		}
+		assert.NotEmpty(t, responseBody.Paging.Next)
+		query = "size=10&sort=last_name&after=" + responseBody.Paging.Next
+
+		// counting is left out once paging by cursor
+		if page > 0 {
+			assert.Equal(t, int64(0), responseBody.Paging.TotalItem)
+		}
+	}
+
+	assert.Len(t, names, 25)
+	assert.True(t, slices.IsSorted(names))
+
+	// and back again
+	_, responseBody := searchContacts(t, "size=10&sort=last_name&page=3")
+	assert.NotEmpty(t, responseBody.Paging.Previous)
+	_, responseBody = searchContacts(t, "size=10&sort=last_name&before="+responseBody.Paging.Previous)
+	assert.Equal(t, names[10:20], contactLastNames(responseBody.Data))
+	assert.NotEmpty(t, responseBody.Paging.Previous)
+	assert.NotEmpty(t, responseBody.Paging.Next)
+
+	_, responseBody = searchContacts(t, "size=10&sort=last_name&before="+responseBody.Paging.Previous)
+	assert.Equal(t, names[:10], contactLastNames(responseBody.Data))
+	assert.Empty(t, responseBody.Paging.Previous)
+}
+
+func TestSearchContactCursorStable(t *testing.T) {
+	TestLogin(t)
+	user := GetFirstUser(t)
+	CreateContacts(user, 10)
+
+	_, first := searchContacts(t, "size=5")
+	assert.NotEmpty(t, first.Paging.Next)
+
+	// contacts removed from the first page do not shift the next one
+	for _, contact := range first.Data[:3] {
+		err := db.Where("id = ?", contact.ID).Delete(new(entity.Contact)).Error
+		assert.Nil(t, err)
+	}
+
+	_, second := searchContacts(t, "size=5&after="+first.Paging.Next)
+	assert.Len(t, second.Data, 5)
+	for _, contact := range second.Data {
+		assert.NotContains(t, contactIds(first.Data), contact.ID)
+	}
+	assert.Empty(t, second.Paging.Next)
+}
+
+func TestSearchContactCursorTotal(t *testing.T) {
+	TestLogin(t)
+	user := GetFirstUser(t)
+	CreateContacts(user, 15)
+
+	_, first := searchContacts(t, "size=10&total=false")
+	assert.Equal(t, int64(0), first.Paging.TotalItem)
+	assert.NotEmpty(t, first.Paging.Next)
+
+	_, second := searchContacts(t, "size=10&total=true&after="+first.Paging.Next)
+	assert.Equal(t, int64(15), second.Paging.TotalItem)
+	assert.Equal(t, int64(2), second.Paging.TotalPage)
+	assert.Len(t, second.Data, 5)
+}
+
+func TestSearchContactCursorNull(t *testing.T) {
+	TestLogin(t)
+	user := GetFirstUser(t)
+	CreateContacts(user, 10)
+
+	// contacts kept from before last_name and email were filled in have them NULL
+	err := db.Model(new(entity.Contact)).Where("user_id = ? AND last_name IN ?", user.ID, []string{"1", "4", "7"}).
+		Updates(map[string]any{"last_name": nil, "email": nil}).Error
+	assert.Nil(t, err)
+
+	for _, sort := range []string{"last_name", "-last_name", "email,first_name", "-email"} {
+		var ids []string
+		query := "size=3&sort=" + sort
+		for page := 0; page < 4; page++ {
+			response, responseBody := searchContacts(t, query)
+			assert.Equal(t, http.StatusOK, response.StatusCode, sort)
+			ids = append(ids, contactIds(responseBody.Data)...)
+
+			if responseBody.Paging.Next == "" {
+				break
+			}
+			query = "size=3&sort=" + sort + "&after=" + responseBody.Paging.Next
+		}
+
+		// every contact shows up once, the NULL ones included
+		assert.Len(t, ids, 10, sort)
+		assert.Len(t, slices.Compact(slices.Sorted(slices.Values(ids))), 10, sort)
+	}
+}
+
+func TestSearchContactCursorInvalid(t *testing.T) {
+	TestLogin(t)
+	user := GetFirstUser(t)
+	CreateContacts(user, 15)
+
+	_, first := searchContacts(t, "size=10&sort=last_name")
+
+	for _, query := range []string{
+		"after=not-a-cursor",
+		// issued for another sort
+		"sort=-last_name&after=" + first.Paging.Next,
+		"after=" + first.Paging.Next + "&before=" + first.Paging.Next,
+		// relevance has no keys to seek on
+		"q=contact&after=" + first.Paging.Next,
+	} {
+		response, _ := searchContacts(t, query)
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode, query)
+	}
+}
+
+func contactLastNames(contacts []model.ContactResponse) []string {
+	names := make([]string, len(contacts))
+	for i, contact := range contacts {
+		names[i] = contact.LastName
+	}
+	return names
+}
+
+func contactIds(contacts []model.ContactResponse) []string {
+	ids := make([]string, len(contacts))
+	for i, contact := range contacts {
+		ids[i] = contact.ID
+	}
+	return ids
+}
+
+func contactFirstNames(contacts []model.ContactResponse) []string {
+	names := make([]string, len(contacts))
+	for i, contact := range contacts {
+		names[i] = contact.FirstName
+	}
+	return names
+}
+
+func searchContacts(t *testing.T, query string) (*http.Response, *model.PageResponse[model.ContactResponse]) {
+	request := httptest.NewRequest(http.MethodGet, "/api/contacts?"+query, nil)
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", GetToken(t))
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+
+	bytes, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	responseBody := new(model.PageResponse[model.ContactResponse])
+	err = json.Unmarshal(bytes, responseBody)
+	assert.Nil(t, err)
+
+	return response, responseBody
+}
@@ -0,0 +1,207 @@
+package test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-clean-template/internal/entity"
+	"go-clean-template/internal/model"
+	"go-clean-template/internal/repository"
+	"go-clean-template/internal/usecase"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateDataExport(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+	token := GetToken(t)
+
+	response, responseBody := createDataExport(t, token)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, model.DataExportPending, responseBody.Data.Status)
+
+	// asking again while it is queued returns the same export
+	response, again := createDataExport(t, token)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, responseBody.Data.ID, again.Data.ID)
+
+	response, _ = downloadDataExport(t, token, responseBody.Data.ID)
+	assert.Equal(t, http.StatusConflict, response.StatusCode)
+}
+
+func TestDownloadDataExport(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+	user := GetFirstUser(t)
+	CreateContacts(user, 2)
+	CreateAddresses(t, GetFirstContact(t, user), 1)
+	token := GetToken(t)
+
+	_, created := createDataExport(t, token)
+	built, err := dataExportUseCase().Build(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), built)
+
+	response, status := getDataExport(t, token, created.Data.ID)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, model.DataExportReady, status.Data.Status)
+	assert.Greater(t, status.Data.ExpiresAt, time.Now().UnixMilli())
+
+	notification := notifier.Last("achieva@example.com")
+	assert.NotNil(t, notification)
+	assert.Contains(t, notification.Body, created.Data.ID+"/download")
+
+	response, archive := downloadDataExport(t, token, created.Data.ID)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, "application/zip", response.Header.Get("Content-Type"))
+
+	files := readDataExport(t, archive)
+
+	profile := new(model.UserResponse)
+	assert.Nil(t, json.Unmarshal(files["profile.json"], profile))
+	assert.Equal(t, "achieva", profile.ID)
+
+	contacts := make([]model.ContactResponse, 0)
+	assert.Nil(t, json.Unmarshal(files["contacts.json"], &contacts))
+	assert.Len(t, contacts, 2)
+	assert.Equal(t, 2, strings.Count(string(files["contacts.vcf"]), "BEGIN:VCARD"))
+	assert.Equal(t, 1, strings.Count(string(files["contacts.vcf"]), "ADR:"))
+
+	sessions := make([]model.SessionResponse, 0)
+	assert.Nil(t, json.Unmarshal(files["sessions.json"], &sessions))
+	assert.Len(t, sessions, 1)
+
+	auditLogs := make([]model.AuditLogResponse, 0)
+	assert.Nil(t, json.Unmarshal(files["audit_log.json"], &auditLogs))
+	assert.Equal(t, model.AuditLogin, auditLogs[0].Action)
+	assert.Equal(t, model.AuditDataExport, auditLogs[len(auditLogs)-1].Action)
+}
+
+func TestDownloadDataExportWithTrash(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+	user := GetFirstUser(t)
+	CreateContacts(user, 2)
+	contact := GetFirstContact(t, user)
+	CreateAddresses(t, contact, 2)
+	token := GetToken(t)
+
+	// the trash is still held until the purge, so it belongs in the export
+	now := time.Now().UnixMilli()
+	err := db.Model(new(entity.Contact)).Where("user_id = ? AND id <> ?", user.ID, contact.ID).Update("deleted_at", now).Error
+	assert.Nil(t, err)
+	err = db.Model(GetFirstAddress(t, contact)).Update("deleted_at", now).Error
+	assert.Nil(t, err)
+
+	_, created := createDataExport(t, token)
+	_, err = dataExportUseCase().Build(context.Background())
+	assert.Nil(t, err)
+
+	response, archive := downloadDataExport(t, token, created.Data.ID)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	files := readDataExport(t, archive)
+
+	contacts := make([]model.ContactResponse, 0)
+	assert.Nil(t, json.Unmarshal(files["contacts.json"], &contacts))
+	assert.Len(t, contacts, 2)
+
+	var trashedContacts, trashedAddresses int
+	for _, exported := range contacts {
+		if exported.DeletedAt != 0 {
+			trashedContacts++
+		}
+		for _, address := range exported.Addresses {
+			if address.DeletedAt != 0 {
+				trashedAddresses++
+			}
+		}
+	}
+	assert.Equal(t, 1, trashedContacts)
+	assert.Equal(t, 1, trashedAddresses)
+
+	// vCards can not mark the trash, so it is only in contacts.json
+	assert.Equal(t, 1, strings.Count(string(files["contacts.vcf"]), "BEGIN:VCARD"))
+	assert.Equal(t, 1, strings.Count(string(files["contacts.vcf"]), "ADR:"))
+}
+
+func TestDownloadDataExportOfOtherUser(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+	CreateUser(t, "gemilang", "rahasia")
+
+	_, created := createDataExport(t, LoginUser(t, "gemilang", "rahasia").Token)
+	_, err := dataExportUseCase().Build(context.Background())
+	assert.Nil(t, err)
+
+	response, _ := downloadDataExport(t, GetToken(t), created.Data.ID)
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+}
+
+func readDataExport(t *testing.T, archive []byte) map[string][]byte {
+	reader, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	assert.Nil(t, err)
+
+	files := map[string][]byte{}
+	for _, file := range reader.File {
+		content, err := file.Open()
+		assert.Nil(t, err)
+		files[file.Name], err = io.ReadAll(content)
+		assert.Nil(t, err)
+	}
+	return files
+}
+
+func createDataExport(t *testing.T, token string) (*http.Response, *model.WebResponse[model.DataExportResponse]) {
+	return dataExportRequest(t, token, http.MethodPost, "/api/users/_current/export")
+}
+
+func getDataExport(t *testing.T, token string, id string) (*http.Response, *model.WebResponse[model.DataExportResponse]) {
+	return dataExportRequest(t, token, http.MethodGet, "/api/users/_current/exports/"+id)
+}
+
+func dataExportRequest(t *testing.T, token string, method string, path string) (*http.Response, *model.WebResponse[model.DataExportResponse]) {
+	request := httptest.NewRequest(method, path, nil)
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", token)
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+
+	bytes, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	responseBody := new(model.WebResponse[model.DataExportResponse])
+	err = json.Unmarshal(bytes, responseBody)
+	assert.Nil(t, err)
+
+	return response, responseBody
+}
+
+func downloadDataExport(t *testing.T, token string, id string) (*http.Response, []byte) {
+	request := httptest.NewRequest(http.MethodGet, "/api/users/_current/exports/"+id+"/download", nil)
+	request.Header.Set("Authorization", token)
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+
+	bytes, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	return response, bytes
+}
+
+// dataExportUseCase builds the use case the worker builds exports with
+func dataExportUseCase() *usecase.DataExportUseCase {
+	return usecase.NewDataExportUseCase(db, log, validate, repository.NewUserRepository(log),
+		repository.NewSessionRepository(log), repository.NewContactRepository(log), repository.NewAuditLogRepository(log),
+		repository.NewDataExportRepository(log), notifier, time.Hour, "http://localhost:8080/api/users/_current/exports")
+}
@@ -0,0 +1,134 @@
+package test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-clean-template/internal/entity"
+	"go-clean-template/internal/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyEmail(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+
+	token := GetNotifiedToken(t, "achieva@example.com")
+
+	response := verifyEmail(t, token)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	user := new(entity.User)
+	err := db.Where("id = ?", "achieva").First(user).Error
+	assert.Nil(t, err)
+	assert.NotZero(t, user.EmailVerifiedAt)
+
+	// tokens are single use
+	response = verifyEmail(t, token)
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+}
+
+func TestVerifyEmailWrongToken(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+
+	response := verifyEmail(t, "wrong")
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+}
+
+func TestVerifyEmailExpiredToken(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+
+	token := GetNotifiedToken(t, "achieva@example.com")
+	err := db.Model(new(entity.EmailVerification)).Where("user_id = ?", "achieva").Update("expires_at", 1).Error
+	assert.Nil(t, err)
+
+	response := verifyEmail(t, token)
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+}
+
+func TestVerifyEmailChangedEmail(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+
+	token := GetNotifiedToken(t, "achieva@example.com")
+	err := db.Model(new(entity.User)).Where("id = ?", "achieva").Update("email", "other@example.com").Error
+	assert.Nil(t, err)
+
+	response := verifyEmail(t, token)
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+}
+
+func TestResendVerificationThrottled(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+
+	response := resendVerification(t, "achieva@example.com")
+	assert.Equal(t, http.StatusTooManyRequests, response.StatusCode)
+	assert.NotEmpty(t, response.Header.Get("Retry-After"))
+}
+
+func TestResendVerification(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+
+	first := GetNotifiedToken(t, "achieva@example.com")
+	err := db.Model(new(entity.EmailVerification)).Where("user_id = ?", "achieva").Update("created_at", 1).Error
+	assert.Nil(t, err)
+
+	response := resendVerification(t, "achieva@example.com")
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	second := GetNotifiedToken(t, "achieva@example.com")
+	assert.NotEqual(t, first, second)
+
+	// a resent link supersedes the previous one
+	response = verifyEmail(t, first)
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+
+	response = verifyEmail(t, second)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+}
+
+func TestResendVerificationUnknownEmail(t *testing.T) {
+	ClearAll()
+
+	response := resendVerification(t, "unknown@example.com")
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Nil(t, notifier.Last("unknown@example.com"))
+}
+
+func verifyEmail(t *testing.T, token string) *http.Response {
+	bodyJson, err := json.Marshal(model.VerifyEmailRequest{Token: token})
+	assert.Nil(t, err)
+
+	request := httptest.NewRequest(http.MethodPost, "/api/users/_verify-email", strings.NewReader(string(bodyJson)))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+	return response
+}
+
+func resendVerification(t *testing.T, email string) *http.Response {
+	bodyJson, err := json.Marshal(model.ResendVerificationRequest{Email: email})
+	assert.Nil(t, err)
+
+	request := httptest.NewRequest(http.MethodPost, "/api/users/_resend-verification", strings.NewReader(string(bodyJson)))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+
+	_, err = io.ReadAll(response.Body)
+	assert.Nil(t, err)
+	return response
+}
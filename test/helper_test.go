@@ -1,21 +1,139 @@
 package test
 
 import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"strconv"
+	"strings"
 	"testing"
 
 	"go-clean-template/internal/entity"
+	"go-clean-template/internal/model"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
 )
 
 func ClearAll() {
 	ClearAddresses()
 	ClearContact()
+	ClearSessions()
+	ClearDataExports()
+	ClearAuditLogs()
+	ClearApiKeys()
+	ClearOidc()
+	ClearPasswordResets()
+	ClearMagicLinks()
+	ClearPasskeys()
+	ClearEmailVerifications()
+	ClearMfa()
+	ClearLoginAttempts()
+	ClearUserRoles()
 	ClearUsers()
 }
 
+// ClearUserRoles removes granted roles and every role except the seeded ones
+func ClearUserRoles() {
+	if err := db.Where("user_id is not null").Delete(&entity.UserRole{}).Error; err != nil {
+		log.Fatalf("Failed clear user role data : %+v", err)
+	}
+	if err := db.Exec("delete from role_permissions where role_id <> ?", model.RoleAdmin).Error; err != nil {
+		log.Fatalf("Failed clear role permission data : %+v", err)
+	}
+	if err := db.Where("id <> ?", model.RoleAdmin).Delete(&entity.Role{}).Error; err != nil {
+		log.Fatalf("Failed clear role data : %+v", err)
+	}
+}
+
+func ClearLoginAttempts() {
+	err := db.Where("key is not null").Delete(&entity.LoginAttempt{}).Error
+	if err != nil {
+		log.Fatalf("Failed clear login attempt data : %+v", err)
+	}
+}
+
+func ClearMfa() {
+	if err := db.Where("id is not null").Delete(&entity.MfaChallenge{}).Error; err != nil {
+		log.Fatalf("Failed clear mfa challenge data : %+v", err)
+	}
+	if err := db.Where("id is not null").Delete(&entity.RecoveryCode{}).Error; err != nil {
+		log.Fatalf("Failed clear recovery code data : %+v", err)
+	}
+	if err := db.Where("user_id is not null").Delete(&entity.UserTotp{}).Error; err != nil {
+		log.Fatalf("Failed clear totp data : %+v", err)
+	}
+}
+
+func ClearEmailVerifications() {
+	err := db.Where("id is not null").Delete(&entity.EmailVerification{}).Error
+	if err != nil {
+		log.Fatalf("Failed clear email verification data : %+v", err)
+	}
+}
+
+func ClearPasswordResets() {
+	err := db.Where("id is not null").Delete(&entity.PasswordReset{}).Error
+	if err != nil {
+		log.Fatalf("Failed clear password reset data : %+v", err)
+	}
+}
+
+func ClearMagicLinks() {
+	err := db.Where("id is not null").Delete(&entity.MagicLink{}).Error
+	if err != nil {
+		log.Fatalf("Failed clear magic link data : %+v", err)
+	}
+}
+
+func ClearPasskeys() {
+	if err := db.Where("id is not null").Delete(&entity.Passkey{}).Error; err != nil {
+		log.Fatalf("Failed clear passkey data : %+v", err)
+	}
+	if err := db.Where("id is not null").Delete(&entity.WebAuthnChallenge{}).Error; err != nil {
+		log.Fatalf("Failed clear webauthn challenge data : %+v", err)
+	}
+}
+
+func ClearOidc() {
+	if err := db.Where("id is not null").Delete(&entity.UserIdentity{}).Error; err != nil {
+		log.Fatalf("Failed clear user identity data : %+v", err)
+	}
+	if err := db.Where("id is not null").Delete(&entity.OidcState{}).Error; err != nil {
+		log.Fatalf("Failed clear oidc state data : %+v", err)
+	}
+}
+
+func ClearDataExports() {
+	err := db.Where("id is not null").Delete(&entity.DataExport{}).Error
+	if err != nil {
+		log.Fatalf("Failed clear data export data : %+v", err)
+	}
+}
+
+func ClearAuditLogs() {
+	err := db.Where("id is not null").Delete(&entity.AuditLog{}).Error
+	if err != nil {
+		log.Fatalf("Failed clear audit log data : %+v", err)
+	}
+}
+
+func ClearApiKeys() {
+	err := db.Where("id is not null").Delete(&entity.ApiKey{}).Error
+	if err != nil {
+		log.Fatalf("Failed clear api key data : %+v", err)
+	}
+}
+
+func ClearSessions() {
+	err := db.Where("id is not null").Delete(&entity.Session{}).Error
+	if err != nil {
+		log.Fatalf("Failed clear session data : %+v", err)
+	}
+}
+
 func ClearUsers() {
 	err := db.Where("id is not null").Delete(&entity.User{}).Error
 	if err != nil {
@@ -54,6 +172,19 @@ func CreateContacts(user *entity.User, total int) {
 	}
 }
 
+func CreateNamedContact(t *testing.T, user *entity.User, firstName string, lastName string) *entity.Contact {
+	contact := &entity.Contact{
+		ID:        uuid.NewString(),
+		FirstName: firstName,
+		LastName:  lastName,
+		Email:     strings.ToLower(firstName+"."+lastName) + "@example.com",
+		UserId:    user.ID,
+	}
+	err := db.Create(contact).Error
+	assert.Nil(t, err)
+	return contact
+}
+
 func CreateAddresses(t *testing.T, contact *entity.Contact, total int) {
 	for i := 0; i < total; i++ {
 		address := &entity.Address{
@@ -90,3 +221,54 @@ func GetFirstAddress(t *testing.T, contact *entity.Contact) *entity.Address {
 	assert.Nil(t, err)
 	return address
 }
+
+func LoginUser(t *testing.T, id string, password string) *model.UserResponse {
+	requestBody := model.LoginUserRequest{
+		ID:       id,
+		Password: password,
+	}
+
+	bodyJson, err := json.Marshal(requestBody)
+	assert.Nil(t, err)
+
+	request := httptest.NewRequest(http.MethodPost, "/api/users/_login", strings.NewReader(string(bodyJson)))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+
+	bytes, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	responseBody := new(model.WebResponse[model.UserResponse])
+	err = json.Unmarshal(bytes, responseBody)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	return &responseBody.Data
+}
+
+func CreateUser(t *testing.T, id string, password string) *entity.User {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	assert.Nil(t, err)
+
+	user := &entity.User{
+		ID:       id,
+		Password: string(hash),
+		Name:     id,
+		Email:    id + "@example.com",
+	}
+	err = db.Create(user).Error
+	assert.Nil(t, err)
+	return user
+}
+
+func GrantRole(t *testing.T, userId string, roleId string) {
+	err := db.Create(&entity.UserRole{UserId: userId, RoleId: roleId}).Error
+	assert.Nil(t, err)
+}
+
+func GetToken(t *testing.T) string {
+	return LoginUser(t, "achieva", "rahasia").Token
+}
@@ -0,0 +1,181 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-clean-template/internal/entity"
+	"go-clean-template/internal/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImpersonateForbidden(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+	CreateUser(t, "gemilang", "rahasia")
+
+	response, _ := adminUserAction(t, GetToken(t), http.MethodPost, "/api/admin/users/gemilang/_impersonate")
+	assert.Equal(t, http.StatusForbidden, response.StatusCode)
+}
+
+func TestImpersonateSelf(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+	GrantRole(t, "achieva", model.RoleAdmin)
+
+	response, _ := adminUserAction(t, GetToken(t), http.MethodPost, "/api/admin/users/achieva/_impersonate")
+	assert.Equal(t, http.StatusConflict, response.StatusCode)
+}
+
+func TestImpersonateDisabledUser(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+	GrantRole(t, "achieva", model.RoleAdmin)
+	CreateUser(t, "gemilang", "rahasia")
+
+	response, responseBody := adminUserAction(t, GetToken(t), http.MethodPost, "/api/admin/users/gemilang/_impersonate")
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	token := responseBody.Data.Token
+
+	response, _ = adminUserAction(t, GetToken(t), http.MethodPost, "/api/admin/users/gemilang/_disable")
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	// the token handed out before is refused
+	response = impersonatedRequest(t, token, http.MethodGet, "/api/contacts", "")
+	assert.Equal(t, http.StatusForbidden, response.StatusCode)
+
+	// and no new one is issued
+	response, _ = adminUserAction(t, GetToken(t), http.MethodPost, "/api/admin/users/gemilang/_impersonate")
+	assert.Equal(t, http.StatusForbidden, response.StatusCode)
+}
+
+func TestImpersonate(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+	GrantRole(t, "achieva", model.RoleAdmin)
+	CreateUser(t, "gemilang", "rahasia")
+
+	response, responseBody := adminUserAction(t, GetToken(t), http.MethodPost, "/api/admin/users/gemilang/_impersonate")
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, "gemilang", responseBody.Data.ID)
+	assert.NotEmpty(t, responseBody.Data.Token)
+	assert.Empty(t, responseBody.Data.RefreshToken)
+	assert.NotZero(t, responseBody.Data.ExpiresAt)
+	token := responseBody.Data.Token
+
+	claims, err := tokenProvider.ParseAccessToken(token)
+	assert.Nil(t, err)
+	assert.Equal(t, "gemilang", claims.Subject)
+	assert.Equal(t, "achieva", claims.Actor.Subject)
+	assert.Empty(t, claims.SessionID)
+	assert.Empty(t, claims.Permissions)
+
+	// reads go through as the user
+	assert.Equal(t, "gemilang", currentUser(t, token).ID)
+	response = impersonatedRequest(t, token, http.MethodGet, "/api/contacts", "")
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	// the account itself can not be changed
+	response = impersonatedRequest(t, token, http.MethodPatch, "/api/users/_current", `{"name":"Impersonated"}`)
+	assert.Equal(t, http.StatusForbidden, response.StatusCode)
+	assert.Equal(t, "Not allowed while impersonating", errorMessage(t, response))
+	response = impersonatedRequest(t, token, http.MethodDelete, "/api/users/_current", `{"password":"rahasia"}`)
+	assert.Equal(t, http.StatusForbidden, response.StatusCode)
+	response = impersonatedRequest(t, token, http.MethodDelete, "/api/users", "")
+	assert.Equal(t, http.StatusForbidden, response.StatusCode)
+
+	// and neither can its data
+	response = impersonatedRequest(t, token, http.MethodPost, "/api/contacts", `{"first_name":"Impersonated"}`)
+	assert.Equal(t, http.StatusForbidden, response.StatusCode)
+	assert.Equal(t, "Not allowed while impersonating", errorMessage(t, response))
+
+	// the token carries no permissions of the admin
+	response = impersonatedRequest(t, token, http.MethodGet, "/api/admin/users", "")
+	assert.Equal(t, http.StatusForbidden, response.StatusCode)
+
+	user := new(entity.User)
+	err = db.Where("id = ?", "gemilang").Take(user).Error
+	assert.Nil(t, err)
+	assert.Equal(t, "gemilang", user.Name)
+	assert.Zero(t, user.DeleteAfter)
+
+	var contacts int64
+	err = db.Model(new(entity.Contact)).Where("user_id = ?", "gemilang").Count(&contacts).Error
+	assert.Nil(t, err)
+	assert.Zero(t, contacts)
+
+	var auditLogs []entity.AuditLog
+	err = db.Where("user_id = ?", "gemilang").Find(&auditLogs).Error
+	assert.Nil(t, err)
+	assert.Len(t, auditLogs, 8)
+	var started int
+	details := make([]string, 0, len(auditLogs))
+	for _, auditLog := range auditLogs {
+		assert.Equal(t, "achieva", auditLog.ActorId)
+		switch auditLog.Action {
+		case model.AuditImpersonation:
+			started++
+		case model.AuditImpersonatedCall:
+			details = append(details, auditLog.Detail)
+		}
+	}
+	assert.Equal(t, 1, started)
+	assert.ElementsMatch(t, []string{
+		"GET /api/users/_current",
+		"GET /api/contacts",
+		"PATCH /api/users/_current",
+		"DELETE /api/users/_current",
+		"DELETE /api/users",
+		"POST /api/contacts",
+		"GET /api/admin/users",
+	}, details)
+}
+
+func TestImpersonateDataExport(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+	GrantRole(t, "achieva", model.RoleAdmin)
+	CreateUser(t, "gemilang", "rahasia")
+
+	_, created := createDataExport(t, LoginUser(t, "gemilang", "rahasia").Token)
+	_, err := dataExportUseCase().Build(context.Background())
+	assert.Nil(t, err)
+
+	_, responseBody := adminUserAction(t, GetToken(t), http.MethodPost, "/api/admin/users/gemilang/_impersonate")
+	token := responseBody.Data.Token
+
+	// the export holds every session and the whole audit log, so it stays with the owner even for reads
+	for _, path := range []string{
+		"/api/users/_current/exports/" + created.Data.ID,
+		"/api/users/_current/exports/" + created.Data.ID + "/download",
+	} {
+		response := impersonatedRequest(t, token, http.MethodGet, path, "")
+		assert.Equal(t, http.StatusForbidden, response.StatusCode, path)
+		assert.Equal(t, "Not allowed while impersonating", errorMessage(t, response))
+	}
+
+	response := impersonatedRequest(t, token, http.MethodPost, "/api/users/_current/export", "")
+	assert.Equal(t, http.StatusForbidden, response.StatusCode)
+
+	var exports int64
+	err = db.Model(new(entity.DataExport)).Where("user_id = ?", "gemilang").Count(&exports).Error
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), exports)
+}
+
+func impersonatedRequest(t *testing.T, token string, method string, path string, body string) *http.Response {
+	request := httptest.NewRequest(method, path, strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", token)
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+	assert.Empty(t, response.Header.Get("X-Access-Token"))
+
+	return response
+}
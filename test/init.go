@@ -2,6 +2,7 @@ package test
 
 import (
 	"go-clean-template/internal/config"
+	"go-clean-template/internal/security"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
@@ -20,13 +21,52 @@ var log *zap.SugaredLogger
 
 var validate *validator.Validate
 
+var tokenProvider *security.TokenProvider
+
+var passwordHasher security.PasswordHasher
+
+var tokenHasher *security.TokenHasher
+
+var notifier = &TestNotifier{}
+
+var oidcIssuer = NewTestIssuer("go-clean-template", "oidc-client-secret")
+
 func init() {
 	viperConfig = config.NewViper()
+	viperConfig.Set("jwt.secret", "test-jwt-signing-key-of-at-least-32-chars")
+	viperConfig.Set("mfa.encryption_key", "dGVzdC1tZmEtZW5jcnlwdGlvbi1rZXktMzItYnl0ZXM=")
+	viperConfig.Set("oidc.enabled", true)
+	viperConfig.Set("oidc.issuer", oidcIssuer.URL)
+	viperConfig.Set("oidc.client_id", oidcIssuer.ClientID)
+	viperConfig.Set("oidc.client_secret", oidcIssuer.ClientSecret)
+	viperConfig.Set("oidc.redirect_url", "http://localhost:8080/oidc/callback")
+	viperConfig.Set("oidc.timeout", 10)
+	viperConfig.Set("oidc.auto_provision", true)
+	viperConfig.Set("oidc.link_by_email", true)
+	viperConfig.Set("webauthn.enabled", true)
+	viperConfig.Set("webauthn.rp_id", authenticator.RPID)
+	viperConfig.Set("webauthn.origins", []string{authenticator.Origin})
+	viperConfig.Set("webauthn.challenge_ttl", 300)
+	viperConfig.Set("api_key.max_per_user", 3)
+	viperConfig.Set("account_deletion.reauth_window", 300)
+	// the fixtures use short passwords like "rahasia"
+	viperConfig.Set("password_policy.min_length", 7)
+	viperConfig.Set("password_policy.min_classes", 1)
+	viperConfig.Set("password_policy.reject_user_info", true)
+	viperConfig.Set("password_policy.breached_list", "breached_passwords.txt")
+	viperConfig.Set("account_deletion.grace_period", 3600)
+	viperConfig.Set("impersonation.ttl", 900)
+	viperConfig.Set("magic_link.ttl", 600)
+	viperConfig.Set("session.token_pepper", "test-session-token-pepper-of-32-chars")
+
 	log = config.NewLogger(viperConfig)
 	validate = config.NewValidator(viperConfig)
 	app = config.NewFiber(viperConfig)
 	db = config.NewDatabase(viperConfig, log)
 	producer := config.NewKafkaProducer(viperConfig, log)
+	tokenProvider = config.NewTokenProvider(viperConfig, log)
+	passwordHasher = config.NewPasswordHasher(viperConfig, log)
+	tokenHasher = config.NewTokenHasher(viperConfig, log)
 
 	config.Bootstrap(&config.BootstrapConfig{
 		DB:       db,
@@ -35,5 +75,6 @@ func init() {
 		Validate: validate,
 		Config:   viperConfig,
 		Producer: producer,
+		Notifier: notifier,
 	})
 }
@@ -0,0 +1,131 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"go-clean-template/internal/entity"
+	"go-clean-template/internal/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoginLockout(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+
+	threshold := viperConfig.GetInt("login_protection.account.threshold")
+	for i := 0; i < threshold; i++ {
+		response := login(t, "achieva", "salah")
+		assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+	}
+
+	// the right password is refused while locked out
+	response := login(t, "achieva", "rahasia")
+	assert.Equal(t, http.StatusLocked, response.StatusCode)
+	assert.Equal(t, "Account temporarily locked", errorMessage(t, response))
+
+	retryAfter, err := strconv.Atoi(response.Header.Get("Retry-After"))
+	assert.Nil(t, err)
+	assert.Greater(t, retryAfter, 0)
+
+	attempt := new(entity.LoginAttempt)
+	err = db.Where("key = ?", "user:achieva").Take(attempt).Error
+	assert.Nil(t, err)
+	assert.Equal(t, threshold, attempt.Failures)
+	assert.Greater(t, attempt.LockedUntil, time.Now().UnixMilli())
+}
+
+func TestLoginLockoutDoubles(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+
+	threshold := viperConfig.GetInt("login_protection.account.threshold")
+	for i := 0; i < threshold; i++ {
+		login(t, "achieva", "salah")
+	}
+
+	first := new(entity.LoginAttempt)
+	err := db.Where("key = ?", "user:achieva").Take(first).Error
+	assert.Nil(t, err)
+
+	// a failure right after the lockout ends locks the account for twice as long
+	err = db.Model(new(entity.LoginAttempt)).Where("key = ?", "user:achieva").Update("locked_until", 1).Error
+	assert.Nil(t, err)
+
+	response := login(t, "achieva", "salah")
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+
+	second := new(entity.LoginAttempt)
+	err = db.Where("key = ?", "user:achieva").Take(second).Error
+	assert.Nil(t, err)
+	assert.Greater(t, second.LockedUntil-second.LastFailureAt, first.LockedUntil-first.LastFailureAt)
+}
+
+func TestLoginLockoutExpires(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+
+	threshold := viperConfig.GetInt("login_protection.account.threshold")
+	for i := 0; i < threshold; i++ {
+		login(t, "achieva", "salah")
+	}
+
+	err := db.Model(new(entity.LoginAttempt)).Where("key = ?", "user:achieva").Update("locked_until", 1).Error
+	assert.Nil(t, err)
+
+	response := login(t, "achieva", "rahasia")
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	// a successful login forgets the failures
+	var total int64
+	err = db.Model(new(entity.LoginAttempt)).Where("key = ?", "user:achieva").Count(&total).Error
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), total)
+}
+
+func TestLoginLockoutUnknownAccount(t *testing.T) {
+	ClearAll()
+
+	threshold := viperConfig.GetInt("login_protection.account.threshold")
+	for i := 0; i < threshold; i++ {
+		login(t, "unknown", "salah")
+	}
+
+	// unknown accounts lock the same way so lockouts do not reveal which accounts exist
+	response := login(t, "unknown", "salah")
+	assert.Equal(t, http.StatusLocked, response.StatusCode)
+}
+
+func TestLoginIPThrottle(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+
+	threshold := viperConfig.GetInt("login_protection.ip.threshold")
+	for i := 0; i < threshold; i++ {
+		login(t, "unknown"+strconv.Itoa(i), "salah")
+	}
+
+	response := login(t, "achieva", "rahasia")
+	assert.Equal(t, http.StatusTooManyRequests, response.StatusCode)
+	assert.Equal(t, "Too many login attempts", errorMessage(t, response))
+	assert.NotEmpty(t, response.Header.Get("Retry-After"))
+}
+
+func login(t *testing.T, id string, password string) *http.Response {
+	bodyJson, err := json.Marshal(model.LoginUserRequest{ID: id, Password: password})
+	assert.Nil(t, err)
+
+	request := httptest.NewRequest(http.MethodPost, "/api/users/_login", strings.NewReader(string(bodyJson)))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+	return response
+}
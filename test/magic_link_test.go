@@ -0,0 +1,165 @@
+package test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-clean-template/internal/entity"
+	"go-clean-template/internal/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMagicLinkLogin(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+
+	response, responseBody := requestMagicLink(t, "achieva")
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.NotEmpty(t, responseBody.Data.DeviceToken)
+	assert.Greater(t, responseBody.Data.ExpiresAt, time.Now().UnixMilli())
+	token := GetNotifiedToken(t, "achieva@example.com")
+
+	response, loginBody := loginMagicLink(t, token, responseBody.Data.DeviceToken)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.NotEmpty(t, loginBody.Data.Token)
+	assert.NotEmpty(t, loginBody.Data.RefreshToken)
+	assert.Equal(t, "achieva", currentUser(t, loginBody.Data.Token).ID)
+
+	// delivering the link proved the email
+	user := new(entity.User)
+	err := db.Where("id = ?", "achieva").First(user).Error
+	assert.Nil(t, err)
+	assert.NotZero(t, user.EmailVerifiedAt)
+
+	// links are single-use
+	response, _ = loginMagicLink(t, token, responseBody.Data.DeviceToken)
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+}
+
+func TestMagicLinkOtherDevice(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+
+	_, requesting := requestMagicLink(t, "achieva")
+	token := GetNotifiedToken(t, "achieva@example.com")
+	_, other := requestMagicLink(t, "unknown")
+
+	response, _ := loginMagicLink(t, token, other.Data.DeviceToken)
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+
+	// the requesting device can still use it
+	response, _ = loginMagicLink(t, token, requesting.Data.DeviceToken)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+}
+
+func TestMagicLinkExpired(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+
+	_, responseBody := requestMagicLink(t, "achieva")
+	token := GetNotifiedToken(t, "achieva@example.com")
+
+	err := db.Model(new(entity.MagicLink)).Where("user_id = ?", "achieva").
+		Update("expires_at", time.Now().Add(-time.Minute).UnixMilli()).Error
+	assert.Nil(t, err)
+
+	response, _ := loginMagicLink(t, token, responseBody.Data.DeviceToken)
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+}
+
+func TestMagicLinkOutstanding(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+
+	_, first := requestMagicLink(t, "achieva")
+	firstToken := GetNotifiedToken(t, "achieva@example.com")
+	_, second := requestMagicLink(t, "achieva")
+	secondToken := GetNotifiedToken(t, "achieva@example.com")
+
+	// asking again does not void the link of another device
+	response, _ := loginMagicLink(t, secondToken, second.Data.DeviceToken)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	// but a login ends every link of the account
+	response, _ = loginMagicLink(t, firstToken, first.Data.DeviceToken)
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+}
+
+func TestMagicLinkThrottle(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+
+	// known and unknown accounts are throttled alike
+	threshold := viperConfig.GetInt("login_protection.account.threshold")
+	for _, id := range []string{"achieva", "unknown"} {
+		for i := 0; i < threshold; i++ {
+			response, _ := requestMagicLink(t, id)
+			assert.Equal(t, http.StatusOK, response.StatusCode)
+		}
+
+		response, _ := requestMagicLink(t, id)
+		assert.Equal(t, http.StatusTooManyRequests, response.StatusCode)
+		assert.NotEmpty(t, response.Header.Get("Retry-After"))
+	}
+
+	// the password login is not locked by it
+	response := login(t, "achieva", "rahasia")
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+}
+
+func TestMagicLinkUnknownUser(t *testing.T) {
+	ClearAll()
+
+	response, responseBody := requestMagicLink(t, "unknown")
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.NotEmpty(t, responseBody.Data.DeviceToken)
+	assert.Nil(t, notifier.Last("unknown"))
+}
+
+func requestMagicLink(t *testing.T, id string) (*http.Response, *model.WebResponse[model.MagicLinkResponse]) {
+	bodyJson, err := json.Marshal(model.RequestMagicLinkRequest{ID: id})
+	assert.Nil(t, err)
+
+	request := httptest.NewRequest(http.MethodPost, "/api/users/_magic-link", strings.NewReader(string(bodyJson)))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+
+	bytes, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	responseBody := new(model.WebResponse[model.MagicLinkResponse])
+	err = json.Unmarshal(bytes, responseBody)
+	assert.Nil(t, err)
+
+	return response, responseBody
+}
+
+func loginMagicLink(t *testing.T, token string, deviceToken string) (*http.Response, *model.WebResponse[model.UserResponse]) {
+	bodyJson, err := json.Marshal(model.LoginMagicLinkRequest{Token: token, DeviceToken: deviceToken})
+	assert.Nil(t, err)
+
+	request := httptest.NewRequest(http.MethodPost, "/api/users/_login/magic-link", strings.NewReader(string(bodyJson)))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+
+	bytes, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	responseBody := new(model.WebResponse[model.UserResponse])
+	err = json.Unmarshal(bytes, responseBody)
+	assert.Nil(t, err)
+
+	return response, responseBody
+}
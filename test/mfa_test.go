@@ -0,0 +1,319 @@
+package test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-clean-template/internal/entity"
+	"go-clean-template/internal/model"
+	"go-clean-template/internal/security"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnrollTotp(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+	token := GetToken(t)
+
+	response, responseBody := enrollTotp(t, token)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.NotEmpty(t, responseBody.Data.Secret)
+	assert.True(t, strings.HasPrefix(responseBody.Data.URI, "otpauth://totp/"))
+	assert.Contains(t, responseBody.Data.URI, "secret="+responseBody.Data.Secret)
+
+	// the secret is encrypted at rest
+	userTotp := new(entity.UserTotp)
+	err := db.Where("user_id = ?", "achieva").Take(userTotp).Error
+	assert.Nil(t, err)
+	assert.NotEqual(t, responseBody.Data.Secret, userTotp.Secret)
+	assert.Zero(t, userTotp.ConfirmedAt)
+
+	// enrollment alone does not change login
+	assert.NotEmpty(t, LoginUser(t, "achieva", "rahasia").Token)
+}
+
+func TestConfirmTotp(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+	token := GetToken(t)
+
+	_, enrollment := enrollTotp(t, token)
+
+	response, responseBody := confirmTotp(t, token, totpCode(t, enrollment.Data.Secret, 0))
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Len(t, responseBody.Data.Codes, 10)
+
+	// enrolling again is refused once enabled
+	response, _ = enrollTotp(t, token)
+	assert.Equal(t, http.StatusConflict, response.StatusCode)
+}
+
+func TestConfirmTotpWrongCode(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+	token := GetToken(t)
+
+	_, enrollment := enrollTotp(t, token)
+
+	response, _ := confirmTotp(t, token, wrongTotpCode(t, enrollment.Data.Secret))
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+}
+
+func TestLoginMfa(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+	secret, _ := EnableTotp(t, GetToken(t))
+
+	login := LoginUser(t, "achieva", "rahasia")
+	assert.True(t, login.MfaRequired)
+	assert.NotEmpty(t, login.MfaToken)
+	assert.Empty(t, login.Token)
+	assert.Empty(t, login.RefreshToken)
+
+	response, responseBody := loginMfa(t, login.MfaToken, totpCode(t, secret, 1))
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.NotEmpty(t, responseBody.Data.Token)
+	assert.NotEmpty(t, responseBody.Data.RefreshToken)
+
+	// the challenge can not be completed twice
+	response, _ = loginMfa(t, login.MfaToken, totpCode(t, secret, 1))
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+}
+
+func TestLoginMfaReplayedCode(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+	secret, _ := EnableTotp(t, GetToken(t))
+
+	// the code used to confirm enrollment is spent
+	login := LoginUser(t, "achieva", "rahasia")
+	request := loginMfaRequest(t, login.MfaToken, totpCode(t, secret, 0))
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+	assert.Equal(t, "Invalid two-factor code", errorMessage(t, response))
+}
+
+func TestLoginMfaRecoveryCode(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+	_, codes := EnableTotp(t, GetToken(t))
+
+	login := LoginUser(t, "achieva", "rahasia")
+	response, responseBody := loginMfa(t, login.MfaToken, strings.ToUpper(codes[0]))
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.NotEmpty(t, responseBody.Data.Token)
+
+	// recovery codes are single use
+	login = LoginUser(t, "achieva", "rahasia")
+	response, _ = loginMfa(t, login.MfaToken, codes[0])
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+}
+
+func TestLoginMfaTooManyAttempts(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+	secret, _ := EnableTotp(t, GetToken(t))
+
+	login := LoginUser(t, "achieva", "rahasia")
+	for i := 0; i < 5; i++ {
+		response, _ := loginMfa(t, login.MfaToken, wrongTotpCode(t, secret))
+		assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+	}
+
+	request := loginMfaRequest(t, login.MfaToken, totpCode(t, secret, 1))
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+	assert.Equal(t, "Two-factor challenge expired", errorMessage(t, response))
+}
+
+func TestDisableTotp(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+	token := GetToken(t)
+	_, codes := EnableTotp(t, token)
+
+	bodyJson, err := json.Marshal(model.DisableTotpRequest{Code: codes[0]})
+	assert.Nil(t, err)
+
+	request := httptest.NewRequest(http.MethodDelete, "/api/users/_current/mfa/totp", strings.NewReader(string(bodyJson)))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", token)
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	login := LoginUser(t, "achieva", "rahasia")
+	assert.False(t, login.MfaRequired)
+	assert.NotEmpty(t, login.Token)
+}
+
+func TestDisableTotpLockout(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+	token := GetToken(t)
+	secret, codes := EnableTotp(t, token)
+
+	threshold := viperConfig.GetInt("login_protection.account.threshold")
+	for i := 0; i < threshold; i++ {
+		response := disableTotp(t, token, wrongTotpCode(t, secret))
+		assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+	}
+
+	// wrong codes lock the account like wrong passwords, so a stolen access token can not guess the code
+	response := disableTotp(t, token, codes[0])
+	assert.Equal(t, http.StatusLocked, response.StatusCode)
+	assert.Equal(t, "Account temporarily locked", errorMessage(t, response))
+}
+
+func TestRegenerateRecoveryCodes(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+	token := GetToken(t)
+	_, codes := EnableTotp(t, token)
+
+	bodyJson, err := json.Marshal(model.RegenerateRecoveryCodesRequest{Code: codes[0]})
+	assert.Nil(t, err)
+
+	request := httptest.NewRequest(http.MethodPost, "/api/users/_current/mfa/recovery-codes", strings.NewReader(string(bodyJson)))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", token)
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+
+	bytes, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	responseBody := new(model.WebResponse[model.RecoveryCodesResponse])
+	err = json.Unmarshal(bytes, responseBody)
+	assert.Nil(t, err)
+
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Len(t, responseBody.Data.Codes, 10)
+
+	// the previous codes stop working
+	login := LoginUser(t, "achieva", "rahasia")
+	response, _ = loginMfa(t, login.MfaToken, codes[1])
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+}
+
+// EnableTotp enrolls and confirms TOTP with the code of the current time step,
+// logins have to use a later step since a code is accepted only once
+func EnableTotp(t *testing.T, token string) (string, []string) {
+	_, enrollment := enrollTotp(t, token)
+
+	response, responseBody := confirmTotp(t, token, totpCode(t, enrollment.Data.Secret, 0))
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	return enrollment.Data.Secret, responseBody.Data.Codes
+}
+
+func totpCode(t *testing.T, secret string, offset int64) string {
+	code, err := security.TOTPCode(secret, security.TOTPStep(time.Now())+offset)
+	assert.Nil(t, err)
+	return code
+}
+
+// wrongTotpCode returns a code that is not valid for any accepted time step
+func wrongTotpCode(t *testing.T, secret string) string {
+	valid := map[string]bool{}
+	for offset := int64(-2); offset <= 2; offset++ {
+		valid[totpCode(t, secret, offset)] = true
+	}
+	for _, code := range []string{"000000", "111111", "222222", "333333", "444444", "555555"} {
+		if !valid[code] {
+			return code
+		}
+	}
+	t.Fatal("no wrong totp code available")
+	return ""
+}
+
+func disableTotp(t *testing.T, token string, code string) *http.Response {
+	bodyJson, err := json.Marshal(model.DisableTotpRequest{Code: code})
+	assert.Nil(t, err)
+
+	request := httptest.NewRequest(http.MethodDelete, "/api/users/_current/mfa/totp", strings.NewReader(string(bodyJson)))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", token)
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+	return response
+}
+
+func enrollTotp(t *testing.T, token string) (*http.Response, *model.WebResponse[model.TotpEnrollmentResponse]) {
+	request := httptest.NewRequest(http.MethodPost, "/api/users/_current/mfa/totp", nil)
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", token)
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+
+	bytes, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	responseBody := new(model.WebResponse[model.TotpEnrollmentResponse])
+	err = json.Unmarshal(bytes, responseBody)
+	assert.Nil(t, err)
+
+	return response, responseBody
+}
+
+func confirmTotp(t *testing.T, token string, code string) (*http.Response, *model.WebResponse[model.RecoveryCodesResponse]) {
+	bodyJson, err := json.Marshal(model.ConfirmTotpRequest{Code: code})
+	assert.Nil(t, err)
+
+	request := httptest.NewRequest(http.MethodPost, "/api/users/_current/mfa/totp/_confirm", strings.NewReader(string(bodyJson)))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", token)
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+
+	bytes, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	responseBody := new(model.WebResponse[model.RecoveryCodesResponse])
+	err = json.Unmarshal(bytes, responseBody)
+	assert.Nil(t, err)
+
+	return response, responseBody
+}
+
+func loginMfaRequest(t *testing.T, mfaToken string, code string) *http.Request {
+	bodyJson, err := json.Marshal(model.LoginMfaUserRequest{MfaToken: mfaToken, Code: code})
+	assert.Nil(t, err)
+
+	request := httptest.NewRequest(http.MethodPost, "/api/users/_login/mfa", strings.NewReader(string(bodyJson)))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+	return request
+}
+
+func loginMfa(t *testing.T, mfaToken string, code string) (*http.Response, *model.WebResponse[model.UserResponse]) {
+	response, err := app.Test(loginMfaRequest(t, mfaToken, code))
+	assert.Nil(t, err)
+
+	bytes, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	responseBody := new(model.WebResponse[model.UserResponse])
+	err = json.Unmarshal(bytes, responseBody)
+	assert.Nil(t, err)
+
+	return response, responseBody
+}
@@ -0,0 +1,33 @@
+package test
+
+import (
+	"context"
+	"sync"
+
+	"go-clean-template/internal/model"
+)
+
+// TestNotifier keeps sent notifications in memory so tests can read tokens out of them
+type TestNotifier struct {
+	mu            sync.Mutex
+	Notifications []model.Notification
+}
+
+func (n *TestNotifier) Send(ctx context.Context, notification *model.Notification) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.Notifications = append(n.Notifications, *notification)
+	return nil
+}
+
+// Last returns the latest notification sent to the recipient
+func (n *TestNotifier) Last(to string) *model.Notification {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for i := len(n.Notifications) - 1; i >= 0; i-- {
+		if n.Notifications[i].To == to {
+			return &n.Notifications[i]
+		}
+	}
+	return nil
+}
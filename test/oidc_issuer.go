@@ -0,0 +1,156 @@
+package test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// TestIdentity is the account a user signs in with at the stand-in issuer
+type TestIdentity struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+type testAuthorization struct {
+	Identity      TestIdentity
+	Nonce         string
+	CodeChallenge string
+	RedirectURI   string
+}
+
+// TestIssuer is a stand-in OpenID Connect issuer serving discovery, keys and the token endpoint,
+// the browser part of the login is replaced by Authorize
+type TestIssuer struct {
+	URL          string
+	ClientID     string
+	ClientSecret string
+
+	mu             sync.Mutex
+	key            *rsa.PrivateKey
+	authorizations map[string]testAuthorization
+}
+
+func NewTestIssuer(clientId string, clientSecret string) *TestIssuer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+
+	issuer := &TestIssuer{
+		ClientID:       clientId,
+		ClientSecret:   clientSecret,
+		key:            key,
+		authorizations: map[string]testAuthorization{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", issuer.discovery)
+	mux.HandleFunc("GET /jwks", issuer.jwks)
+	mux.HandleFunc("POST /token", issuer.token)
+	issuer.URL = httptest.NewServer(mux).URL
+
+	return issuer
+}
+
+// Authorize plays the user signing in at the authorization URL and returns the code and state
+// the issuer would redirect back with
+func (i *TestIssuer) Authorize(authorizationURL string, identity TestIdentity) (string, string) {
+	parsed, err := url.Parse(authorizationURL)
+	if err != nil {
+		panic(err)
+	}
+	query := parsed.Query()
+
+	code := query.Get("state") + ".code"
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.authorizations[code] = testAuthorization{
+		Identity:      identity,
+		Nonce:         query.Get("nonce"),
+		CodeChallenge: query.Get("code_challenge"),
+		RedirectURI:   query.Get("redirect_uri"),
+	}
+
+	return code, query.Get("state")
+}
+
+func (i *TestIssuer) discovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, map[string]string{
+		"issuer":                 i.URL,
+		"authorization_endpoint": i.URL + "/authorize",
+		"token_endpoint":         i.URL + "/token",
+		"jwks_uri":               i.URL + "/jwks",
+	})
+}
+
+func (i *TestIssuer) jwks(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, map[string]any{
+		"keys": []map[string]string{{
+			"kid": "test",
+			"kty": "RSA",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(i.key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(i.key.E)).Bytes()),
+		}},
+	})
+}
+
+func (i *TestIssuer) token(w http.ResponseWriter, r *http.Request) {
+	clientId, clientSecret, ok := r.BasicAuth()
+	if !ok || clientId != i.ClientID || clientSecret != i.ClientSecret {
+		http.Error(w, "invalid_client", http.StatusUnauthorized)
+		return
+	}
+
+	i.mu.Lock()
+	authorization, found := i.authorizations[r.FormValue("code")]
+	delete(i.authorizations, r.FormValue("code"))
+	i.mu.Unlock()
+
+	sum := sha256.Sum256([]byte(r.FormValue("code_verifier")))
+	if !found || r.FormValue("redirect_uri") != authorization.RedirectURI ||
+		base64.RawURLEncoding.EncodeToString(sum[:]) != authorization.CodeChallenge {
+		http.Error(w, "invalid_grant", http.StatusBadRequest)
+		return
+	}
+
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":            i.URL,
+		"sub":            authorization.Identity.Subject,
+		"aud":            i.ClientID,
+		"iat":            now.Unix(),
+		"exp":            now.Add(time.Minute).Unix(),
+		"nonce":          authorization.Nonce,
+		"email":          authorization.Identity.Email,
+		"email_verified": authorization.Identity.EmailVerified,
+		"name":           authorization.Identity.Name,
+	})
+	token.Header["kid"] = "test"
+
+	idToken, err := token.SignedString(i.key)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, map[string]string{"access_token": "unused", "token_type": "Bearer", "id_token": idToken})
+}
+
+func writeJSON(w http.ResponseWriter, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(body)
+}
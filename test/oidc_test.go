@@ -0,0 +1,187 @@
+package test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-clean-template/internal/entity"
+	"go-clean-template/internal/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var ssoIdentity = TestIdentity{
+	Subject:       "sso-subject-1",
+	Email:         "sso@example.com",
+	EmailVerified: true,
+	Name:          "Sso User",
+}
+
+func TestOidcLoginProvisionsUser(t *testing.T) {
+	ClearAll()
+
+	code, state := oidcIssuer.Authorize(oidcAuthorize(t), ssoIdentity)
+	response, responseBody := oidcCallback(t, code, state)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.NotEmpty(t, responseBody.Data.Token)
+	assert.NotEmpty(t, responseBody.Data.RefreshToken)
+
+	current := currentUser(t, responseBody.Data.Token)
+	assert.Equal(t, "Sso User", current.Name)
+	assert.Equal(t, "sso@example.com", current.Email)
+	assert.NotZero(t, current.EmailVerifiedAt)
+
+	// the second login finds the linked user instead of creating another one
+	code, state = oidcIssuer.Authorize(oidcAuthorize(t), ssoIdentity)
+	response, responseBody = oidcCallback(t, code, state)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, current.ID, currentUser(t, responseBody.Data.Token).ID)
+
+	var total int64
+	err := db.Model(new(entity.UserIdentity)).Where("user_id = ?", current.ID).Count(&total).Error
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), total)
+}
+
+func TestOidcLoginLinksVerifiedEmail(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+
+	identity := TestIdentity{Subject: "sso-subject-2", Email: "achieva@example.com", EmailVerified: true}
+
+	// an unverified account does not get linked
+	code, state := oidcIssuer.Authorize(oidcAuthorize(t), identity)
+	response, _ := oidcCallback(t, code, state)
+	assert.Equal(t, http.StatusConflict, response.StatusCode)
+
+	err := db.Model(new(entity.User)).Where("id = ?", "achieva").Update("email_verified_at", time.Now().UnixMilli()).Error
+	assert.Nil(t, err)
+
+	code, state = oidcIssuer.Authorize(oidcAuthorize(t), identity)
+	response, responseBody := oidcCallback(t, code, state)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, "achieva", currentUser(t, responseBody.Data.Token).ID)
+}
+
+func TestOidcStateSingleUse(t *testing.T) {
+	ClearAll()
+
+	code, state := oidcIssuer.Authorize(oidcAuthorize(t), ssoIdentity)
+	response, _ := oidcCallback(t, code, state)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	response, _ = oidcCallback(t, code, state)
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+}
+
+func TestOidcInvalidCode(t *testing.T) {
+	ClearAll()
+
+	_, state := oidcIssuer.Authorize(oidcAuthorize(t), ssoIdentity)
+	response, _ := oidcCallback(t, "wrong", state)
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+
+	code, _ := oidcIssuer.Authorize(oidcAuthorize(t), ssoIdentity)
+	response, _ = oidcCallback(t, code, "wrong")
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+}
+
+func TestOidcLoginDisabledUser(t *testing.T) {
+	ClearAll()
+
+	code, state := oidcIssuer.Authorize(oidcAuthorize(t), ssoIdentity)
+	response, responseBody := oidcCallback(t, code, state)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	userId := currentUser(t, responseBody.Data.Token).ID
+	err := db.Model(new(entity.User)).Where("id = ?", userId).Update("disabled_at", time.Now().UnixMilli()).Error
+	assert.Nil(t, err)
+
+	code, state = oidcIssuer.Authorize(oidcAuthorize(t), ssoIdentity)
+	response, _ = oidcCallback(t, code, state)
+	assert.Equal(t, http.StatusForbidden, response.StatusCode)
+}
+
+func TestOidcLoginMfa(t *testing.T) {
+	ClearAll()
+
+	code, state := oidcIssuer.Authorize(oidcAuthorize(t), ssoIdentity)
+	response, responseBody := oidcCallback(t, code, state)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	secret, _ := EnableTotp(t, responseBody.Data.Token)
+
+	// a user with TOTP answers the challenge after the issuer, like after a password
+	code, state = oidcIssuer.Authorize(oidcAuthorize(t), ssoIdentity)
+	response, responseBody = oidcCallback(t, code, state)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.True(t, responseBody.Data.MfaRequired)
+	assert.Empty(t, responseBody.Data.Token)
+
+	response, mfaBody := loginMfa(t, responseBody.Data.MfaToken, totpCode(t, secret, 1))
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.NotEmpty(t, mfaBody.Data.Token)
+}
+
+func oidcAuthorize(t *testing.T) string {
+	request := httptest.NewRequest(http.MethodPost, "/api/users/_oidc/authorize", nil)
+	request.Header.Set("Accept", "application/json")
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	bytes, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	responseBody := new(model.WebResponse[model.OidcAuthorizationResponse])
+	err = json.Unmarshal(bytes, responseBody)
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(responseBody.Data.AuthorizationURL, oidcIssuer.URL+"/authorize?"))
+
+	return responseBody.Data.AuthorizationURL
+}
+
+func oidcCallback(t *testing.T, code string, state string) (*http.Response, *model.WebResponse[model.UserResponse]) {
+	bodyJson, err := json.Marshal(model.OidcCallbackRequest{Code: code, State: state})
+	assert.Nil(t, err)
+
+	request := httptest.NewRequest(http.MethodPost, "/api/users/_oidc/callback", strings.NewReader(string(bodyJson)))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+
+	bytes, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	responseBody := new(model.WebResponse[model.UserResponse])
+	err = json.Unmarshal(bytes, responseBody)
+	assert.Nil(t, err)
+
+	return response, responseBody
+}
+
+func currentUser(t *testing.T, token string) *model.UserResponse {
+	request := httptest.NewRequest(http.MethodGet, "/api/users/_current", nil)
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", token)
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	bytes, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	responseBody := new(model.WebResponse[model.UserResponse])
+	err = json.Unmarshal(bytes, responseBody)
+	assert.Nil(t, err)
+
+	return &responseBody.Data
+}
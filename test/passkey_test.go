@@ -0,0 +1,323 @@
+package test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-clean-template/internal/entity"
+	"go-clean-template/internal/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPasskeyLogin(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+	token := LoginUser(t, "achieva", "rahasia").Token
+
+	options := beginPasskeyRegistration(t, token)
+	assert.Equal(t, authenticator.RPID, options.RP.ID)
+	assert.Equal(t, "achieva", options.User.Name)
+	assert.Empty(t, options.ExcludeCredentials)
+
+	credential, passkey := authenticator.Create(options)
+	response, registered := registerPasskey(t, token, "Laptop", credential)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, "Laptop", registered.Data.Name)
+
+	// discoverable login, the passkey tells who the user is
+	response, loginBody := loginPasskey(t, authenticator.Get(beginPasskeyLogin(t, ""), passkey))
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.NotEmpty(t, loginBody.Data.Token)
+	assert.NotEmpty(t, loginBody.Data.RefreshToken)
+	assert.Equal(t, "achieva", currentUser(t, loginBody.Data.Token).ID)
+
+	passkeys := listPasskeys(t, token)
+	assert.Len(t, passkeys, 1)
+	assert.NotZero(t, passkeys[0].LastUsedAt)
+}
+
+func TestPasskeyLoginNamedUser(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+	token := LoginUser(t, "achieva", "rahasia").Token
+	passkey := addPasskey(t, token)
+
+	options := beginPasskeyLogin(t, "achieva")
+	assert.Len(t, options.AllowCredentials, 1)
+
+	response, _ := loginPasskey(t, authenticator.Get(options, passkey))
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	// a login started for another account does not accept the passkey
+	CreateUser(t, "other", "rahasia")
+	response, _ = loginPasskey(t, authenticator.Get(beginPasskeyLogin(t, "other"), passkey))
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+}
+
+func TestPasskeyMultiple(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+	token := LoginUser(t, "achieva", "rahasia").Token
+
+	laptop := addPasskey(t, token)
+	options := beginPasskeyRegistration(t, token)
+	assert.Len(t, options.ExcludeCredentials, 1)
+	credential, phone := authenticator.Create(options)
+	response, _ := registerPasskey(t, token, "", credential)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Len(t, listPasskeys(t, token), 2)
+
+	for _, passkey := range []*TestPasskey{laptop, phone} {
+		response, _ := loginPasskey(t, authenticator.Get(beginPasskeyLogin(t, ""), passkey))
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+	}
+}
+
+func TestPasskeyRegisterTwice(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+	token := LoginUser(t, "achieva", "rahasia").Token
+
+	options := beginPasskeyRegistration(t, token)
+	credential, _ := authenticator.Create(options)
+	response, _ := registerPasskey(t, token, "", credential)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	// the challenge is spent
+	response, _ = registerPasskey(t, token, "", credential)
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+}
+
+func TestPasskeyRegisterOtherUserChallenge(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+	token := LoginUser(t, "achieva", "rahasia").Token
+	CreateUser(t, "other", "rahasia")
+	otherToken := LoginUser(t, "other", "rahasia").Token
+
+	credential, _ := authenticator.Create(beginPasskeyRegistration(t, token))
+	response, _ := registerPasskey(t, otherToken, "", credential)
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+}
+
+func TestPasskeyLoginReplay(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+	passkey := addPasskey(t, LoginUser(t, "achieva", "rahasia").Token)
+
+	assertion := authenticator.Get(beginPasskeyLogin(t, ""), passkey)
+	response, _ := loginPasskey(t, assertion)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	response, _ = loginPasskey(t, assertion)
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+}
+
+func TestPasskeyLoginExpiredChallenge(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+	passkey := addPasskey(t, LoginUser(t, "achieva", "rahasia").Token)
+
+	options := beginPasskeyLogin(t, "")
+	err := db.Model(new(entity.WebAuthnChallenge)).Where("ceremony = ?", model.WebAuthnLogin).
+		Update("expires_at", time.Now().Add(-time.Minute).UnixMilli()).Error
+	assert.Nil(t, err)
+
+	response, _ := loginPasskey(t, authenticator.Get(options, passkey))
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+}
+
+func TestPasskeyLoginUnknownChallenge(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+	passkey := addPasskey(t, LoginUser(t, "achieva", "rahasia").Token)
+
+	options := &model.PasskeyRequestOptions{Challenge: "not-issued-by-the-server", RPID: authenticator.RPID}
+	response, _ := loginPasskey(t, authenticator.Get(options, passkey))
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+}
+
+func TestPasskeyLoginClonedAuthenticator(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+	passkey := addPasskey(t, LoginUser(t, "achieva", "rahasia").Token)
+
+	response, _ := loginPasskey(t, authenticator.Get(beginPasskeyLogin(t, ""), passkey))
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	// a copy of the key still at the counter of the first login
+	passkey.SignCount--
+	response, _ = loginPasskey(t, authenticator.Get(beginPasskeyLogin(t, ""), passkey))
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+}
+
+func TestPasskeyLoginDisabledUser(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+	passkey := addPasskey(t, LoginUser(t, "achieva", "rahasia").Token)
+
+	err := db.Model(new(entity.User)).Where("id = ?", "achieva").Update("disabled_at", time.Now().UnixMilli()).Error
+	assert.Nil(t, err)
+
+	response, _ := loginPasskey(t, authenticator.Get(beginPasskeyLogin(t, ""), passkey))
+	assert.Equal(t, http.StatusForbidden, response.StatusCode)
+}
+
+func TestPasskeyDelete(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+	token := LoginUser(t, "achieva", "rahasia").Token
+	passkey := addPasskey(t, token)
+	id := listPasskeys(t, token)[0].ID
+
+	request := httptest.NewRequest(http.MethodDelete, "/api/users/_current/passkeys/"+id, nil)
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", token)
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Empty(t, listPasskeys(t, token))
+
+	response, _ = loginPasskey(t, authenticator.Get(beginPasskeyLogin(t, ""), passkey))
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+
+	auditLog := new(entity.AuditLog)
+	err = db.Where("user_id = ? and action = ?", "achieva", model.AuditPasskeyRemoved).First(auditLog).Error
+	assert.Nil(t, err)
+}
+
+func TestPasskeyDeleteOtherUser(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+	token := LoginUser(t, "achieva", "rahasia").Token
+	addPasskey(t, token)
+	id := listPasskeys(t, token)[0].ID
+
+	CreateUser(t, "other", "rahasia")
+	request := httptest.NewRequest(http.MethodDelete, "/api/users/_current/passkeys/"+id, nil)
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", LoginUser(t, "other", "rahasia").Token)
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+	assert.Len(t, listPasskeys(t, token), 1)
+}
+
+// addPasskey registers a passkey on the authenticator for the user of the token
+func addPasskey(t *testing.T, token string) *TestPasskey {
+	credential, passkey := authenticator.Create(beginPasskeyRegistration(t, token))
+	response, _ := registerPasskey(t, token, "", credential)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	return passkey
+}
+
+func beginPasskeyRegistration(t *testing.T, token string) *model.PasskeyCreationOptions {
+	request := httptest.NewRequest(http.MethodPost, "/api/users/_current/passkeys/_begin", nil)
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", token)
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	bytes, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	responseBody := new(model.WebResponse[model.PasskeyCreationOptions])
+	err = json.Unmarshal(bytes, responseBody)
+	assert.Nil(t, err)
+
+	return &responseBody.Data
+}
+
+func registerPasskey(t *testing.T, token string, name string, credential *model.PasskeyAttestation) (*http.Response, *model.WebResponse[model.PasskeyResponse]) {
+	bodyJson, err := json.Marshal(model.FinishPasskeyRegistrationRequest{Name: name, Credential: *credential})
+	assert.Nil(t, err)
+
+	request := httptest.NewRequest(http.MethodPost, "/api/users/_current/passkeys", strings.NewReader(string(bodyJson)))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", token)
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+
+	bytes, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	responseBody := new(model.WebResponse[model.PasskeyResponse])
+	err = json.Unmarshal(bytes, responseBody)
+	assert.Nil(t, err)
+
+	return response, responseBody
+}
+
+func listPasskeys(t *testing.T, token string) []model.PasskeyResponse {
+	request := httptest.NewRequest(http.MethodGet, "/api/users/_current/passkeys", nil)
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", token)
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	bytes, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	responseBody := new(model.WebResponse[[]model.PasskeyResponse])
+	err = json.Unmarshal(bytes, responseBody)
+	assert.Nil(t, err)
+
+	return responseBody.Data
+}
+
+func beginPasskeyLogin(t *testing.T, id string) *model.PasskeyRequestOptions {
+	bodyJson, err := json.Marshal(model.BeginPasskeyLoginRequest{ID: id})
+	assert.Nil(t, err)
+
+	request := httptest.NewRequest(http.MethodPost, "/api/users/_login/passkey/_begin", strings.NewReader(string(bodyJson)))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	bytes, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	responseBody := new(model.WebResponse[model.PasskeyRequestOptions])
+	err = json.Unmarshal(bytes, responseBody)
+	assert.Nil(t, err)
+
+	return &responseBody.Data
+}
+
+func loginPasskey(t *testing.T, credential *model.PasskeyAssertion) (*http.Response, *model.WebResponse[model.UserResponse]) {
+	bodyJson, err := json.Marshal(model.LoginPasskeyRequest{Credential: *credential})
+	assert.Nil(t, err)
+
+	request := httptest.NewRequest(http.MethodPost, "/api/users/_login/passkey", strings.NewReader(string(bodyJson)))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+
+	bytes, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	responseBody := new(model.WebResponse[model.UserResponse])
+	err = json.Unmarshal(bytes, responseBody)
+	assert.Nil(t, err)
+
+	return response, responseBody
+}
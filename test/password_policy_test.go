@@ -0,0 +1,86 @@
+package test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-clean-template/internal/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterPasswordTooShort(t *testing.T) {
+	ClearAll()
+
+	response, responseBody := register(t, "achieva", "Achieva Gemilang", "rahas")
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	assert.Equal(t, []string{"must be at least 7 characters long"}, responseBody.Fields["password"])
+}
+
+func TestRegisterPasswordMatchesUserId(t *testing.T) {
+	ClearAll()
+
+	response, responseBody := register(t, "achieva", "Achieva Gemilang", "ACHIEVA")
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	assert.Equal(t, []string{"must not be your user id or name"}, responseBody.Fields["password"])
+}
+
+func TestRegisterBreachedPassword(t *testing.T) {
+	ClearAll()
+
+	response, responseBody := register(t, "achieva", "Achieva Gemilang", "password123")
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	assert.Equal(t, "Password does not meet the password policy", responseBody.Errors)
+	assert.Equal(t, []string{"has appeared in a data breach, choose another one"}, responseBody.Fields["password"])
+
+	total := new(int64)
+	err := db.Table("users").Where("id = ?", "achieva").Count(total).Error
+	assert.Nil(t, err)
+	assert.Zero(t, *total)
+}
+
+func TestResetPasswordBreachedPassword(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+	forgotPassword(t, "achieva")
+	token := GetNotifiedToken(t, "achieva@example.com")
+
+	response := resetPassword(t, token, "qwerty123")
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+
+	// the rejected password does not use up the token
+	response = resetPassword(t, token, "rahasiabaru")
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+}
+
+func register(t *testing.T, id string, name string, password string) (*http.Response, *model.ErrorResponse) {
+	requestBody := model.RegisterUserRequest{
+		ID:       id,
+		Password: password,
+		Name:     name,
+		Email:    id + "@example.com",
+	}
+
+	bodyJson, err := json.Marshal(requestBody)
+	assert.Nil(t, err)
+
+	request := httptest.NewRequest(http.MethodPost, "/api/users", strings.NewReader(string(bodyJson)))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+
+	bytes, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	responseBody := new(model.ErrorResponse)
+	err = json.Unmarshal(bytes, responseBody)
+	assert.Nil(t, err)
+
+	return response, responseBody
+}
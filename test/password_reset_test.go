@@ -0,0 +1,135 @@
+package test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+
+	"go-clean-template/internal/entity"
+	"go-clean-template/internal/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var notifiedTokenPattern = regexp.MustCompile(`token=(\S+)`)
+
+func TestForgotPassword(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+
+	response, responseBody := forgotPassword(t, "achieva")
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.True(t, responseBody.Data)
+
+	assert.NotEmpty(t, GetNotifiedToken(t, "achieva@example.com"))
+}
+
+func TestForgotPasswordUnknownUser(t *testing.T) {
+	ClearAll()
+
+	response, responseBody := forgotPassword(t, "unknown")
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.True(t, responseBody.Data)
+	assert.Nil(t, notifier.Last("unknown"))
+}
+
+func TestResetPassword(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+	login := LoginUser(t, "achieva", "rahasia")
+	forgotPassword(t, "achieva")
+	token := GetNotifiedToken(t, "achieva@example.com")
+
+	response := resetPassword(t, token, "rahasiabaru")
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	user := new(entity.User)
+	err := db.Where("id = ?", "achieva").First(user).Error
+	assert.Nil(t, err)
+	matched, err := passwordHasher.Verify(user.Password, "rahasiabaru")
+	assert.Nil(t, err)
+	assert.True(t, matched)
+
+	// existing sessions are logged out
+	response, _ = refresh(t, login.RefreshToken)
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+
+	// and the token can only be used once
+	response = resetPassword(t, token, "rahasialagi")
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+}
+
+func TestResetPasswordSupersededToken(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+	forgotPassword(t, "achieva")
+	first := GetNotifiedToken(t, "achieva@example.com")
+	forgotPassword(t, "achieva")
+
+	response := resetPassword(t, first, "rahasiabaru")
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+}
+
+func TestResetPasswordExpiredToken(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+	forgotPassword(t, "achieva")
+	token := GetNotifiedToken(t, "achieva@example.com")
+
+	err := db.Model(new(entity.PasswordReset)).Where("user_id = ?", "achieva").Update("expires_at", 1).Error
+	assert.Nil(t, err)
+
+	response := resetPassword(t, token, "rahasiabaru")
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+}
+
+func GetNotifiedToken(t *testing.T, to string) string {
+	notification := notifier.Last(to)
+	assert.NotNil(t, notification)
+
+	match := notifiedTokenPattern.FindStringSubmatch(notification.Body)
+	assert.Len(t, match, 2)
+
+	token, err := url.QueryUnescape(match[1])
+	assert.Nil(t, err)
+	return token
+}
+
+func forgotPassword(t *testing.T, id string) (*http.Response, *model.WebResponse[bool]) {
+	bodyJson, err := json.Marshal(model.ForgotPasswordRequest{ID: id})
+	assert.Nil(t, err)
+
+	request := httptest.NewRequest(http.MethodPost, "/api/users/_forgot-password", strings.NewReader(string(bodyJson)))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+
+	bytes, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	responseBody := new(model.WebResponse[bool])
+	err = json.Unmarshal(bytes, responseBody)
+	assert.Nil(t, err)
+
+	return response, responseBody
+}
+
+func resetPassword(t *testing.T, token string, password string) *http.Response {
+	bodyJson, err := json.Marshal(model.ResetPasswordRequest{Token: token, Password: password})
+	assert.Nil(t, err)
+
+	request := httptest.NewRequest(http.MethodPost, "/api/users/_reset-password", strings.NewReader(string(bodyJson)))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+	return response
+}
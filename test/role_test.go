@@ -0,0 +1,171 @@
+package test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-clean-template/internal/entity"
+	"go-clean-template/internal/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListRolesForbidden(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+
+	response, _ := listRoles(t, GetToken(t))
+	assert.Equal(t, http.StatusForbidden, response.StatusCode)
+}
+
+func TestListRoles(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+	GrantRole(t, "achieva", model.RoleAdmin)
+
+	response, responseBody := listRoles(t, GetToken(t))
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Len(t, responseBody.Data, 1)
+	assert.Equal(t, model.RoleAdmin, responseBody.Data[0].ID)
+	assert.ElementsMatch(t, []string{
+		model.PermissionUsersRead,
+		model.PermissionUsersWrite,
+		model.PermissionRolesRead,
+		model.PermissionRolesWrite,
+	}, responseBody.Data[0].Permissions)
+}
+
+func TestGrantRole(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+	GrantRole(t, "achieva", model.RoleAdmin)
+	CreateUser(t, "gemilang", "rahasia")
+
+	response := grantRole(t, GetToken(t), "gemilang", model.RoleAdmin)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	// the permissions arrive with the next token of the user
+	response, _ = listRoles(t, LoginUser(t, "gemilang", "rahasia").Token)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	request := httptest.NewRequest(http.MethodGet, "/api/admin/users/gemilang/roles", nil)
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", GetToken(t))
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+
+	bytes, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	responseBody := new(model.WebResponse[[]model.RoleResponse])
+	err = json.Unmarshal(bytes, responseBody)
+	assert.Nil(t, err)
+
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Len(t, responseBody.Data, 1)
+	assert.Equal(t, model.RoleAdmin, responseBody.Data[0].ID)
+}
+
+func TestGrantRoleNotFound(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+	GrantRole(t, "achieva", model.RoleAdmin)
+	CreateUser(t, "gemilang", "rahasia")
+
+	response := grantRole(t, GetToken(t), "gemilang", "unknown")
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+
+	response = grantRole(t, GetToken(t), "unknown", model.RoleAdmin)
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+}
+
+func TestGrantRoleEscalation(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+	CreateRole(t, "support", model.PermissionRolesRead, model.PermissionRolesWrite)
+	GrantRole(t, "achieva", "support")
+	CreateUser(t, "gemilang", "rahasia")
+
+	// roles:write alone does not allow handing out permissions the actor does not hold
+	response := grantRole(t, GetToken(t), "gemilang", model.RoleAdmin)
+	assert.Equal(t, http.StatusForbidden, response.StatusCode)
+
+	response = grantRole(t, GetToken(t), "gemilang", "support")
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+}
+
+func TestRevokeRole(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+	GrantRole(t, "achieva", model.RoleAdmin)
+	CreateUser(t, "gemilang", "rahasia")
+	GrantRole(t, "gemilang", model.RoleAdmin)
+	token := GetToken(t)
+
+	request := httptest.NewRequest(http.MethodDelete, "/api/admin/users/gemilang/roles/admin", nil)
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", token)
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	request = httptest.NewRequest(http.MethodDelete, "/api/admin/users/gemilang/roles/admin", nil)
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", token)
+
+	response, err = app.Test(request)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+
+	response, _ = listRoles(t, LoginUser(t, "gemilang", "rahasia").Token)
+	assert.Equal(t, http.StatusForbidden, response.StatusCode)
+}
+
+func listRoles(t *testing.T, token string) (*http.Response, *model.WebResponse[[]model.RoleResponse]) {
+	request := httptest.NewRequest(http.MethodGet, "/api/admin/roles", nil)
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", token)
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+
+	bytes, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	responseBody := new(model.WebResponse[[]model.RoleResponse])
+	err = json.Unmarshal(bytes, responseBody)
+	assert.Nil(t, err)
+
+	return response, responseBody
+}
+
+func grantRole(t *testing.T, token string, userId string, roleId string) *http.Response {
+	bodyJson, err := json.Marshal(model.GrantRoleRequest{RoleId: roleId})
+	assert.Nil(t, err)
+
+	request := httptest.NewRequest(http.MethodPost, "/api/admin/users/"+userId+"/roles", strings.NewReader(string(bodyJson)))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", token)
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+	return response
+}
+
+func CreateRole(t *testing.T, id string, permissions ...string) {
+	role := &entity.Role{ID: id, Name: id}
+	err := db.Create(role).Error
+	assert.Nil(t, err)
+
+	for _, permission := range permissions {
+		err = db.Exec("insert into role_permissions (role_id, permission_id) values (?, ?)", id, permission).Error
+		assert.Nil(t, err)
+	}
+}
@@ -0,0 +1,366 @@
+package test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-clean-template/internal/entity"
+	"go-clean-template/internal/model"
+	"go-clean-template/internal/security"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListSessions(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+	LoginUser(t, "achieva", "rahasia")
+	login := LoginUser(t, "achieva", "rahasia")
+
+	request := httptest.NewRequest(http.MethodGet, "/api/users/_current/sessions", nil)
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("User-Agent", "session-test")
+	request.Header.Set("Authorization", login.Token)
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+
+	bytes, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	responseBody := new(model.WebResponse[[]model.SessionResponse])
+	err = json.Unmarshal(bytes, responseBody)
+	assert.Nil(t, err)
+
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, 2, len(responseBody.Data))
+
+	current := 0
+	for _, session := range responseBody.Data {
+		if session.Current {
+			current++
+		}
+	}
+	assert.Equal(t, 1, current)
+}
+
+func TestRevokeSession(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+	other := LoginUser(t, "achieva", "rahasia")
+	login := LoginUser(t, "achieva", "rahasia")
+
+	sessions := listSessions(t, login.Token)
+	var otherId string
+	for _, session := range sessions {
+		if !session.Current {
+			otherId = session.ID
+		}
+	}
+
+	request := httptest.NewRequest(http.MethodDelete, "/api/users/_current/sessions/"+otherId, nil)
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", login.Token)
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	response, _ = refresh(t, other.RefreshToken)
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+
+	response, _ = refresh(t, login.RefreshToken)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+}
+
+func TestRevokeSessionNotFound(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+	login := LoginUser(t, "achieva", "rahasia")
+
+	request := httptest.NewRequest(http.MethodDelete, "/api/users/_current/sessions/0b6f2f0e-5d0c-4d8c-9e3c-54d7b8a1f0aa", nil)
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", login.Token)
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+}
+
+func TestRevokeAllSessions(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+	other := LoginUser(t, "achieva", "rahasia")
+	login := LoginUser(t, "achieva", "rahasia")
+
+	request := httptest.NewRequest(http.MethodDelete, "/api/users/_current/sessions", nil)
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", login.Token)
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	response, _ = refresh(t, other.RefreshToken)
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+
+	response, _ = refresh(t, login.RefreshToken)
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+}
+
+func TestLogoutKeepsOtherSessions(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+	other := LoginUser(t, "achieva", "rahasia")
+	login := LoginUser(t, "achieva", "rahasia")
+
+	request := httptest.NewRequest(http.MethodDelete, "/api/users", nil)
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", login.Token)
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	response, _ = refresh(t, login.RefreshToken)
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+
+	response, _ = refresh(t, other.RefreshToken)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+}
+
+func TestRefreshIdleSession(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+	login := LoginUser(t, "achieva", "rahasia")
+
+	err := db.Model(new(entity.Session)).Where("user_id = ?", "achieva").Update("last_seen_at", 1).Error
+	assert.Nil(t, err)
+
+	bodyJson, err := json.Marshal(model.RefreshUserRequest{RefreshToken: login.RefreshToken})
+	assert.Nil(t, err)
+
+	request := httptest.NewRequest(http.MethodPost, "/api/users/_refresh", strings.NewReader(string(bodyJson)))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+	assert.Equal(t, "Session expired", errorMessage(t, response))
+}
+
+func TestRefreshExpiredSession(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+	login := LoginUser(t, "achieva", "rahasia")
+
+	err := db.Model(new(entity.Session)).Where("user_id = ?", "achieva").Update("expires_at", 1).Error
+	assert.Nil(t, err)
+
+	bodyJson, err := json.Marshal(model.RefreshUserRequest{RefreshToken: login.RefreshToken})
+	assert.Nil(t, err)
+
+	request := httptest.NewRequest(http.MethodPost, "/api/users/_refresh", strings.NewReader(string(bodyJson)))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+	assert.Equal(t, "Session expired", errorMessage(t, response))
+}
+
+func TestExpiredAccessToken(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+	LoginUser(t, "achieva", "rahasia")
+	session := getFirstSession(t)
+
+	expired := *tokenProvider
+	expired.AccessTTL = -time.Minute
+	token, _, err := expired.GenerateAccessToken(session.UserId, session.ID, nil, nil)
+	assert.Nil(t, err)
+
+	request := httptest.NewRequest(http.MethodGet, "/api/users/_current", nil)
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", token)
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+	assert.Equal(t, "Access token expired", errorMessage(t, response))
+}
+
+func TestAccessTokenSlidingRenewal(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+	LoginUser(t, "achieva", "rahasia")
+	session := getFirstSession(t)
+
+	// a token close to its expiry is renewed on use
+	almostExpired := *tokenProvider
+	almostExpired.AccessTTL = 10 * time.Second
+	token, _, err := almostExpired.GenerateAccessToken(session.UserId, session.ID, nil, nil)
+	assert.Nil(t, err)
+
+	request := httptest.NewRequest(http.MethodGet, "/api/users/_current", nil)
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", token)
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	renewed := response.Header.Get("X-Access-Token")
+	assert.NotEmpty(t, renewed)
+
+	claims, err := tokenProvider.ParseAccessToken(renewed)
+	assert.Nil(t, err)
+	assert.Equal(t, session.ID, claims.SessionID)
+
+	// but not once its session is over
+	err = db.Model(new(entity.Session)).Where("id = ?", session.ID).Update("revoked_at", 1).Error
+	assert.Nil(t, err)
+
+	request = httptest.NewRequest(http.MethodGet, "/api/users/_current", nil)
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", token)
+
+	response, err = app.Test(request)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+	assert.Equal(t, "Session expired", errorMessage(t, response))
+}
+
+func TestLegacyToken(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+
+	token := "0cd85818-8720-4121-b8ae-c5dff37869e5"
+	err := db.Create(&entity.Session{
+		ID:         "6c7c6a4e-2f8a-4f6c-9d5e-0b7f6f3d2a10",
+		UserId:     "achieva",
+		TokenHash:  security.HashToken(token),
+		LastSeenAt: time.Now().UnixMilli(),
+		ExpiresAt:  time.Now().Add(time.Hour).UnixMilli(),
+	}).Error
+	assert.Nil(t, err)
+
+	request := httptest.NewRequest(http.MethodGet, "/api/users/_current", nil)
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", token)
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	// the plain hash is replaced by the peppered one on first use
+	assert.Equal(t, tokenHasher.Hash(token), getFirstSession(t).TokenHash)
+}
+
+func TestLegacyTokenRejectsRefreshToken(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+
+	// opaque refresh tokens of the first generation were carried over as sessions as well
+	token, err := security.GenerateOpaqueToken()
+	assert.Nil(t, err)
+	err = db.Create(&entity.Session{
+		ID:         "6c7c6a4e-2f8a-4f6c-9d5e-0b7f6f3d2a10",
+		UserId:     "achieva",
+		TokenHash:  tokenHasher.Hash(token),
+		LastSeenAt: time.Now().UnixMilli(),
+		ExpiresAt:  time.Now().Add(time.Hour).UnixMilli(),
+	}).Error
+	assert.Nil(t, err)
+
+	request := httptest.NewRequest(http.MethodGet, "/api/users/_current", nil)
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", token)
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+
+	// but they can still be exchanged for a new pair
+	response, _ = refresh(t, token)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+}
+
+func TestRefreshForgedTokenKeepsSession(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+	login := LoginUser(t, "achieva", "rahasia")
+	session := getFirstSession(t)
+
+	// a token that merely names the session is not a replay
+	response, _ := refresh(t, session.ID+".garbage")
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+	assert.Equal(t, int64(0), getFirstSession(t).RevokedAt)
+
+	response, _ = refresh(t, login.RefreshToken)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+}
+
+func TestRefreshUnpepperedSession(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+	login := LoginUser(t, "achieva", "rahasia")
+
+	// sessions stored before the pepper was configured hold the plain SHA-256 of their refresh token
+	session := getFirstSession(t)
+	assert.NotEqual(t, security.HashToken(login.RefreshToken), session.TokenHash)
+	err := db.Model(session).Update("token_hash", security.HashToken(login.RefreshToken)).Error
+	assert.Nil(t, err)
+
+	response, responseBody := refresh(t, login.RefreshToken)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, tokenHasher.Hash(responseBody.Data.RefreshToken), getFirstSession(t).TokenHash)
+
+	// and the rotated token still counts as reused
+	response, _ = refresh(t, login.RefreshToken)
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+}
+
+func getFirstSession(t *testing.T) *entity.Session {
+	session := new(entity.Session)
+	err := db.First(session).Error
+	assert.Nil(t, err)
+	return session
+}
+
+func errorMessage(t *testing.T, response *http.Response) string {
+	bytes, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	responseBody := new(model.ErrorResponse)
+	err = json.Unmarshal(bytes, responseBody)
+	assert.Nil(t, err)
+
+	return responseBody.Errors
+}
+
+func listSessions(t *testing.T, token string) []model.SessionResponse {
+	request := httptest.NewRequest(http.MethodGet, "/api/users/_current/sessions", nil)
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", token)
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	bytes, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	responseBody := new(model.WebResponse[[]model.SessionResponse])
+	err = json.Unmarshal(bytes, responseBody)
+	assert.Nil(t, err)
+
+	return responseBody.Data
+}
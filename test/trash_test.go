@@ -0,0 +1,289 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"go-clean-template/internal/entity"
+	"go-clean-template/internal/model"
+	"go-clean-template/internal/repository"
+	"go-clean-template/internal/usecase"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeleteContactMovesToTrash(t *testing.T) {
+	TestLogin(t)
+	user := GetFirstUser(t)
+	CreateContacts(user, 2)
+	contact := GetFirstContact(t, user)
+	token := GetToken(t)
+
+	response := requestWithToken(t, http.MethodDelete, "/api/contacts/"+contact.ID, token)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	response = requestWithToken(t, http.MethodGet, "/api/contacts/"+contact.ID, token)
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+
+	_, list := searchContacts(t, "")
+	assert.Equal(t, int64(1), list.Paging.TotalItem)
+	assert.NotContains(t, contactIds(list.Data), contact.ID)
+
+	response, trash := listTrashedContacts(t, token)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, int64(1), trash.Paging.TotalItem)
+	assert.Equal(t, contact.ID, trash.Data[0].ID)
+	assert.NotZero(t, trash.Data[0].DeletedAt)
+
+	// the row is kept until the purge
+	trashed := new(entity.Contact)
+	assert.Nil(t, db.Where("id = ?", contact.ID).Take(trashed).Error)
+	assert.NotZero(t, trashed.DeletedAt)
+	assert.Equal(t, contact.UpdatedAt, trashed.UpdatedAt)
+}
+
+func TestDeleteContactWithAddresses(t *testing.T) {
+	TestLogin(t)
+	user := GetFirstUser(t)
+	CreateContacts(user, 1)
+	contact := GetFirstContact(t, user)
+	CreateAddresses(t, contact, 2)
+
+	response := requestWithToken(t, http.MethodDelete, "/api/contacts/"+contact.ID, GetToken(t))
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	// the addresses go to the trash in the same transaction as their contact
+	trashed := new(entity.Contact)
+	assert.Nil(t, db.Where("id = ?", contact.ID).Take(trashed).Error)
+	var addresses []entity.Address
+	assert.Nil(t, db.Where("contact_id = ?", contact.ID).Find(&addresses).Error)
+	assert.Len(t, addresses, 2)
+	for _, address := range addresses {
+		assert.Equal(t, trashed.DeletedAt, address.DeletedAt)
+	}
+
+	// and are purged with it
+	expired := time.Now().Add(-2 * time.Hour).UnixMilli()
+	assert.Nil(t, db.Model(new(entity.Contact)).Where("id = ?", contact.ID).Update("deleted_at", expired).Error)
+	assert.Nil(t, db.Model(new(entity.Address)).Where("contact_id = ?", contact.ID).Update("deleted_at", expired).Error)
+
+	contacts, purged, err := trashUseCase().Purge(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), contacts)
+	assert.Equal(t, int64(2), purged)
+}
+
+func TestRestoreContact(t *testing.T) {
+	TestLogin(t)
+	user := GetFirstUser(t)
+	CreateContacts(user, 1)
+	contact := GetFirstContact(t, user)
+	CreateAddresses(t, contact, 2)
+	token := GetToken(t)
+
+	response := requestWithToken(t, http.MethodDelete, "/api/contacts/"+contact.ID, token)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	// addresses of a trashed contact are out of reach
+	response = requestWithToken(t, http.MethodGet, "/api/contacts/"+contact.ID+"/addresses", token)
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+
+	response = requestWithToken(t, http.MethodPost, "/api/contacts/"+contact.ID+"/_restore", token)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	restored := new(model.WebResponse[model.ContactResponse])
+	assert.Nil(t, readJSON(response, restored))
+	assert.Equal(t, contact.ID, restored.Data.ID)
+	assert.Zero(t, restored.Data.DeletedAt)
+
+	response = requestWithToken(t, http.MethodGet, "/api/contacts/"+contact.ID, token)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	response, addresses := listAddresses(t, token, contact.ID, "addresses")
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Len(t, addresses.Data, 2)
+
+	_, trash := listTrashedContacts(t, token)
+	assert.Zero(t, trash.Paging.TotalItem)
+}
+
+func TestRestoreContactNotTrashed(t *testing.T) {
+	TestLogin(t)
+	user := GetFirstUser(t)
+	CreateContacts(user, 1)
+	contact := GetFirstContact(t, user)
+	token := GetToken(t)
+
+	response := requestWithToken(t, http.MethodPost, "/api/contacts/"+contact.ID+"/_restore", token)
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+
+	response = requestWithToken(t, http.MethodPost, "/api/contacts/"+uuid.NewString()+"/_restore", token)
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+}
+
+func TestRestoreContactOtherUser(t *testing.T) {
+	TestLogin(t)
+	CreateUser(t, "gemilang", "rahasia")
+	other := &entity.User{ID: "gemilang"}
+	CreateContacts(other, 1)
+	contact := GetFirstContact(t, other)
+	assert.Nil(t, db.Model(contact).Update("deleted_at", time.Now().UnixMilli()).Error)
+
+	response := requestWithToken(t, http.MethodPost, "/api/contacts/"+contact.ID+"/_restore", GetToken(t))
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+
+	_, trash := listTrashedContacts(t, GetToken(t))
+	assert.Zero(t, trash.Paging.TotalItem)
+}
+
+func TestDeleteAddressMovesToTrash(t *testing.T) {
+	TestLogin(t)
+	user := GetFirstUser(t)
+	CreateContacts(user, 1)
+	contact := GetFirstContact(t, user)
+	CreateAddresses(t, contact, 2)
+	address := GetFirstAddress(t, contact)
+	token := GetToken(t)
+
+	response := requestWithToken(t, http.MethodDelete, "/api/contacts/"+contact.ID+"/addresses/"+address.ID, token)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	response = requestWithToken(t, http.MethodGet, "/api/contacts/"+contact.ID+"/addresses/"+address.ID, token)
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+
+	_, addresses := listAddresses(t, token, contact.ID, "addresses")
+	assert.Len(t, addresses.Data, 1)
+	assert.NotEqual(t, address.ID, addresses.Data[0].ID)
+
+	response, trash := listAddresses(t, token, contact.ID, "addresses/_trash")
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Len(t, trash.Data, 1)
+	assert.Equal(t, address.ID, trash.Data[0].ID)
+	assert.NotZero(t, trash.Data[0].DeletedAt)
+}
+
+func TestRestoreAddress(t *testing.T) {
+	TestLogin(t)
+	user := GetFirstUser(t)
+	CreateContacts(user, 1)
+	contact := GetFirstContact(t, user)
+	CreateAddresses(t, contact, 1)
+	address := GetFirstAddress(t, contact)
+	token := GetToken(t)
+
+	response := requestWithToken(t, http.MethodDelete, "/api/contacts/"+contact.ID+"/addresses/"+address.ID, token)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	response = requestWithToken(t, http.MethodPost, "/api/contacts/"+contact.ID+"/addresses/"+address.ID+"/_restore", token)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	restored := new(model.WebResponse[model.AddressResponse])
+	assert.Nil(t, readJSON(response, restored))
+	assert.Equal(t, address.ID, restored.Data.ID)
+	assert.Zero(t, restored.Data.DeletedAt)
+
+	response = requestWithToken(t, http.MethodGet, "/api/contacts/"+contact.ID+"/addresses/"+address.ID, token)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	// restoring twice finds nothing in the trash
+	response = requestWithToken(t, http.MethodPost, "/api/contacts/"+contact.ID+"/addresses/"+address.ID+"/_restore", token)
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+}
+
+func TestRestoreAddressOfTrashedContact(t *testing.T) {
+	TestLogin(t)
+	user := GetFirstUser(t)
+	CreateContacts(user, 1)
+	contact := GetFirstContact(t, user)
+	CreateAddresses(t, contact, 1)
+	address := GetFirstAddress(t, contact)
+	token := GetToken(t)
+
+	// the address was deleted on its own before the contact
+	assert.Nil(t, db.Model(address).Update("deleted_at", time.Now().Add(-time.Minute).UnixMilli()).Error)
+	response := requestWithToken(t, http.MethodDelete, "/api/contacts/"+contact.ID, token)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	response = requestWithToken(t, http.MethodPost, "/api/contacts/"+contact.ID+"/addresses/"+address.ID+"/_restore", token)
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+
+	// the address stays in the trash when its contact comes back
+	response = requestWithToken(t, http.MethodPost, "/api/contacts/"+contact.ID+"/_restore", token)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	_, trash := listAddresses(t, token, contact.ID, "addresses/_trash")
+	assert.Len(t, trash.Data, 1)
+}
+
+func TestPurgeTrash(t *testing.T) {
+	TestLogin(t)
+	user := GetFirstUser(t)
+	expired := time.Now().Add(-2 * time.Hour).UnixMilli()
+
+	// a contact trashed long ago goes with its addresses
+	purgedContact := CreateNamedContact(t, user, "Purged", "Contact")
+	CreateAddresses(t, purgedContact, 2)
+	assert.Nil(t, db.Model(purgedContact).Update("deleted_at", expired).Error)
+
+	// a recently trashed contact is kept
+	keptContact := CreateNamedContact(t, user, "Kept", "Contact")
+	CreateAddresses(t, keptContact, 2)
+	assert.Nil(t, db.Model(keptContact).Update("deleted_at", time.Now().UnixMilli()).Error)
+
+	// of a live contact only the address trashed long ago goes
+	liveContact := CreateNamedContact(t, user, "Live", "Contact")
+	CreateAddresses(t, liveContact, 2)
+	purgedAddress := GetFirstAddress(t, liveContact)
+	assert.Nil(t, db.Model(purgedAddress).Update("deleted_at", expired).Error)
+
+	contacts, addresses, err := trashUseCase().Purge(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), contacts)
+	assert.Equal(t, int64(3), addresses)
+
+	var total int64
+	assert.Nil(t, db.Model(new(entity.Contact)).Where("id = ?", purgedContact.ID).Count(&total).Error)
+	assert.Zero(t, total)
+	assert.Nil(t, db.Model(new(entity.Contact)).Count(&total).Error)
+	assert.Equal(t, int64(2), total)
+	assert.Nil(t, db.Model(new(entity.Address)).Where("contact_id = ?", keptContact.ID).Count(&total).Error)
+	assert.Equal(t, int64(2), total)
+	assert.Nil(t, db.Model(new(entity.Address)).Where("contact_id = ?", liveContact.ID).Count(&total).Error)
+	assert.Equal(t, int64(1), total)
+}
+
+func listTrashedContacts(t *testing.T, token string) (*http.Response, *model.PageResponse[model.ContactResponse]) {
+	response := requestWithToken(t, http.MethodGet, "/api/contacts/_trash", token)
+
+	responseBody := new(model.PageResponse[model.ContactResponse])
+	assert.Nil(t, readJSON(response, responseBody))
+	return response, responseBody
+}
+
+// listAddresses gets the addresses of the contact under path, the live ones or those in the trash
+func listAddresses(t *testing.T, token string, contactId string, path string) (*http.Response, *model.WebResponse[[]model.AddressResponse]) {
+	response := requestWithToken(t, http.MethodGet, "/api/contacts/"+contactId+"/"+path, token)
+
+	responseBody := new(model.WebResponse[[]model.AddressResponse])
+	assert.Nil(t, readJSON(response, responseBody))
+	return response, responseBody
+}
+
+func readJSON(response *http.Response, body any) error {
+	bytes, err := io.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(bytes, body)
+}
+
+// trashUseCase builds the use case the worker runs the purge with
+func trashUseCase() *usecase.ContactUseCase {
+	return usecase.NewContactUseCase(db, log, validate, repository.NewContactRepository(log),
+		repository.NewAddressRepository(log), repository.NewUserRepository(log), nil, nil, false, time.Hour)
+}
@@ -12,7 +12,6 @@ import (
 	"go-clean-template/internal/model"
 
 	"github.com/stretchr/testify/assert"
-	"golang.org/x/crypto/bcrypt"
 )
 
 func TestRegister(t *testing.T) {
@@ -21,6 +20,7 @@ func TestRegister(t *testing.T) {
 		ID:       "achieva",
 		Password: "rahasia",
 		Name:     "Achieva Gemilang",
+		Email:    "achieva@example.com",
 	}
 
 	bodyJson, err := json.Marshal(requestBody)
@@ -43,6 +43,8 @@ func TestRegister(t *testing.T) {
 	assert.Equal(t, http.StatusOK, response.StatusCode)
 	assert.Equal(t, requestBody.ID, responseBody.Data.ID)
 	assert.Equal(t, requestBody.Name, responseBody.Data.Name)
+	assert.Equal(t, requestBody.Email, responseBody.Data.Email)
+	assert.Zero(t, responseBody.Data.EmailVerifiedAt)
 	assert.NotNil(t, responseBody.Data.CreatedAt)
 	assert.NotNil(t, responseBody.Data.UpdatedAt)
 }
@@ -53,6 +55,7 @@ func TestRegisterError(t *testing.T) {
 		ID:       "",
 		Password: "",
 		Name:     "",
+		Email:    "",
 	}
 
 	bodyJson, err := json.Marshal(requestBody)
@@ -84,6 +87,7 @@ func TestRegisterDuplicate(t *testing.T) {
 		ID:       "achieva",
 		Password: "rahasia",
 		Name:     "Achieva Gemilang",
+		Email:    "achieva@example.com",
 	}
 
 	bodyJson, err := json.Marshal(requestBody)
@@ -107,6 +111,30 @@ func TestRegisterDuplicate(t *testing.T) {
 	assert.NotNil(t, responseBody.Errors)
 }
 
+func TestRegisterDuplicateEmail(t *testing.T) {
+	ClearAll()
+	TestRegister(t) // register success
+
+	requestBody := model.RegisterUserRequest{
+		ID:       "gemilang",
+		Password: "rahasia",
+		Name:     "Gemilang",
+		Email:    "ACHIEVA@example.com",
+	}
+
+	bodyJson, err := json.Marshal(requestBody)
+	assert.Nil(t, err)
+
+	request := httptest.NewRequest(http.MethodPost, "/api/users", strings.NewReader(string(bodyJson)))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+
+	assert.Equal(t, http.StatusConflict, response.StatusCode)
+}
+
 func TestLogin(t *testing.T) {
 	TestRegister(t) // register success
 
@@ -133,12 +161,115 @@ func TestLogin(t *testing.T) {
 	assert.Nil(t, err)
 
 	assert.Equal(t, http.StatusOK, response.StatusCode)
-	assert.NotNil(t, responseBody.Data.Token)
+	assert.NotEmpty(t, responseBody.Data.Token)
+	assert.NotEmpty(t, responseBody.Data.RefreshToken)
 
-	user := new(entity.User)
-	err = db.Where("id = ?", requestBody.ID).First(user).Error
+	session := new(entity.Session)
+	err = db.Where("token_hash = ?", tokenHasher.Hash(responseBody.Data.RefreshToken)).First(session).Error
+	assert.Nil(t, err)
+	assert.Equal(t, requestBody.ID, session.UserId)
+}
+
+func TestLoginRehashesBcrypt(t *testing.T) {
+	ClearAll()
+	user := CreateUser(t, "gemilang", "rahasia")
+	assert.True(t, strings.HasPrefix(user.Password, "$2a$"))
+
+	response := login(t, "gemilang", "rahasia")
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	user = new(entity.User)
+	err := db.Where("id = ?", "gemilang").First(user).Error
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(user.Password, "$argon2id$v=19$"))
+	assert.False(t, passwordHasher.NeedsRehash(user.Password))
+
+	// the upgraded hash keeps working
+	response = login(t, "gemilang", "rahasia")
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+}
+
+func TestLoginLongPassword(t *testing.T) {
+	ClearAll()
+	password := strings.Repeat("r", 100)
+
+	hash, err := passwordHasher.Hash(password)
+	assert.Nil(t, err)
+	err = db.Create(&entity.User{ID: "gemilang", Password: hash, Name: "gemilang"}).Error
+	assert.Nil(t, err)
+
+	// bcrypt ignored everything after 72 bytes, argon2id does not
+	response := login(t, "gemilang", password[:72])
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+
+	response = login(t, "gemilang", password)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+}
+
+func TestRefresh(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+	login := LoginUser(t, "achieva", "rahasia")
+
+	response, responseBody := refresh(t, login.RefreshToken)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.NotEmpty(t, responseBody.Data.Token)
+	assert.NotEmpty(t, responseBody.Data.RefreshToken)
+	assert.NotEqual(t, login.RefreshToken, responseBody.Data.RefreshToken)
+
+	request := httptest.NewRequest(http.MethodGet, "/api/users/_current", nil)
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", responseBody.Data.Token)
+
+	response, err := app.Test(request)
 	assert.Nil(t, err)
-	assert.Equal(t, user.Token, responseBody.Data.Token)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+}
+
+func TestRefreshReuseRevokesSession(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+	login := LoginUser(t, "achieva", "rahasia")
+
+	response, rotated := refresh(t, login.RefreshToken)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	// replaying the first refresh token is treated as theft
+	response, _ = refresh(t, login.RefreshToken)
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+
+	// so the session, including the token issued by the legitimate rotation, is revoked
+	response, _ = refresh(t, rotated.Data.RefreshToken)
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+}
+
+func TestRefreshWrongToken(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+
+	response, _ := refresh(t, "wrong")
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+}
+
+func refresh(t *testing.T, refreshToken string) (*http.Response, *model.WebResponse[model.UserResponse]) {
+	bodyJson, err := json.Marshal(model.RefreshUserRequest{RefreshToken: refreshToken})
+	assert.Nil(t, err)
+
+	request := httptest.NewRequest(http.MethodPost, "/api/users/_refresh", strings.NewReader(string(bodyJson)))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+
+	bytes, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	responseBody := new(model.WebResponse[model.UserResponse])
+	err = json.Unmarshal(bytes, responseBody)
+	assert.Nil(t, err)
+
+	return response, responseBody
 }
 
 func TestLoginWrongUsername(t *testing.T) {
@@ -212,7 +343,7 @@ func TestLogout(t *testing.T) {
 	request := httptest.NewRequest(http.MethodDelete, "/api/users", nil)
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", user.Token)
+	request.Header.Set("Authorization", GetToken(t))
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -262,7 +393,7 @@ func TestGetCurrentUser(t *testing.T) {
 	request := httptest.NewRequest(http.MethodGet, "/api/users/_current", nil)
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", user.Token)
+	request.Header.Set("Authorization", GetToken(t))
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -322,7 +453,7 @@ func TestUpdateUserName(t *testing.T) {
 	request := httptest.NewRequest(http.MethodPatch, "/api/users/_current", strings.NewReader(string(bodyJson)))
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", user.Token)
+	request.Header.Set("Authorization", GetToken(t))
 
 	response, err := app.Test(request)
 	assert.Nil(t, err)
@@ -341,47 +472,107 @@ func TestUpdateUserName(t *testing.T) {
 	assert.NotNil(t, responseBody.Data.UpdatedAt)
 }
 
-func TestUpdateUserPassword(t *testing.T) {
+func TestChangePassword(t *testing.T) {
 	ClearAll()
-	TestLogin(t) // login success
+	TestRegister(t)
+	current := LoginUser(t, "achieva", "rahasia")
+	other := LoginUser(t, "achieva", "rahasia")
+
+	response := changePassword(t, current.Token, "rahasia", "rahasialagi")
+	assert.Equal(t, http.StatusOK, response.StatusCode)
 
 	user := new(entity.User)
 	err := db.Where("id = ?", "achieva").First(user).Error
 	assert.Nil(t, err)
 
-	requestBody := model.UpdateUserRequest{
-		Password: "rahasialagi",
-	}
-
-	bodyJson, err := json.Marshal(requestBody)
+	matched, err := passwordHasher.Verify(user.Password, "rahasialagi")
 	assert.Nil(t, err)
+	assert.True(t, matched)
 
-	request := httptest.NewRequest(http.MethodPatch, "/api/users/_current", strings.NewReader(string(bodyJson)))
-	request.Header.Set("Content-Type", "application/json")
-	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Authorization", user.Token)
+	// only the session that changed the password stays logged in
+	response, _ = refresh(t, other.RefreshToken)
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+	response, _ = refresh(t, current.RefreshToken)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
 
-	response, err := app.Test(request)
-	assert.Nil(t, err)
+	response = login(t, "achieva", "rahasia")
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+	LoginUser(t, "achieva", "rahasialagi")
 
-	bytes, err := io.ReadAll(response.Body)
+	var total int64
+	err = db.Model(new(entity.AuditLog)).Where("user_id = ? AND action = ?", "achieva", model.AuditPasswordChanged).Count(&total).Error
 	assert.Nil(t, err)
+	assert.Equal(t, int64(1), total)
+}
 
-	responseBody := new(model.WebResponse[model.UserResponse])
-	err = json.Unmarshal(bytes, responseBody)
-	assert.Nil(t, err)
+func TestChangePasswordWrongCurrent(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+	other := LoginUser(t, "achieva", "rahasia")
+
+	response := changePassword(t, GetToken(t), "salah", "rahasialagi")
+	assert.Equal(t, http.StatusForbidden, response.StatusCode)
+	assert.Equal(t, "Password confirmation failed", errorMessage(t, response))
 
+	response, _ = refresh(t, other.RefreshToken)
 	assert.Equal(t, http.StatusOK, response.StatusCode)
-	assert.Equal(t, user.ID, responseBody.Data.ID)
-	assert.NotNil(t, responseBody.Data.CreatedAt)
-	assert.NotNil(t, responseBody.Data.UpdatedAt)
+	LoginUser(t, "achieva", "rahasia")
+}
 
-	user = new(entity.User)
-	err = db.Where("id = ?", "achieva").First(user).Error
+func TestChangePasswordLockout(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+	token := GetToken(t)
+
+	threshold := viperConfig.GetInt("login_protection.account.threshold")
+	for i := 0; i < threshold; i++ {
+		response := changePassword(t, token, "salah", "rahasialagi")
+		assert.Equal(t, http.StatusForbidden, response.StatusCode)
+	}
+
+	// wrong current passwords lock the account like failed logins, so a stolen access token can not guess it
+	response := changePassword(t, token, "rahasia", "rahasialagi")
+	assert.Equal(t, http.StatusLocked, response.StatusCode)
+	assert.Equal(t, "Account temporarily locked", errorMessage(t, response))
+	assert.NotEmpty(t, response.Header.Get("Retry-After"))
+
+	user := new(entity.User)
+	err := db.Where("id = ?", "achieva").First(user).Error
 	assert.Nil(t, err)
 
-	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(requestBody.Password))
+	matched, err := passwordHasher.Verify(user.Password, "rahasia")
 	assert.Nil(t, err)
+	assert.True(t, matched)
+}
+
+func TestChangePasswordWeak(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+
+	response := changePassword(t, GetToken(t), "rahasia", "achieva")
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	LoginUser(t, "achieva", "rahasia")
+}
+
+func TestUpdateUserPassword(t *testing.T) {
+	ClearAll()
+	TestRegister(t)
+
+	request := httptest.NewRequest(http.MethodPatch, "/api/users/_current", strings.NewReader(`{"name":"Achieva","password":"rahasialagi"}`))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", GetToken(t))
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+
+	// the password has its own endpoint, and a profile update carrying one changes nothing at all
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	assert.Equal(t, "Change the password at /api/users/_current/_change-password", errorMessage(t, response))
+
+	user := GetFirstUser(t)
+	assert.Equal(t, "Achieva Gemilang", user.Name)
+	LoginUser(t, "achieva", "rahasia")
 }
 
 func TestUpdateFailed(t *testing.T) {
@@ -389,7 +580,7 @@ func TestUpdateFailed(t *testing.T) {
 	TestLogin(t) // login success
 
 	requestBody := model.UpdateUserRequest{
-		Password: "rahasialagi",
+		Name: "Achieva Futura Gemilang",
 	}
 
 	bodyJson, err := json.Marshal(requestBody)
@@ -413,3 +604,23 @@ func TestUpdateFailed(t *testing.T) {
 	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
 	assert.NotNil(t, responseBody.Errors)
 }
+
+func changePassword(t *testing.T, token string, currentPassword string, password string) *http.Response {
+	requestBody := model.ChangePasswordRequest{
+		CurrentPassword: currentPassword,
+		Password:        password,
+	}
+
+	bodyJson, err := json.Marshal(requestBody)
+	assert.Nil(t, err)
+
+	request := httptest.NewRequest(http.MethodPost, "/api/users/_current/_change-password", strings.NewReader(string(bodyJson)))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Authorization", token)
+
+	response, err := app.Test(request)
+	assert.Nil(t, err)
+
+	return response
+}